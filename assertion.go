@@ -0,0 +1,547 @@
+package ultron
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wosai/ultron/v2/pkg/genproto"
+)
+
+type (
+	// HTTPAssertion 可序列化的断言，可以在测试计划中按Attacker名称声明
+	HTTPAssertion struct {
+		Type  string      `json:"type"`            // 断言类型，见AssertStatus等常量
+		Key   string      `json:"key,omitempty"`   // 响应头名称或JSONPath
+		Value interface{} `json:"value,omitempty"` // 期望值，含义由Type决定
+	}
+
+	// AssertedPlan Plan的可选实现，按Attacker名称声明断言，由HTTPAttacker在自身的校验函数之后执行
+	AssertedPlan interface {
+		Plan
+		GetAssertions() map[string][]*HTTPAssertion
+	}
+
+	// AssertionError 断言失败，以不满足的条件作为错误类别，不包含实际值
+	AssertionError struct {
+		Condition string // 不满足的条件，如 $.code != 0
+		Actual    string // 实际值，可以为空
+	}
+
+	// jsonPathSegment JSONPath的一级，isIndex为true时为数组下标
+	jsonPathSegment struct {
+		key     string
+		index   int
+		isIndex bool
+	}
+
+	// jsonBody 同一次Fire中的各个断言共享响应体的解析结果
+	jsonBody struct {
+		raw   []byte
+		value interface{}
+		err   error
+	}
+
+	// httpLatencyKey 响应对应的请求context中保存的请求耗时
+	httpLatencyKey struct{}
+)
+
+const (
+	AssertStatus      = "status"       // value为期望的状态码集合
+	AssertHeader      = "header"       // key为响应头名称，value为期望值
+	AssertHeaderMatch = "header_match" // key为响应头名称，value为正则表达式
+	AssertBodyMatch   = "body_match"   // value为正则表达式
+	AssertJSON        = "json"         // key为JSONPath，value为期望值
+	AssertJSONExists  = "json_exists"  // key为JSONPath
+	AssertJSONType    = "json_type"    // key为JSONPath，value为null、boolean、number、string、array、object之一
+	AssertLatency     = "latency"      // value为单次请求的延迟上限，如"200ms"
+)
+
+const (
+	keyJSONBody = "http_json_body"
+)
+
+var (
+	_ AssertedPlan = (*plan)(nil)
+
+	jsonTypes = map[string]struct{}{"null": {}, "boolean": {}, "number": {}, "string": {}, "array": {}, "object": {}}
+)
+
+func (e *AssertionError) Error() string {
+	if e.Actual == "" {
+		return "assert: " + e.Condition
+	}
+	return "assert: " + e.Condition + ", actual: " + e.Actual
+}
+
+// FailureKey 用于错误归类
+func (e *AssertionError) FailureKey() string {
+	return "assert: " + e.Condition
+}
+
+// ExpectStatus 状态码属于codes之一
+func ExpectStatus(codes ...int) HTTPCheckFunc {
+	return mustCheck(statusCheck(codes))
+}
+
+// ExpectHeader 响应头等于value
+func ExpectHeader(name, value string) HTTPCheckFunc {
+	return mustCheck(headerCheck(name, value))
+}
+
+// ExpectHeaderMatch 响应头匹配正则表达式
+func ExpectHeaderMatch(name, pattern string) HTTPCheckFunc {
+	return mustCheck(headerMatchCheck(name, pattern))
+}
+
+// ExpectBodyMatch 响应体匹配正则表达式
+func ExpectBodyMatch(pattern string) HTTPCheckFunc {
+	return mustCheck(bodyMatchCheck(pattern))
+}
+
+// ExpectJSON 响应体中path对应的值等于value，按JSON编码后的值比较
+func ExpectJSON(path string, value interface{}) HTTPCheckFunc {
+	return mustCheck(jsonCheck(path, value))
+}
+
+// ExpectJSONExists 响应体中存在path
+func ExpectJSONExists(path string) HTTPCheckFunc {
+	return mustCheck(jsonExistsCheck(path))
+}
+
+// ExpectJSONType 响应体中path对应的值为指定的JSON类型
+func ExpectJSONType(path, typ string) HTTPCheckFunc {
+	return mustCheck(jsonTypeCheck(path, typ))
+}
+
+// ExpectLatency 单次请求从发送到读完响应体的耗时不超过max
+func ExpectLatency(max time.Duration) HTTPCheckFunc {
+	return mustCheck(latencyCheck(max))
+}
+
+// NewHTTPChecks 将声明的断言转换为校验函数
+func NewHTTPChecks(assertions ...*HTTPAssertion) ([]HTTPCheckFunc, error) {
+	checks := make([]HTTPCheckFunc, len(assertions))
+	for i, a := range assertions {
+		check, err := a.compile()
+		if err != nil {
+			return nil, err
+		}
+		checks[i] = check
+	}
+	return checks, nil
+}
+
+func mustCheck(check HTTPCheckFunc, err error) HTTPCheckFunc {
+	if err != nil {
+		panic(err)
+	}
+	return check
+}
+
+func (a *HTTPAssertion) compile() (HTTPCheckFunc, error) {
+	if a == nil {
+		return nil, errors.New("assertion cannot be nil")
+	}
+	switch a.Type {
+	case AssertStatus:
+		codes, err := assertionInts(a.Value)
+		if err != nil {
+			return nil, err
+		}
+		return statusCheck(codes)
+	case AssertHeader, AssertHeaderMatch, AssertBodyMatch, AssertJSONType:
+		s, ok := a.Value.(string)
+		if !ok {
+			return nil, fmt.Errorf("value of %s assertion must be a string", a.Type)
+		}
+		switch a.Type {
+		case AssertHeader:
+			return headerCheck(a.Key, s)
+		case AssertHeaderMatch:
+			return headerMatchCheck(a.Key, s)
+		case AssertBodyMatch:
+			return bodyMatchCheck(s)
+		default:
+			return jsonTypeCheck(a.Key, s)
+		}
+	case AssertJSON:
+		return jsonCheck(a.Key, a.Value)
+	case AssertJSONExists:
+		return jsonExistsCheck(a.Key)
+	case AssertLatency:
+		d, err := assertionDuration(a.Value)
+		if err != nil {
+			return nil, err
+		}
+		return latencyCheck(d)
+	}
+	return nil, fmt.Errorf("unknown assertion type %s", a.Type)
+}
+
+// assertionInts 兼容Go中构造的[]int以及JSON解析出的[]interface{}
+func assertionInts(v interface{}) ([]int, error) {
+	switch vs := v.(type) {
+	case []int:
+		return vs, nil
+	case []interface{}:
+		ret := make([]int, len(vs))
+		for i, item := range vs {
+			f, ok := item.(float64)
+			if !ok || f != float64(int(f)) {
+				return nil, errors.New("value of status assertion must be an array of integers")
+			}
+			ret[i] = int(f)
+		}
+		return ret, nil
+	}
+	return nil, errors.New("value of status assertion must be an array of integers")
+}
+
+// assertionDuration 字符串按time.ParseDuration解析，数字的单位为纳秒
+func assertionDuration(v interface{}) (time.Duration, error) {
+	switch d := v.(type) {
+	case time.Duration:
+		return d, nil
+	case string:
+		return time.ParseDuration(d)
+	case float64:
+		return time.Duration(d), nil
+	case int:
+		return time.Duration(d), nil
+	}
+	return 0, errors.New("value of latency assertion must be a duration")
+}
+
+func statusCheck(codes []int) (HTTPCheckFunc, error) {
+	if len(codes) == 0 {
+		return nil, errors.New("expected status codes cannot be empty")
+	}
+	expected := make(map[int]struct{}, len(codes))
+	for _, code := range codes {
+		expected[code] = struct{}{}
+	}
+	condition := fmt.Sprintf("status not in %v", codes)
+	return func(_ context.Context, res *http.Response, _ []byte) error {
+		if _, ok := expected[res.StatusCode]; !ok {
+			return &AssertionError{Condition: condition, Actual: strconv.Itoa(res.StatusCode)}
+		}
+		return nil
+	}, nil
+}
+
+func headerCheck(name, value string) (HTTPCheckFunc, error) {
+	if name == "" {
+		return nil, errors.New("header name cannot be empty")
+	}
+	condition := fmt.Sprintf("header %s != %s", http.CanonicalHeaderKey(name), value)
+	return func(_ context.Context, res *http.Response, _ []byte) error {
+		if actual := res.Header.Get(name); actual != value {
+			return &AssertionError{Condition: condition, Actual: actual}
+		}
+		return nil
+	}, nil
+}
+
+func headerMatchCheck(name, pattern string) (HTTPCheckFunc, error) {
+	if name == "" {
+		return nil, errors.New("header name cannot be empty")
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	condition := fmt.Sprintf("header %s !~ %s", http.CanonicalHeaderKey(name), pattern)
+	return func(_ context.Context, res *http.Response, _ []byte) error {
+		if actual := res.Header.Get(name); !re.MatchString(actual) {
+			return &AssertionError{Condition: condition, Actual: actual}
+		}
+		return nil
+	}, nil
+}
+
+func bodyMatchCheck(pattern string) (HTTPCheckFunc, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	condition := "body !~ " + pattern
+	return func(_ context.Context, _ *http.Response, body []byte) error {
+		if !re.Match(body) {
+			return &AssertionError{Condition: condition}
+		}
+		return nil
+	}, nil
+}
+
+func jsonCheck(path string, value interface{}) (HTTPCheckFunc, error) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var expected interface{}
+	if err := json.Unmarshal(data, &expected); err != nil {
+		return nil, err
+	}
+	condition := fmt.Sprintf("%s != %s", path, data)
+	return func(ctx context.Context, _ *http.Response, body []byte) error {
+		actual, err := lookupJSONBody(ctx, body, segments)
+		if err != nil {
+			return &AssertionError{Condition: condition, Actual: err.Error()}
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			data, _ := json.Marshal(actual)
+			return &AssertionError{Condition: condition, Actual: string(data)}
+		}
+		return nil
+	}, nil
+}
+
+func jsonExistsCheck(path string) (HTTPCheckFunc, error) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+	condition := path + " not exists"
+	return func(ctx context.Context, _ *http.Response, body []byte) error {
+		if _, err := lookupJSONBody(ctx, body, segments); err != nil {
+			return &AssertionError{Condition: condition, Actual: err.Error()}
+		}
+		return nil
+	}, nil
+}
+
+func jsonTypeCheck(path, typ string) (HTTPCheckFunc, error) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := jsonTypes[typ]; !ok {
+		return nil, fmt.Errorf("unknown json type %s", typ)
+	}
+	condition := fmt.Sprintf("%s type != %s", path, typ)
+	return func(ctx context.Context, _ *http.Response, body []byte) error {
+		actual, err := lookupJSONBody(ctx, body, segments)
+		if err != nil {
+			return &AssertionError{Condition: condition, Actual: err.Error()}
+		}
+		if t := jsonTypeOf(actual); t != typ {
+			return &AssertionError{Condition: condition, Actual: t}
+		}
+		return nil
+	}, nil
+}
+
+func latencyCheck(max time.Duration) (HTTPCheckFunc, error) {
+	if max <= 0 {
+		return nil, errors.New("max latency must be positive")
+	}
+	condition := "latency > " + max.String()
+	return func(_ context.Context, res *http.Response, _ []byte) error {
+		if latency, ok := httpLatencyOf(res); ok && latency > max {
+			return &AssertionError{Condition: condition, Actual: latency.String()}
+		}
+		return nil
+	}, nil
+}
+
+// withHTTPLatency 将请求耗时保存在响应对应的请求中，校验函数不依赖ctx的具体类型即可读取
+func withHTTPLatency(res *http.Response, latency time.Duration) {
+	if res.Request != nil {
+		res.Request = res.Request.WithContext(context.WithValue(res.Request.Context(), httpLatencyKey{}, latency))
+	}
+}
+
+func httpLatencyOf(res *http.Response) (time.Duration, bool) {
+	if res == nil || res.Request == nil {
+		return 0, false
+	}
+	latency, ok := res.Request.Context().Value(httpLatencyKey{}).(time.Duration)
+	return latency, ok
+}
+
+// parseJSONPath 支持JSONPath的子集：$、.name、[n]、['name']
+func parseJSONPath(path string) ([]jsonPathSegment, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("json path %s must start with $", path)
+	}
+	var segments []jsonPathSegment
+	for rest := path[1:]; rest != ""; {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("bad json path %s", path)
+			}
+			segments = append(segments, jsonPathSegment{key: rest[:end]})
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("bad json path %s", path)
+			}
+			inner := rest[1:end]
+			rest = rest[end+1:]
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				segments = append(segments, jsonPathSegment{key: inner[1 : len(inner)-1]})
+				continue
+			}
+			index, err := strconv.Atoi(inner)
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("bad json path %s", path)
+			}
+			segments = append(segments, jsonPathSegment{index: index, isIndex: true})
+		default:
+			return nil, fmt.Errorf("bad json path %s", path)
+		}
+	}
+	return segments, nil
+}
+
+// lookupJSONBody 解析响应体并取出path对应的值，响应体的解析结果在同一次Fire中复用
+func lookupJSONBody(ctx context.Context, body []byte, segments []jsonPathSegment) (interface{}, error) {
+	v, _ := FromContext(ctx, keyJSONBody)
+	jb, ok := v.(*jsonBody)
+	if !ok || !sameBytes(jb.raw, body) {
+		jb = &jsonBody{raw: body}
+		jb.err = json.Unmarshal(body, &jb.value)
+		StoreInContext(ctx, keyJSONBody, jb)
+	}
+	if jb.err != nil {
+		return nil, errors.New("invalid json")
+	}
+
+	value := jb.value
+	for _, seg := range segments {
+		if seg.isIndex {
+			arr, ok := value.([]interface{})
+			if !ok || seg.index >= len(arr) {
+				return nil, errors.New("missing")
+			}
+			value = arr[seg.index]
+			continue
+		}
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("missing")
+		}
+		if value, ok = obj[seg.key]; !ok {
+			return nil, errors.New("missing")
+		}
+	}
+	return value, nil
+}
+
+// sameBytes 响应体相同时复用解析结果，TransactionAttacker中多个步骤共享存储空间
+func sameBytes(a, b []byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	return len(a) == 0 || &a[0] == &b[0] || bytes.Equal(a, b)
+}
+
+func jsonTypeOf(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return "unknown"
+}
+
+// checkAssertions Attacker名称不能为空，断言需要能够转换为校验函数
+func checkAssertions(assertions map[string][]*HTTPAssertion) error {
+	for name, list := range assertions {
+		if name == "" {
+			return errors.New("attacker name in assertions cannot be empty")
+		}
+		if _, err := NewHTTPChecks(list...); err != nil {
+			return fmt.Errorf("assertions of attacker %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// planAssertions 未实现AssertedPlan时返回nil
+func planAssertions(p Plan) map[string][]*HTTPAssertion {
+	if ap, ok := p.(AssertedPlan); ok {
+		return ap.GetAssertions()
+	}
+	return nil
+}
+
+// convertAssertions 按Attacker名称排序，期望值按JSON编码
+func convertAssertions(assertions map[string][]*HTTPAssertion) ([]*genproto.AssertionDTO, error) {
+	names := make([]string, 0, len(assertions))
+	for name := range assertions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var ret []*genproto.AssertionDTO
+	for _, name := range names {
+		for _, a := range assertions[name] {
+			value, err := json.Marshal(a.Value)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, &genproto.AssertionDTO{Attacker: name, Type: a.Type, Key: a.Key, Value: value})
+		}
+	}
+	return ret, nil
+}
+
+func newHTTPChecksFromDTO(dtos []*genproto.AssertionDTO) (map[string][]HTTPCheckFunc, error) {
+	if len(dtos) == 0 {
+		return nil, nil
+	}
+	ret := make(map[string][]HTTPCheckFunc)
+	for _, dto := range dtos {
+		a := &HTTPAssertion{Type: dto.GetType(), Key: dto.GetKey()}
+		if err := json.Unmarshal(dto.GetValue(), &a.Value); err != nil {
+			return nil, err
+		}
+		check, err := a.compile()
+		if err != nil {
+			return nil, fmt.Errorf("assertions of attacker %s: %w", dto.GetAttacker(), err)
+		}
+		ret[dto.GetAttacker()] = append(ret[dto.GetAttacker()], check)
+	}
+	return ret, nil
+}
+
+// planChecks 测试计划中为该Attacker声明的断言
+func planChecks(ctx context.Context, name string) []HTTPCheckFunc {
+	e, ok := executionFrom(ctx)
+	if !ok {
+		return nil
+	}
+	checks, _ := e.assertions.Load().(map[string][]HTTPCheckFunc)
+	return checks[name]
+}
@@ -0,0 +1,191 @@
+package ultron
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wosai/ultron/v2/pkg/genproto"
+	"github.com/wosai/ultron/v2/pkg/statistics"
+)
+
+func TestParseJSONPath(t *testing.T) {
+	segments, err := parseJSONPath("$")
+	assert.Nil(t, err)
+	assert.Empty(t, segments)
+
+	segments, err = parseJSONPath("$.data.items[1]['first name']")
+	assert.Nil(t, err)
+	assert.EqualValues(t, []jsonPathSegment{{key: "data"}, {key: "items"}, {index: 1, isIndex: true}, {key: "first name"}}, segments)
+
+	for _, path := range []string{"", "data", "$.", "$..a", "$[a]", "$[-1]", "$[0", "$a"} {
+		_, err = parseJSONPath(path)
+		assert.NotNil(t, err, path)
+	}
+}
+
+func TestHTTPChecks(t *testing.T) {
+	res := &http.Response{StatusCode: http.StatusOK, Header: http.Header{"Content-Type": []string{"application/json; charset=utf-8"}}, Request: &http.Request{}}
+	body := []byte(`{"code": 0, "data": {"items": [{"id": 1}, {"id": 2}], "name": null}}`)
+	ctx := newExecutorSharedContext(context.Background())
+	withHTTPLatency(res, 50*time.Millisecond)
+
+	cases := []struct {
+		check HTTPCheckFunc
+		key   string // 为空时断言通过
+	}{
+		{ExpectStatus(200, 201), ""},
+		{ExpectStatus(201, 204), "assert: status not in [201 204]"},
+		{ExpectHeader("content-type", "application/json; charset=utf-8"), ""},
+		{ExpectHeader("X-Request-Id", "1"), "assert: header X-Request-Id != 1"},
+		{ExpectHeaderMatch("Content-Type", "^application/json"), ""},
+		{ExpectHeaderMatch("Content-Type", "^text/"), "assert: header Content-Type !~ ^text/"},
+		{ExpectBodyMatch(`"code":\s*0`), ""},
+		{ExpectBodyMatch(`"code":\s*1`), `assert: body !~ "code":\s*1`},
+		{ExpectJSON("$.code", 0), ""},
+		{ExpectJSON("$.code", 1), "assert: $.code != 1"},
+		{ExpectJSON("$.data.items[1]", map[string]int{"id": 2}), ""},
+		{ExpectJSON("$.data.missing", "x"), `assert: $.data.missing != "x"`},
+		{ExpectJSONExists("$.data.name"), ""},
+		{ExpectJSONExists("$.data.items[2]"), "assert: $.data.items[2] not exists"},
+		{ExpectJSONType("$.data.items", "array"), ""},
+		{ExpectJSONType("$.data['name']", "null"), ""},
+		{ExpectJSONType("$.code", "string"), "assert: $.code type != string"},
+		{ExpectLatency(100 * time.Millisecond), ""},
+		{ExpectLatency(20 * time.Millisecond), "assert: latency > 20ms"},
+	}
+	for i, c := range cases {
+		err := c.check(ctx, res, body)
+		if c.key == "" {
+			assert.Nil(t, err, i)
+			continue
+		}
+		var ae *AssertionError
+		assert.True(t, errors.As(err, &ae), i)
+		assert.EqualValues(t, c.key, statistics.DefaultErrorClassifier(err), i)
+	}
+
+	err := ExpectJSON("$.code", 0)(ctx, res, []byte("not json"))
+	assert.EqualValues(t, "assert: $.code != 0, actual: invalid json", err.Error())
+	err = ExpectStatus(201)(ctx, res, body)
+	assert.EqualValues(t, "assert: status not in [201], actual: 200", err.Error())
+
+	assert.Panics(t, func() { ExpectStatus() })
+	assert.Panics(t, func() { ExpectHeaderMatch("X", "(") })
+	assert.Panics(t, func() { ExpectJSON("code", 0) })
+	assert.Panics(t, func() { ExpectJSONType("$.code", "integer") })
+	assert.Panics(t, func() { ExpectLatency(0) })
+}
+
+func TestHTTPAttacker_ExpectLatency(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	prepare := WithPrepareFunc(func(context.Context) (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, server.URL, nil)
+	})
+	assert.Nil(t, NewHTTPAttacker("fast", prepare, WithCheckFuncs(ExpectLatency(time.Second))).Fire(context.Background()))
+	err := NewHTTPAttacker("slow", prepare, WithCheckFuncs(ExpectLatency(time.Millisecond))).Fire(context.Background())
+	assert.EqualValues(t, "assert: latency > 1ms", statistics.DefaultErrorClassifier(err))
+
+	// 校验函数不依赖ctx的具体类型
+	slow := ExpectLatency(time.Millisecond)
+	wrapped := func(ctx context.Context, res *http.Response, body []byte) error {
+		ctx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		return slow(ctx, res, body)
+	}
+	err = NewHTTPAttacker("wrapped", prepare, WithCheckFuncs(wrapped)).Fire(context.Background())
+	assert.EqualValues(t, "assert: latency > 1ms", statistics.DefaultErrorClassifier(err))
+}
+
+func TestNewHTTPChecks(t *testing.T) {
+	var assertions []*HTTPAssertion
+	assert.Nil(t, json.Unmarshal([]byte(`[
+		{"type": "status", "value": [200]},
+		{"type": "header", "key": "X-Env", "value": "prod"},
+		{"type": "json", "key": "$.data", "value": {"ok": true}},
+		{"type": "json_exists", "key": "$.data.ok"},
+		{"type": "latency", "value": "1s"}
+	]`), &assertions))
+	checks, err := NewHTTPChecks(assertions...)
+	assert.Nil(t, err)
+	assert.Len(t, checks, 5)
+
+	res := &http.Response{StatusCode: http.StatusOK, Header: http.Header{"X-Env": []string{"prod"}}}
+	for _, check := range checks {
+		assert.Nil(t, check(context.Background(), res, []byte(`{"data": {"ok": true}}`)))
+	}
+
+	for _, a := range []*HTTPAssertion{
+		nil,
+		{Type: "unknown"},
+		{Type: AssertStatus, Value: []interface{}{200.5}},
+		{Type: AssertHeader, Value: "v"},
+		{Type: AssertBodyMatch, Value: 1},
+		{Type: AssertLatency, Value: "soon"},
+	} {
+		_, err := NewHTTPChecks(a)
+		assert.NotNil(t, err)
+	}
+}
+
+func TestPlan_SetAssertions(t *testing.T) {
+	p := NewPlan("assertions")
+	p.AddStages(BuildStage().WithAttackStrategy(&FixedConcurrentUsers{ConcurrentUsers: 1}).WithExitConditions(&UniversalExitConditions{Requests: 1}))
+	p.SetAssertions(map[string][]*HTTPAssertion{"": {{Type: AssertStatus, Value: []int{200}}}})
+	assert.NotNil(t, p.validateStages())
+
+	p.SetAssertions(map[string][]*HTTPAssertion{"pay": {{Type: AssertJSONType, Key: "$.code", Value: "integer"}}})
+	assert.NotNil(t, p.validateStages())
+
+	assertions := map[string][]*HTTPAssertion{"pay": {{Type: AssertStatus, Value: []int{200}}}}
+	p.SetAssertions(assertions)
+	assert.Nil(t, p.validateStages())
+	assert.EqualValues(t, assertions, planAssertions(p))
+}
+
+func TestHTTPAttacker_PlanAssertions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code": 1}`))
+	}))
+	defer server.Close()
+
+	attacker := NewHTTPAttacker("pay", WithPrepareFunc(func(context.Context) (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, server.URL, nil)
+	}))
+	exec := newExecution("plan", "slave")
+	ctx := newExecutorSharedContext(withExecution(context.Background(), exec))
+	assert.Nil(t, attacker.Fire(ctx))
+
+	dtos, err := convertAssertions(map[string][]*HTTPAssertion{
+		"pay":  {{Type: AssertStatus, Value: []int{200}}, {Type: AssertJSON, Key: "$.code", Value: 0}},
+		"read": {{Type: AssertStatus, Value: []int{404}}},
+	})
+	assert.Nil(t, err)
+	assert.EqualValues(t, []*genproto.AssertionDTO{
+		{Attacker: "pay", Type: AssertStatus, Value: []byte("[200]")},
+		{Attacker: "pay", Type: AssertJSON, Key: "$.code", Value: []byte("0")},
+		{Attacker: "read", Type: AssertStatus, Value: []byte("[404]")},
+	}, dtos)
+
+	assert.Nil(t, exec.setAssertions(dtos))
+	err = attacker.Fire(ctx)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "assert: $.code != 0", statistics.DefaultErrorClassifier(err))
+
+	// 自身的校验函数先于测试计划中的断言执行
+	attacker.Apply(WithCheckFuncs(ExpectJSON("$.code", 2)))
+	err = attacker.Fire(ctx)
+	assert.EqualValues(t, "assert: $.code != 2", statistics.DefaultErrorClassifier(err))
+
+	assert.NotNil(t, exec.setAssertions([]*genproto.AssertionDTO{{Attacker: "pay", Type: "unknown", Value: []byte("null")}}))
+	assert.Empty(t, planChecks(ctx, "pay"))
+}
@@ -2,12 +2,18 @@ package ultron
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
 	"net/url"
 	"time"
+	"unicode/utf8"
+
+	"github.com/wosai/ultron/v2/pkg/statistics"
+	"golang.org/x/net/http2"
 )
 
 type (
@@ -28,10 +34,29 @@ type (
 		name        string
 		prepareFunc HTTPPrepareFunc
 		checkFuncs  []HTTPCheckFunc
+		diagnostics bool // 请求失败时附加诊断信息
+		trace       bool // 统计各阶段的耗时
+		dialer      *httpDialer
+		connections ConnectionModel
+		cookieJar   bool // 每个虚拟用户独立的Cookie
 	}
 
 	// HTTPAttackerOption HTTPAttacker配置项
 	HTTPAttackerOption func(*HTTPAttacker)
+
+	// HTTPStatusError 响应状态码不符合预期
+	HTTPStatusError struct {
+		Code int
+	}
+
+	// HTTPProtocol HTTPAttacker使用的协议
+	HTTPProtocol int
+)
+
+const (
+	HTTPProtocolHTTP1 HTTPProtocol = iota // 默认，HTTP/1.1
+	HTTPProtocolHTTP2                     // 强制HTTP/2，仅支持https
+	HTTPProtocolH2C                       // 明文HTTP/2（h2c），仅支持http
 )
 
 const (
@@ -39,34 +64,40 @@ const (
 )
 
 var (
-	// defaultHTTPClient 默认http.Client
-	// http://tleyden.github.io/blog/2016/11/21/tuning-the-go-http-client-library-for-load-testing/
-	defaultHTTPClient = &http.Client{
-		Timeout: 45 * time.Second,
-		Transport: &http.Transport{
-			Proxy: nil,
-			DialContext: (&net.Dialer{
-				Timeout:   30 * time.Second,
-				KeepAlive: 30 * time.Second,
-				DualStack: true,
-			}).DialContext,
-			DisableKeepAlives:     false,
-			MaxIdleConns:          1000,
-			MaxIdleConnsPerHost:   1000,
-			IdleConnTimeout:       30 * time.Second,
-			TLSHandshakeTimeout:   10 * time.Second,
-			ExpectContinueTimeout: 1 * time.Second,
-		},
-	}
-
-	_ Attacker = (*HTTPAttacker)(nil)
+	_ Attacker        = (*HTTPAttacker)(nil)
+	_ VirtualUserHook = (*HTTPAttacker)(nil)
 )
 
+// newHTTPClient 每个HTTPAttacker独占的http.Client
+// http://tleyden.github.io/blog/2016/11/21/tuning-the-go-http-client-library-for-load-testing/
+func newHTTPClient(dialer *httpDialer) *http.Client {
+	return &http.Client{
+		Timeout:   45 * time.Second,
+		Transport: newHTTPTransport(dialer),
+	}
+}
+
+func newHTTPTransport(dialer *httpDialer) *http.Transport {
+	return &http.Transport{
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		DisableKeepAlives:     false,
+		MaxIdleConns:          1000,
+		MaxIdleConnsPerHost:   1000,
+		IdleConnTimeout:       30 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
 func NewHTTPAttacker(name string, opts ...HTTPAttackerOption) *HTTPAttacker {
+	dialer := newHTTPDialer()
 	attacker := &HTTPAttacker{
-		client:     defaultHTTPClient,
-		name:       name,
-		checkFuncs: make([]HTTPCheckFunc, 0),
+		client:      newHTTPClient(dialer),
+		dialer:      dialer,
+		name:        name,
+		checkFuncs:  make([]HTTPCheckFunc, 0),
+		diagnostics: true,
 	}
 	attacker.Apply(opts...)
 	return attacker
@@ -88,36 +119,126 @@ func (ha *HTTPAttacker) Fire(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	req = req.WithContext(ctx)
+	var tracer *httpTracer
+	if ha.trace {
+		tracer = newHTTPTracer()
+		req = req.WithContext(tracer.withContext(ctx))
+		defer func() { traceResult(ctx, tracer.trace()) }()
+	} else {
+		req = req.WithContext(ctx)
+	}
 	// change user agent
 	if req.Header.Get("User-Agent") == "" {
 		req.Header.Set("User-Agent", defaultUserAgent)
 	}
 
-	res, err := ha.client.Do(req)
+	start := time.Now()
+	res, err := ha.userClient(ctx).Do(req)
 	if err != nil {
-		return err
+		return ha.diagnose(err, req, nil, nil)
 	}
 
-	if len(ha.checkFuncs) == 0 {
+	checks := ha.checks(ctx)
+	if len(checks) == 0 {
 		io.Copy(io.Discard, res.Body) // no checker defined, discard body
+		tracer.bodyRead()
 		return res.Body.Close()
 	}
 	body, err := io.ReadAll(res.Body)
+	tracer.bodyRead()
+	withHTTPLatency(res, time.Since(start))
 	if err != nil {
-		return err
+		return ha.diagnose(err, req, res, nil)
 	}
 
 	res.Body.Close()
 
-	for _, check := range ha.checkFuncs {
+	for _, check := range checks {
 		if err = check(ctx, res, body); err != nil {
-			return err
+			return ha.diagnose(err, req, res, body)
 		}
 	}
 	return nil
 }
 
+// checks 自身的校验函数之后追加测试计划中声明的断言
+func (ha *HTTPAttacker) checks(ctx context.Context) []HTTPCheckFunc {
+	asserted := planChecks(ctx, ha.name)
+	if len(asserted) == 0 {
+		return ha.checkFuncs
+	}
+	return append(append(make([]HTTPCheckFunc, 0, len(ha.checkFuncs)+len(asserted)), ha.checkFuncs...), asserted...)
+}
+
+// diagnose 为失败的请求附加诊断信息，请求体只能通过GetBody获取
+func (ha *HTTPAttacker) diagnose(err error, req *http.Request, res *http.Response, body []byte) error {
+	if !ha.diagnostics {
+		return err
+	}
+	d := &statistics.Diagnostic{
+		Request:        fmt.Sprintf("%s %s %s", req.Method, req.URL.String(), req.Proto),
+		RequestHeaders: flattenHTTPHeader(req.Header),
+		At:             time.Now(),
+	}
+	if req.GetBody != nil {
+		if rc, e := req.GetBody(); e == nil {
+			data, _ := io.ReadAll(io.LimitReader(rc, DiagnosticPayloadLimit+utf8.UTFMax))
+			rc.Close()
+			d.RequestBody = ExcerptPayload(data)
+		}
+	}
+	if res != nil {
+		d.Status = res.Status
+		d.ResponseHeaders = flattenHTTPHeader(res.Header)
+		d.ResponseBody = ExcerptPayload(body)
+	}
+	return AttachDiagnostic(err, d)
+}
+
+// ownTransport 复制client以及Transport后替换，修改Transport的配置项都需要先调用，避免修改WithClient传入的、可能被共享的Transport；
+// Transport为nil时复制http.DefaultTransport，不支持的Transport直接panic
+func (ha *HTTPAttacker) ownTransport() http.RoundTripper {
+	transport := cloneTransport(ha.client.Transport)
+	switch transport.(type) {
+	case *http.Transport, *http2.Transport:
+	default:
+		panic(fmt.Sprintf("cannot configure the transport %T", ha.client.Transport))
+	}
+	client := *ha.client
+	client.Transport = transport
+	ha.client = &client
+	return transport
+}
+
+// tlsConfig 复制Transport及其TLS配置后返回TLS配置
+func (ha *HTTPAttacker) tlsConfig() *tls.Config {
+	var conf *tls.Config
+	switch t := ha.ownTransport().(type) {
+	case *http.Transport: // Clone时已复制TLS配置
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		}
+		conf = t.TLSClientConfig
+	case *http2.Transport:
+		if t.TLSClientConfig = t.TLSClientConfig.Clone(); t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		}
+		conf = t.TLSClientConfig
+	}
+	return conf
+}
+
+// currentTLSConfig 复制当前Transport的TLS配置，不支持的Transport返回nil
+func (ha *HTTPAttacker) currentTLSConfig() *tls.Config {
+	switch t := ha.client.Transport.(type) {
+	case *http.Transport:
+		return t.TLSClientConfig.Clone()
+	case *http2.Transport:
+		return t.TLSClientConfig.Clone()
+	}
+	return nil
+}
+
 func (ha *HTTPAttacker) Apply(opts ...HTTPAttackerOption) {
 	for _, opt := range opts {
 		opt(ha)
@@ -152,12 +273,26 @@ func WithCheckFuncs(checks ...HTTPCheckFunc) HTTPAttackerOption {
 
 func WithDisableKeepAlives(disable bool) HTTPAttackerOption {
 	return func(h *HTTPAttacker) {
-		if tran, ok := h.client.Transport.(*http.Transport); ok {
+		if tran, ok := h.ownTransport().(*http.Transport); ok { // http2.Transport不支持
 			tran.DisableKeepAlives = disable
 		}
 	}
 }
 
+// WithDiagnostics 请求失败时是否附加诊断信息，默认开启；保留数量由统计中的诊断信息抽样数量控制
+func WithDiagnostics(enable bool) HTTPAttackerOption {
+	return func(h *HTTPAttacker) {
+		h.diagnostics = enable
+	}
+}
+
+// WithHTTPTrace 是否通过httptrace统计DNS、建连、TLS握手、首字节、传输各阶段的耗时以及连接是否复用，默认关闭
+func WithHTTPTrace(enable bool) HTTPAttackerOption {
+	return func(h *HTTPAttacker) {
+		h.trace = enable
+	}
+}
+
 func WithTimeout(t time.Duration) HTTPAttackerOption {
 	return func(h *HTTPAttacker) {
 		h.client.Timeout = t
@@ -166,16 +301,92 @@ func WithTimeout(t time.Duration) HTTPAttackerOption {
 
 func WithProxy(proxy func(*http.Request) (*url.URL, error)) HTTPAttackerOption {
 	return func(h *HTTPAttacker) {
-		if transport, ok := h.client.Transport.(*http.Transport); ok {
+		if transport, ok := h.ownTransport().(*http.Transport); ok { // http2.Transport不支持
 			transport.Proxy = proxy
 		}
 	}
 }
 
+// WithHTTPProtocol 切换协议，会替换当前的Transport，仅保留TLS配置，之前的代理、keep-alive等配置失效
+func WithHTTPProtocol(protocol HTTPProtocol) HTTPAttackerOption {
+	return func(h *HTTPAttacker) {
+		conf := h.currentTLSConfig()
+		client := *h.client // 不修改WithClient传入的client
+		h.client = &client
+		switch protocol {
+		case HTTPProtocolHTTP1:
+			transport := newHTTPTransport(h.dialer)
+			transport.TLSClientConfig = conf
+			h.client.Transport = transport
+		case HTTPProtocolHTTP2:
+			h.client.Transport = &http2.Transport{TLSClientConfig: conf, DialTLSContext: h.dialer.DialTLSContext}
+		case HTTPProtocolH2C:
+			h.client.Transport = &http2.Transport{
+				TLSClientConfig: conf,
+				AllowHTTP:       true,
+				DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+					return h.dialer.DialContext(ctx, network, addr)
+				},
+			}
+		default:
+			panic("unknown http protocol")
+		}
+	}
+}
+
+// WithClientCertificates 双向认证时使用的客户端证书
+func WithClientCertificates(certs ...tls.Certificate) HTTPAttackerOption {
+	return func(h *HTTPAttacker) {
+		conf := h.tlsConfig()
+		conf.Certificates = append(conf.Certificates, certs...)
+	}
+}
+
+// WithRootCAs 校验服务端证书使用的根证书，为nil时使用系统根证书
+func WithRootCAs(pool *x509.CertPool) HTTPAttackerOption {
+	return func(h *HTTPAttacker) {
+		h.tlsConfig().RootCAs = pool
+	}
+}
+
+// WithInsecureSkipVerify 是否跳过服务端证书校验
+func WithInsecureSkipVerify(skip bool) HTTPAttackerOption {
+	return func(h *HTTPAttacker) {
+		h.tlsConfig().InsecureSkipVerify = skip
+	}
+}
+
+// WithTLSSessionResumption 是否复用TLS会话以简化新连接的握手，默认关闭，即每个新连接都完整握手
+func WithTLSSessionResumption(enable bool) HTTPAttackerOption {
+	return func(h *HTTPAttacker) {
+		conf := h.tlsConfig()
+		conf.ClientSessionCache = nil
+		if enable {
+			conf.ClientSessionCache = tls.NewLRUClientSessionCache(0)
+		}
+	}
+}
+
+// WithServerName 覆盖SNI以及证书校验使用的主机名，为空时使用请求的主机名
+func WithServerName(name string) HTTPAttackerOption {
+	return func(h *HTTPAttacker) {
+		h.tlsConfig().ServerName = name
+	}
+}
+
 // CheckHTTPStatusCode 检查状态码是否>=400, 如果是则视为请求失败
 func CheckHTTPStatusCode(_ context.Context, res *http.Response, body []byte) error {
 	if res.StatusCode >= http.StatusBadRequest {
-		return fmt.Errorf("bad status code: %d", res.StatusCode)
+		return &HTTPStatusError{Code: res.StatusCode}
 	}
 	return nil
 }
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("bad status code: %d", e.Code)
+}
+
+// StatusCode 用于错误归类
+func (e *HTTPStatusError) StatusCode() int {
+	return e.Code
+}
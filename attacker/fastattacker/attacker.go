@@ -2,11 +2,12 @@ package fastattacker
 
 import (
 	"context"
-	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/valyala/fasthttp"
 	"github.com/wosai/ultron/v2"
+	"github.com/wosai/ultron/v2/pkg/statistics"
 )
 
 type (
@@ -16,6 +17,10 @@ type (
 		client      *fasthttp.Client
 		prepareFunc FastHTTPPrepareFunc
 		checkFuncs  []FastHTTPCheckFunc
+		diagnostics bool // 请求失败时附加诊断信息
+		connections ultron.ConnectionModel
+		cookieJar   bool       // 每个虚拟用户独立的Cookie
+		shared      *userState // 无法区分虚拟用户时使用
 	}
 
 	// FastHTTPPrepareFunc 构造fasthttp.Request的请求
@@ -29,23 +34,30 @@ type (
 const defaultUserAgent = "github.com/wosai/ultron"
 
 var (
-	defaultFastHTTPClient = &fasthttp.Client{
+	_ ultron.Attacker        = (*FastHTTPAttacker)(nil)
+	_ ultron.VirtualUserHook = (*FastHTTPAttacker)(nil)
+)
+
+// newFastHTTPClient 每个FastHTTPAttacker独占的fasthttp.Client
+func newFastHTTPClient() *fasthttp.Client {
+	return &fasthttp.Client{
 		Name:                defaultUserAgent,
 		MaxConnsPerHost:     1000,
 		MaxIdleConnDuration: 30 * time.Second,
 		ReadTimeout:         30 * time.Second,
 		WriteTimeout:        30 * time.Second,
 	}
-
-	_ ultron.Attacker = (*FastHTTPAttacker)(nil)
-)
+}
 
 func NewFastHTTPAttacker(name string) *FastHTTPAttacker {
-	return &FastHTTPAttacker{
-		name:       name,
-		client:     defaultFastHTTPClient,
-		checkFuncs: make([]FastHTTPCheckFunc, 0),
+	fa := &FastHTTPAttacker{
+		name:        name,
+		client:      newFastHTTPClient(),
+		checkFuncs:  make([]FastHTTPCheckFunc, 0),
+		diagnostics: true,
 	}
+	fa.shared = &userState{attacker: fa}
+	return fa
 }
 
 func (fa *FastHTTPAttacker) Name() string {
@@ -76,18 +88,46 @@ func (fa *FastHTTPAttacker) Fire(ctx context.Context) error {
 	default:
 	}
 
-	if err = fa.client.Do(req, res); err != nil {
-		return err
+	user := fa.user(ctx)
+	uri := user.attachCookies(req)
+	if err = user.httpClient().Do(req, res); err != nil {
+		return fa.diagnose(err, req, nil)
 	}
+	user.storeCookies(uri, res)
 
 	for _, check := range fa.checkFuncs {
 		if err = check(ctx, res); err != nil {
-			return err
+			return fa.diagnose(err, req, res)
 		}
 	}
 	return nil
 }
 
+// diagnose 为失败的请求附加诊断信息，request、response会被回收，需要复制
+func (fa *FastHTTPAttacker) diagnose(err error, req *fasthttp.Request, res *fasthttp.Response) error {
+	if !fa.diagnostics {
+		return err
+	}
+	d := &statistics.Diagnostic{
+		Request:        string(req.Header.Method()) + " " + req.URI().String() + " " + string(req.Header.Protocol()),
+		RequestHeaders: make(map[string]string),
+		RequestBody:    ultron.ExcerptPayload(req.Body()),
+		At:             time.Now(),
+	}
+	req.Header.VisitAll(func(key, value []byte) {
+		d.RequestHeaders[string(key)] = ultron.RedactHeader(string(key), string(value))
+	})
+	if res != nil {
+		d.Status = strconv.Itoa(res.StatusCode()) + " " + fasthttp.StatusMessage(res.StatusCode())
+		d.ResponseHeaders = make(map[string]string)
+		res.Header.VisitAll(func(key, value []byte) {
+			d.ResponseHeaders[string(key)] = ultron.RedactHeader(string(key), string(value))
+		})
+		d.ResponseBody = ultron.ExcerptPayload(res.Body())
+	}
+	return ultron.AttachDiagnostic(err, d)
+}
+
 func (fa *FastHTTPAttacker) Apply(opts ...FastHTTPAttackerOption) {
 	for _, opt := range opts {
 		opt(fa)
@@ -120,6 +160,13 @@ func WithPrepareFunc(prepare FastHTTPPrepareFunc) FastHTTPAttackerOption {
 	}
 }
 
+// WithDiagnostics 请求失败时是否附加诊断信息，默认开启；保留数量由统计中的诊断信息抽样数量控制
+func WithDiagnostics(enable bool) FastHTTPAttackerOption {
+	return func(fh *FastHTTPAttacker) {
+		fh.diagnostics = enable
+	}
+}
+
 func WithTimeout(t time.Duration) FastHTTPAttackerOption {
 	return func(fh *FastHTTPAttacker) {
 		fh.client.ReadTimeout = t
@@ -129,7 +176,7 @@ func WithTimeout(t time.Duration) FastHTTPAttackerOption {
 
 func CheckHTTPStatusCode(_ context.Context, res *fasthttp.Response) error {
 	if code := res.StatusCode(); code >= fasthttp.StatusBadRequest {
-		return fmt.Errorf("bad status code: %d", code)
+		return &ultron.HTTPStatusError{Code: code}
 	}
 	return nil
 }
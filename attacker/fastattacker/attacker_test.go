@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/valyala/fasthttp"
+	"github.com/wosai/ultron/v2"
 )
 
 func TestFastHTTPAttacker_Fire(t *testing.T) {
@@ -37,3 +40,37 @@ func TestFastHTTPAttacker_Fire(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+func TestFastHTTPAttacker_Diagnose(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+		rw.Write([]byte("maintenance"))
+	}))
+	defer server.Close()
+
+	attacker := NewFastHTTPAttacker("diagnose")
+	attacker.Apply(
+		WithPrepareFunc(func(_ context.Context, r *fasthttp.Request) error {
+			r.SetRequestURI(server.URL + "/ping")
+			r.Header.SetMethod(fasthttp.MethodPost)
+			r.Header.Set("Cookie", "session=secret")
+			r.SetBodyString("ping")
+			return nil
+		}),
+		WithCheckFunc(CheckHTTPStatusCode),
+	)
+	err := attacker.Fire(context.Background())
+	if err == nil || err.Error() != "bad status code: 503" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d := ultron.DiagnosticOf(err)
+	if d == nil {
+		t.Fatal("missing diagnostic")
+	}
+	if d.Request != "POST "+server.URL+"/ping HTTP/1.1" || d.RequestBody != "ping" || d.RequestHeaders["Cookie"] == "session=secret" {
+		t.Fatalf("unexpected request diagnostic: %+v", d)
+	}
+	if d.Status != "503 Service Unavailable" || d.ResponseBody != "maintenance" {
+		t.Fatalf("unexpected response diagnostic: %+v", d)
+	}
+}
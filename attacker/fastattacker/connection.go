@@ -0,0 +1,159 @@
+package fastattacker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"sync/atomic"
+
+	"github.com/valyala/fasthttp"
+	"github.com/wosai/ultron/v2"
+)
+
+type (
+	// userState 虚拟用户独占的client以及Cookie
+	userState struct {
+		attacker *FastHTTPAttacker
+		client   *fasthttp.Client // 为nil时使用FastHTTPAttacker的client
+		jar      *cookiejar.Jar   // 为nil时不维护Cookie
+	}
+)
+
+// cloneClient 复制fasthttp.Client的配置，连接池不共享
+func cloneClient(c *fasthttp.Client) *fasthttp.Client {
+	return &fasthttp.Client{
+		Name:                          c.Name,
+		NoDefaultUserAgentHeader:      c.NoDefaultUserAgentHeader,
+		Dial:                          c.Dial,
+		DialDualStack:                 c.DialDualStack,
+		TLSConfig:                     c.TLSConfig,
+		MaxConnsPerHost:               c.MaxConnsPerHost,
+		MaxIdleConnDuration:           c.MaxIdleConnDuration,
+		MaxConnDuration:               c.MaxConnDuration,
+		MaxIdemponentCallAttempts:     c.MaxIdemponentCallAttempts,
+		ReadBufferSize:                c.ReadBufferSize,
+		WriteBufferSize:               c.WriteBufferSize,
+		ReadTimeout:                   c.ReadTimeout,
+		WriteTimeout:                  c.WriteTimeout,
+		MaxResponseBodySize:           c.MaxResponseBodySize,
+		DisableHeaderNamesNormalizing: c.DisableHeaderNamesNormalizing,
+		DisablePathNormalizing:        c.DisablePathNormalizing,
+		MaxConnWaitTimeout:            c.MaxConnWaitTimeout,
+		RetryIf:                       c.RetryIf,
+		ConfigureClient:               c.ConfigureClient,
+	}
+}
+
+func (fa *FastHTTPAttacker) userKey() string {
+	return fmt.Sprintf("fasthttp:%p", fa)
+}
+
+// user 当前虚拟用户的状态，保存在虚拟用户级别的数据中；无法区分虚拟用户时使用共享的状态
+func (fa *FastHTTPAttacker) user(ctx context.Context) *userState {
+	if fa.connections == ultron.ConnectionShared && !fa.cookieJar {
+		return fa.shared
+	}
+	if v, ok := ultron.UserValue(ctx, fa.userKey()); ok {
+		return v.(*userState)
+	}
+	user := &userState{attacker: fa}
+	if fa.connections == ultron.ConnectionPerUser {
+		user.client = cloneClient(fa.client)
+	}
+	if fa.cookieJar {
+		user.jar, _ = cookiejar.New(nil) // 不会返回error
+	}
+	if !ultron.StoreUserValue(ctx, fa.userKey(), user) {
+		return fa.shared
+	}
+	return user
+}
+
+func (u *userState) httpClient() *fasthttp.Client {
+	if u.client == nil {
+		return u.attacker.client
+	}
+	return u.client
+}
+
+// attachCookies 为请求附加Cookie，返回请求的URL，不维护Cookie时返回nil
+func (u *userState) attachCookies(req *fasthttp.Request) *url.URL {
+	if u.jar == nil {
+		return nil
+	}
+	uri, err := url.Parse(req.URI().String())
+	if err != nil {
+		return nil
+	}
+	for _, c := range u.jar.Cookies(uri) {
+		req.Header.SetCookie(c.Name, c.Value)
+	}
+	return uri
+}
+
+func (u *userState) storeCookies(uri *url.URL, res *fasthttp.Response) {
+	if u.jar == nil || uri == nil {
+		return
+	}
+	header := make(http.Header)
+	res.Header.VisitAllCookie(func(_, value []byte) {
+		header.Add("Set-Cookie", string(value))
+	})
+	u.jar.SetCookies(uri, (&http.Response{Header: header}).Cookies())
+}
+
+func (fa *FastHTTPAttacker) OnStart(context.Context) error {
+	return nil
+}
+
+// OnStop 关闭虚拟用户独占的空闲连接
+func (fa *FastHTTPAttacker) OnStop(ctx context.Context) {
+	if fa.connections != ultron.ConnectionPerUser {
+		return
+	}
+	if v, ok := ultron.UserValue(ctx, fa.userKey()); ok {
+		v.(*userState).httpClient().CloseIdleConnections()
+	}
+}
+
+// WithConnectionModel 虚拟用户之间如何共享连接，默认共享连接池
+func WithConnectionModel(model ultron.ConnectionModel) FastHTTPAttackerOption {
+	return func(fh *FastHTTPAttacker) {
+		if model != ultron.ConnectionShared && model != ultron.ConnectionPerUser {
+			panic("unknown connection model")
+		}
+		fh.connections = model
+	}
+}
+
+// WithUserCookieJar 是否为每个虚拟用户维护独立的Cookie，默认关闭
+func WithUserCookieJar(enable bool) FastHTTPAttackerOption {
+	return func(fh *FastHTTPAttacker) {
+		fh.cookieJar = enable
+	}
+}
+
+// WithLocalAddrs 新建连接时依次绑定的本地IP，用于多个源地址分摊临时端口；会覆盖client的Dial
+func WithLocalAddrs(ips ...string) FastHTTPAttackerOption {
+	return func(fh *FastHTTPAttacker) {
+		if len(ips) == 0 {
+			fh.client.Dial = nil
+			return
+		}
+		dialers := make([]*fasthttp.TCPDialer, len(ips))
+		for i, ip := range ips {
+			parsed := net.ParseIP(ip)
+			if parsed == nil {
+				panic(fmt.Sprintf("invalid local address %s", ip))
+			}
+			dialers[i] = &fasthttp.TCPDialer{LocalAddr: &net.TCPAddr{IP: parsed}}
+		}
+		var next uint32
+		fh.client.Dial = func(addr string) (net.Conn, error) {
+			return dialers[atomic.AddUint32(&next, 1)%uint32(len(dialers))].Dial(addr)
+		}
+	}
+}
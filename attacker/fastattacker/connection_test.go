@@ -0,0 +1,107 @@
+package fastattacker
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+	"github.com/wosai/ultron/v2"
+)
+
+// newUserContexts 模拟多个虚拟用户的context
+func newUserContexts(n int) []context.Context {
+	users := make([]context.Context, n)
+	for i := range users {
+		ctx := ultron.AllocateStorageInContext(context.Background())
+		ultron.ClearStorageInContext(ctx)
+		users[i] = ctx
+	}
+	return users
+}
+
+func TestFastHTTPAttacker_ConnectionModel(t *testing.T) {
+	for _, c := range []struct {
+		model  ultron.ConnectionModel
+		opened int32
+	}{{ultron.ConnectionShared, 1}, {ultron.ConnectionPerUser, 2}} {
+		var opened int32
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+		server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+			if state == http.StateNew {
+				atomic.AddInt32(&opened, 1)
+			}
+		}
+		server.Start()
+
+		attacker := NewFastHTTPAttacker("unittest")
+		attacker.Apply(
+			WithConnectionModel(c.model),
+			WithLocalAddrs("127.0.0.1"),
+			WithPrepareFunc(func(_ context.Context, r *fasthttp.Request) error {
+				r.SetRequestURI(server.URL)
+				return nil
+			}),
+		)
+		users := newUserContexts(2)
+		for i := 0; i < 3; i++ {
+			for _, user := range users {
+				if err := attacker.Fire(user); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+		}
+		if n := atomic.LoadInt32(&opened); n != c.opened {
+			t.Fatalf("expected %d connections, got %d", c.opened, n)
+		}
+		for _, user := range users {
+			attacker.OnStop(user)
+		}
+		server.Close()
+	}
+}
+
+func TestFastHTTPAttacker_UserCookieJar(t *testing.T) {
+	var session int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c, err := r.Cookie("session"); err == nil {
+			w.Write([]byte(c.Value))
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: strconv.Itoa(int(atomic.AddInt32(&session, 1)))})
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	var bodies []string
+	attacker := NewFastHTTPAttacker("unittest")
+	attacker.Apply(
+		WithUserCookieJar(true),
+		WithPrepareFunc(func(_ context.Context, r *fasthttp.Request) error {
+			r.SetRequestURI(server.URL)
+			return nil
+		}),
+		WithCheckFunc(func(_ context.Context, r *fasthttp.Response) error {
+			mu.Lock()
+			defer mu.Unlock()
+			bodies = append(bodies, string(r.Body()))
+			return nil
+		}),
+	)
+	users := newUserContexts(2)
+	for i := 0; i < 2; i++ {
+		for _, user := range users {
+			if err := attacker.Fire(user); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+	if len(bodies) != 4 || bodies[0] != "" || bodies[1] != "" || bodies[2] != "1" || bodies[3] != "2" {
+		t.Fatalf("unexpected bodies: %q", bodies)
+	}
+}
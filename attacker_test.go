@@ -2,14 +2,26 @@ package ultron
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"errors"
+	"math/big"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/wosai/ultron/v2/pkg/statistics"
 	"go.uber.org/zap"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 type mockAttacker struct{}
@@ -98,8 +110,180 @@ func TestHTTPAttacker_Apply(t *testing.T) {
 		}),
 	)
 
-	assert.Equal(t, attacker.client, client)
 	assert.EqualValues(t, attacker.client.Timeout, 3*time.Second)
 	assert.EqualValues(t, len(attacker.checkFuncs), 1)
+	assert.True(t, attacker.client.Transport.(*http.Transport).DisableKeepAlives)
 	assert.NotNil(t, attacker.client.Transport.(*http.Transport).Proxy)
+
+	// WithClient传入的Transport可能被共享，不应被修改
+	assert.NotSame(t, client.Transport, attacker.client.Transport)
+	assert.False(t, client.Transport.(*http.Transport).DisableKeepAlives)
+	assert.Nil(t, client.Transport.(*http.Transport).Proxy)
+}
+
+func TestHTTPAttacker_OwnClient(t *testing.T) {
+	a1 := NewHTTPAttacker("a1", WithTimeout(time.Second))
+	a2 := NewHTTPAttacker("a2")
+	assert.NotSame(t, a1.client, a2.client)
+	assert.NotSame(t, a1.client.Transport, a2.client.Transport)
+	assert.EqualValues(t, 45*time.Second, a2.client.Timeout)
+}
+
+// getURL 请求url，并通过校验函数取出响应
+func getURL(url string, opts ...HTTPAttackerOption) (*http.Response, error) {
+	var res *http.Response
+	opts = append(opts,
+		WithPrepareFunc(func(context.Context) (*http.Request, error) {
+			return http.NewRequest(http.MethodGet, url, nil)
+		}),
+		WithCheckFuncs(func(_ context.Context, r *http.Response, _ []byte) error {
+			res = r
+			return nil
+		}),
+	)
+	err := NewHTTPAttacker("unittest", opts...).Fire(context.Background())
+	return res, err
+}
+
+func newClientCertificate(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "ultron"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.Nil(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestHTTPAttacker_TLS(t *testing.T) {
+	var mu sync.Mutex
+	var serverName string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAnyClientCert,
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			serverName = hello.ServerName
+			return nil, nil
+		},
+	}
+	server.StartTLS()
+	defer server.Close()
+	cert := newClientCertificate(t)
+
+	_, err := getURL(server.URL, WithClientCertificates(cert))
+	assert.Error(t, err) // 未知的根证书
+
+	_, err = getURL(server.URL, WithInsecureSkipVerify(true))
+	assert.Error(t, err) // 缺少客户端证书
+
+	_, err = getURL(server.URL, WithInsecureSkipVerify(true), WithClientCertificates(cert))
+	assert.Nil(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	_, err = getURL(server.URL, WithRootCAs(pool), WithServerName("example.com"), WithClientCertificates(cert))
+	assert.Nil(t, err)
+	mu.Lock()
+	assert.EqualValues(t, "example.com", serverName)
+	mu.Unlock()
+}
+
+func TestHTTPAttacker_TLSSharedClient(t *testing.T) {
+	shared := &http.Transport{TLSClientConfig: &tls.Config{ServerName: "shared"}}
+	client := &http.Client{Transport: shared}
+	attacker := NewHTTPAttacker("unittest", WithClient(client), WithInsecureSkipVerify(true))
+	assert.False(t, shared.TLSClientConfig.InsecureSkipVerify) // 不修改共享的Transport
+	assert.Same(t, shared, client.Transport)
+	conf := attacker.client.Transport.(*http.Transport).TLSClientConfig
+	assert.True(t, conf.InsecureSkipVerify)
+	assert.EqualValues(t, "shared", conf.ServerName)
+
+	attacker = NewHTTPAttacker("unittest", WithClient(&http.Client{}), WithServerName("example.com"))
+	assert.EqualValues(t, "example.com", attacker.client.Transport.(*http.Transport).TLSClientConfig.ServerName)
+
+	h2 := &http2.Transport{AllowHTTP: true}
+	attacker = NewHTTPAttacker("unittest", WithClient(&http.Client{Transport: h2}), WithInsecureSkipVerify(true))
+	assert.Nil(t, h2.TLSClientConfig)
+	assert.True(t, attacker.client.Transport.(*http2.Transport).AllowHTTP)
+	assert.True(t, attacker.client.Transport.(*http2.Transport).TLSClientConfig.InsecureSkipVerify)
+
+	assert.Panics(t, func() {
+		NewHTTPAttacker("unittest", WithClient(&http.Client{Transport: roundTripperFunc(nil)}), WithInsecureSkipVerify(true))
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestHTTPAttacker_TLSSessionResumption(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+
+	for _, enable := range []bool{true, false} {
+		var resumed []bool
+		attacker := NewHTTPAttacker("unittest",
+			WithInsecureSkipVerify(true),
+			WithTLSSessionResumption(enable),
+			WithDisableKeepAlives(true),
+			WithPrepareFunc(func(context.Context) (*http.Request, error) {
+				return http.NewRequest(http.MethodGet, server.URL, nil)
+			}),
+			WithCheckFuncs(func(_ context.Context, r *http.Response, _ []byte) error {
+				resumed = append(resumed, r.TLS.DidResume)
+				return nil
+			}),
+		)
+		for i := 0; i < 2; i++ {
+			assert.Nil(t, attacker.Fire(context.Background()))
+		}
+		assert.EqualValues(t, []bool{false, enable}, resumed)
+	}
+}
+
+func TestHTTPAttacker_HTTP2(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	res, err := getURL(server.URL, WithInsecureSkipVerify(true))
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, res.ProtoMajor)
+
+	res, err = getURL(server.URL, WithInsecureSkipVerify(true), WithHTTPProtocol(HTTPProtocolHTTP2)) // 保留之前的TLS配置
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, res.ProtoMajor)
+
+	res, err = getURL(server.URL, WithHTTPProtocol(HTTPProtocolHTTP2), WithHTTPProtocol(HTTPProtocolHTTP1), WithInsecureSkipVerify(true))
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, res.ProtoMajor)
+
+	assert.Panics(t, func() { NewHTTPAttacker("unittest", WithHTTPProtocol(HTTPProtocol(-1))) })
+}
+
+func TestHTTPAttacker_H2C(t *testing.T) {
+	server := httptest.NewServer(h2c.NewHandler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}), &http2.Server{}))
+	defer server.Close()
+
+	res, err := getURL(server.URL, WithHTTPProtocol(HTTPProtocolH2C))
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, res.ProtoMajor)
+}
+
+func TestCheckHTTPStatusCode(t *testing.T) {
+	assert.Nil(t, CheckHTTPStatusCode(context.Background(), &http.Response{StatusCode: http.StatusOK}, nil))
+
+	err := CheckHTTPStatusCode(context.Background(), &http.Response{StatusCode: http.StatusServiceUnavailable}, nil)
+	assert.EqualError(t, err, "bad status code: 503")
+	assert.EqualValues(t, statistics.DefaultErrorClassifier(err), "http 5xx")
 }
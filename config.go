@@ -4,14 +4,18 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/jacexh/multiconfig"
 )
 
 type (
 	Option struct {
-		Server ServerOption
-		Logger LoggerOption
+		Server     ServerOption
+		Logger     LoggerOption
+		Queue      PlanQueueOption
+		History    ReportHistoryOption
+		Statistics StatisticsOption
 	}
 
 	ServerOption struct {
@@ -19,6 +23,24 @@ type (
 		GRPCAddr string `default:":2021" yaml:"grpc_addr,omitempty" json:"grpc_addr,omitempty" toml:"grpc_addr"`
 	}
 
+	PlanQueueOption struct {
+		StoreFile string `yaml:"store_file,omitempty" json:"store_file,omitempty" toml:"store_file"` // 为空时不持久化排队中的测试计划
+	}
+
+	ReportHistoryOption struct {
+		MaxPlans  int `default:"5" yaml:"max_plans,omitempty" json:"max_plans,omitempty" toml:"max_plans"`       // 保留最近的测试计划数量
+		MaxPoints int `default:"1440" yaml:"max_points,omitempty" json:"max_points,omitempty" toml:"max_points"` // 单个测试计划保留的最大点数，超出后降采样
+	}
+
+	StatisticsOption struct {
+		RecentWindow       time.Duration `default:"12s" yaml:"recent_window,omitempty" json:"recent_window,omitempty" toml:"recent_window"`     // 实时报告中百分位、平均值以及错误率的统计窗口
+		TimelineResolution time.Duration `yaml:"timeline_resolution,omitempty" json:"timeline_resolution,omitempty" toml:"timeline_resolution"` // 时间线的时间片长度，为0时不统计时间线
+		TimelineMaxSlots   int           `default:"3600" yaml:"timeline_max_slots,omitempty" json:"timeline_max_slots,omitempty" toml:"timeline_max_slots"`
+		MaxFailureKeys     int           `default:"50" yaml:"max_failure_keys,omitempty" json:"max_failure_keys,omitempty" toml:"max_failure_keys"`      // 每个attacker错误类别的数量上限，超出后归入other
+		FailureSamples     int           `default:"3" yaml:"failure_samples,omitempty" json:"failure_samples,omitempty" toml:"failure_samples"`          // 每个错误类别保留的原始错误信息数量
+		DiagnosticSamples  int           `default:"3" yaml:"diagnostic_samples,omitempty" json:"diagnostic_samples,omitempty" toml:"diagnostic_samples"` // 每个错误类别抽样保留的诊断信息数量，为0时不保留
+	}
+
 	LoggerOption struct {
 		Level      string `default:"info" yaml:"level,omitempty" json:"level,omitempty" toml:"level"`
 		FileName   string `yaml:"filename,omitempty" json:"filename,omitempty" toml:"filename"`
@@ -0,0 +1,168 @@
+package ultron
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/http2"
+)
+
+type (
+	// ConnectionModel 虚拟用户之间如何共享连接
+	ConnectionModel int
+
+	// httpDialer 配置了本地地址时，新建连接依次绑定各个地址
+	httpDialer struct {
+		net.Dialer
+		localAddrs []net.Addr
+		next       uint32
+	}
+)
+
+const (
+	ConnectionShared  ConnectionModel = iota // 默认，所有虚拟用户共享连接池
+	ConnectionPerUser                        // 每个虚拟用户独占Transport，虚拟用户退出时关闭其空闲连接
+)
+
+func newHTTPDialer() *httpDialer {
+	return &httpDialer{
+		Dialer: net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			DualStack: true,
+		},
+	}
+}
+
+func (d *httpDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if len(d.localAddrs) == 0 {
+		return d.Dialer.DialContext(ctx, network, address)
+	}
+	dialer := d.Dialer
+	dialer.LocalAddr = d.localAddrs[atomic.AddUint32(&d.next, 1)%uint32(len(d.localAddrs))]
+	return dialer.DialContext(ctx, network, address)
+}
+
+// DialTLSContext 供http2.Transport使用，conf中已包含ALPN以及SNI
+func (d *httpDialer) DialTLSContext(ctx context.Context, network, address string, conf *tls.Config) (net.Conn, error) {
+	conn, err := d.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	tc := tls.Client(conn, conf)
+	if err := tc.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return tc, nil
+}
+
+// cloneTransport 复制Transport的配置，连接池不共享
+func cloneTransport(rt http.RoundTripper) http.RoundTripper {
+	switch t := rt.(type) {
+	case nil:
+		return http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		return t.Clone()
+	case *http2.Transport:
+		return &http2.Transport{
+			TLSClientConfig:            t.TLSClientConfig,
+			AllowHTTP:                  t.AllowHTTP,
+			DialTLSContext:             t.DialTLSContext,
+			DialTLS:                    t.DialTLS,
+			DisableCompression:         t.DisableCompression,
+			MaxHeaderListSize:          t.MaxHeaderListSize,
+			MaxReadFrameSize:           t.MaxReadFrameSize,
+			MaxDecoderHeaderTableSize:  t.MaxDecoderHeaderTableSize,
+			MaxEncoderHeaderTableSize:  t.MaxEncoderHeaderTableSize,
+			StrictMaxConcurrentStreams: t.StrictMaxConcurrentStreams,
+			ReadIdleTimeout:            t.ReadIdleTimeout,
+			PingTimeout:                t.PingTimeout,
+			WriteByteTimeout:           t.WriteByteTimeout,
+			CountError:                 t.CountError,
+		}
+	}
+	return rt // 未知的Transport无法复制，仍然共享
+}
+
+func (ha *HTTPAttacker) userKey() string {
+	return fmt.Sprintf("http:%p", ha)
+}
+
+// userClient 当前虚拟用户使用的client，保存在虚拟用户级别的数据中；无法区分虚拟用户时使用共享的client
+func (ha *HTTPAttacker) userClient(ctx context.Context) *http.Client {
+	if ha.connections == ConnectionShared && !ha.cookieJar {
+		return ha.client
+	}
+	if v, ok := UserValue(ctx, ha.userKey()); ok {
+		return v.(*http.Client)
+	}
+	client := &http.Client{
+		Transport:     ha.client.Transport,
+		CheckRedirect: ha.client.CheckRedirect,
+		Jar:           ha.client.Jar,
+		Timeout:       ha.client.Timeout,
+	}
+	if ha.connections == ConnectionPerUser {
+		client.Transport = cloneTransport(client.Transport)
+	}
+	if ha.cookieJar {
+		client.Jar, _ = cookiejar.New(nil) // 不会返回error
+	}
+	if !StoreUserValue(ctx, ha.userKey(), client) {
+		return ha.client
+	}
+	return client
+}
+
+func (ha *HTTPAttacker) OnStart(context.Context) error {
+	return nil
+}
+
+// OnStop 关闭虚拟用户独占的空闲连接
+func (ha *HTTPAttacker) OnStop(ctx context.Context) {
+	if ha.connections != ConnectionPerUser {
+		return
+	}
+	if v, ok := UserValue(ctx, ha.userKey()); ok {
+		v.(*http.Client).CloseIdleConnections()
+	}
+}
+
+// WithConnectionModel 虚拟用户之间如何共享连接，默认共享连接池
+func WithConnectionModel(model ConnectionModel) HTTPAttackerOption {
+	return func(h *HTTPAttacker) {
+		if model != ConnectionShared && model != ConnectionPerUser {
+			panic("unknown connection model")
+		}
+		h.connections = model
+	}
+}
+
+// WithUserCookieJar 是否为每个虚拟用户维护独立的Cookie，默认关闭
+func WithUserCookieJar(enable bool) HTTPAttackerOption {
+	return func(h *HTTPAttacker) {
+		h.cookieJar = enable
+	}
+}
+
+// WithLocalAddrs 新建连接时依次绑定的本地IP，用于多个源地址分摊临时端口；对WithClient设置的client无效
+func WithLocalAddrs(ips ...string) HTTPAttackerOption {
+	return func(h *HTTPAttacker) {
+		addrs := make([]net.Addr, len(ips))
+		for i, ip := range ips {
+			parsed := net.ParseIP(ip)
+			if parsed == nil {
+				panic(fmt.Sprintf("invalid local address %s", ip))
+			}
+			addrs[i] = &net.TCPAddr{IP: parsed}
+		}
+		h.dialer.localAddrs = addrs
+	}
+}
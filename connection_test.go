@@ -0,0 +1,116 @@
+package ultron
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPAttacker_ConnectionModel(t *testing.T) {
+	for _, c := range []struct {
+		model  ConnectionModel
+		opened int32
+		closed int32
+	}{{ConnectionShared, 1, 0}, {ConnectionPerUser, 2, 2}} {
+		var opened, closed int32
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+		server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+			switch state {
+			case http.StateNew:
+				atomic.AddInt32(&opened, 1)
+			case http.StateClosed:
+				atomic.AddInt32(&closed, 1)
+			}
+		}
+		server.Start()
+
+		attacker := NewHTTPAttacker("unittest", WithConnectionModel(c.model), WithPrepareFunc(func(context.Context) (*http.Request, error) {
+			return http.NewRequest(http.MethodGet, server.URL, nil)
+		}))
+		users := []context.Context{newExecutorSharedContext(context.Background()), newExecutorSharedContext(context.Background())}
+		for i := 0; i < 3; i++ {
+			for _, user := range users {
+				assert.Nil(t, attacker.Fire(user))
+			}
+		}
+		assert.EqualValues(t, c.opened, atomic.LoadInt32(&opened))
+
+		for _, user := range users {
+			attacker.OnStop(user)
+		}
+		assert.Eventually(t, func() bool { return atomic.LoadInt32(&closed) == c.closed }, time.Second, 10*time.Millisecond)
+		server.Close()
+	}
+
+	assert.Panics(t, func() { NewHTTPAttacker("unittest", WithConnectionModel(ConnectionModel(-1))) })
+}
+
+func TestHTTPAttacker_UserCookieJar(t *testing.T) {
+	var session int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c, err := r.Cookie("session"); err == nil {
+			w.Write([]byte(c.Value))
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: strconv.Itoa(int(atomic.AddInt32(&session, 1)))})
+	}))
+	defer server.Close()
+
+	for _, enable := range []bool{true, false} {
+		atomic.StoreInt32(&session, 0)
+		var mu sync.Mutex
+		var bodies []string
+		attacker := NewHTTPAttacker("unittest",
+			WithUserCookieJar(enable),
+			WithPrepareFunc(func(context.Context) (*http.Request, error) {
+				return http.NewRequest(http.MethodGet, server.URL, nil)
+			}),
+			WithCheckFuncs(func(_ context.Context, _ *http.Response, body []byte) error {
+				mu.Lock()
+				defer mu.Unlock()
+				bodies = append(bodies, string(body))
+				return nil
+			}),
+		)
+		users := []context.Context{newExecutorSharedContext(context.Background()), newExecutorSharedContext(context.Background())}
+		for i := 0; i < 2; i++ {
+			for _, user := range users {
+				assert.Nil(t, attacker.Fire(user))
+			}
+		}
+		if enable {
+			assert.EqualValues(t, []string{"", "", "1", "2"}, bodies) // 每个虚拟用户保留各自的会话
+		} else {
+			assert.EqualValues(t, []string{"", "", "", ""}, bodies)
+		}
+	}
+}
+
+func TestHTTPAttacker_LocalAddrs(t *testing.T) {
+	var mu sync.Mutex
+	var remote string
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		remote, _, _ = net.SplitHostPort(r.RemoteAddr)
+	}))
+	defer server.Close()
+
+	attacker := NewHTTPAttacker("unittest", WithLocalAddrs("127.0.0.1"), WithPrepareFunc(func(context.Context) (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, server.URL, nil)
+	}))
+	assert.Nil(t, attacker.Fire(context.Background()))
+	mu.Lock()
+	assert.EqualValues(t, "127.0.0.1", remote)
+	mu.Unlock()
+
+	assert.Panics(t, func() { NewHTTPAttacker("unittest", WithLocalAddrs("localhost")) })
+}
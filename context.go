@@ -3,6 +3,8 @@ package ultron
 import (
 	"context"
 	"time"
+
+	"github.com/wosai/ultron/v2/pkg/statistics"
 )
 
 type (
@@ -10,15 +12,59 @@ type (
 	executorSharedContext struct {
 		parentCtx context.Context
 		counter   int32
+		pinned    int32 // >0时，嵌套的Attacker共享当前的存储空间
 		resources map[int32]map[string]interface{}
+		user      map[string]interface{} // 虚拟用户级别的存储，生命周期与executor一致
+		rename    string                 // 非空时，本次执行结果以该名称统计
+		trace     *statistics.Trace      // 本次执行各阶段的耗时
+	}
+
+	// detachedContext 保留parent中的值，但不会被取消
+	detachedContext struct {
+		parent context.Context
 	}
 )
 
-func newExecutorSharedContext(ctx context.Context) context.Context {
+func newExecutorSharedContext(ctx context.Context) *executorSharedContext {
+	return &executorSharedContext{
+		parentCtx: ctx,
+		resources: make(map[int32]map[string]interface{}),
+		user:      make(map[string]interface{}),
+	}
+}
+
+// detach 生成一个不受executor取消影响、但共享虚拟用户存储的context，用于虚拟用户退出时的清理
+func (m *executorSharedContext) detach(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(detachedContext{parent: m.parentCtx}, timeout)
 	return &executorSharedContext{
 		parentCtx: ctx,
 		resources: make(map[int32]map[string]interface{}),
+		user:      m.user,
+	}, cancel
+}
+
+// detachContext 保留ctx中的值但不会随ctx取消，超时后取消，用于调用OnStop
+func detachContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if m, ok := ctx.(*executorSharedContext); ok {
+		return m.detach(timeout)
 	}
+	return context.WithTimeout(detachedContext{parent: ctx}, timeout)
+}
+
+func (d detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (d detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (d detachedContext) Err() error {
+	return nil
+}
+
+func (d detachedContext) Value(key interface{}) interface{} {
+	return d.parent.Value(key)
 }
 
 func (m *executorSharedContext) Deadline() (time.Time, bool) {
@@ -58,17 +104,84 @@ func StoreInContext(ctx context.Context, key string, value interface{}) bool {
 	return false
 }
 
-func ClearStorageInContext(ctx context.Context) {
+// UserValue 读取虚拟用户级别的数据
+func UserValue(ctx context.Context, key string) (interface{}, bool) {
 	if entity, ok := ctx.(*executorSharedContext); ok {
+		v, ok := entity.user[key]
+		return v, ok
+	}
+	return nil, false
+}
+
+// StoreUserValue 保存虚拟用户级别的数据，不会在每次Fire结束时清理，适合保存登录态、连接等
+func StoreUserValue(ctx context.Context, key string, value interface{}) bool {
+	if entity, ok := ctx.(*executorSharedContext); ok {
+		entity.user[key] = value
+		return true
+	}
+	return false
+}
+
+func ClearStorageInContext(ctx context.Context) {
+	if entity, ok := ctx.(*executorSharedContext); ok && entity.pinned == 0 {
 		delete(entity.resources, entity.counter)
 	}
 }
 
 func AllocateStorageInContext(ctx context.Context) context.Context {
 	if entity, ok := ctx.(*executorSharedContext); ok {
-		entity.counter++
+		if entity.pinned == 0 {
+			entity.counter++
+		}
 	} else {
 		ctx = newExecutorSharedContext(ctx)
 	}
 	return ctx
 }
+
+// renameResult 以指定名称统计本次执行结果，用于将同一Attacker的请求分组统计
+func renameResult(ctx context.Context, name string) {
+	if entity, ok := ctx.(*executorSharedContext); ok {
+		entity.rename = name
+	}
+}
+
+// takeResultName 读取并清除renameResult设置的名称，未设置时返回fallback
+func takeResultName(ctx context.Context, fallback string) string {
+	if entity, ok := ctx.(*executorSharedContext); ok && entity.rename != "" {
+		name := entity.rename
+		entity.rename = ""
+		return name
+	}
+	return fallback
+}
+
+// traceResult 附加本次执行各阶段的耗时
+func traceResult(ctx context.Context, t *statistics.Trace) {
+	if entity, ok := ctx.(*executorSharedContext); ok {
+		entity.trace = t
+	}
+}
+
+// takeResultTrace 读取并清除traceResult附加的耗时，未开启追踪时返回nil
+func takeResultTrace(ctx context.Context) *statistics.Trace {
+	if entity, ok := ctx.(*executorSharedContext); ok && entity.trace != nil {
+		t := entity.trace
+		entity.trace = nil
+		return t
+	}
+	return nil
+}
+
+// pinStorageInContext 固定当前的存储空间，之后的分配、清理都不生效，直到unpin
+func pinStorageInContext(ctx context.Context) {
+	if entity, ok := ctx.(*executorSharedContext); ok {
+		entity.pinned++
+	}
+}
+
+func unpinStorageInContext(ctx context.Context) {
+	if entity, ok := ctx.(*executorSharedContext); ok && entity.pinned > 0 {
+		entity.pinned--
+	}
+}
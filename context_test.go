@@ -3,6 +3,7 @@ package ultron
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -23,3 +24,51 @@ func TestContext(t *testing.T) {
 	StoreInContext(ctx, "hello", "world")
 	assert.EqualValues(t, es.resources[1], map[string]interface{}{"foo": "bar", "hello": "world"})
 }
+
+func TestUserValue(t *testing.T) {
+	es := newExecutorSharedContext(context.Background())
+	assert.True(t, StoreUserValue(es, "token", "abc"))
+
+	ctx := AllocateStorageInContext(es)
+	StoreInContext(ctx, "foo", "bar")
+	ClearStorageInContext(ctx)
+
+	v, ok := UserValue(ctx, "token")
+	assert.True(t, ok)
+	assert.EqualValues(t, "abc", v)
+
+	stopCtx, cancel := es.detach(time.Second)
+	defer cancel()
+	v, ok = UserValue(stopCtx, "token")
+	assert.True(t, ok)
+	assert.EqualValues(t, "abc", v)
+
+	assert.False(t, StoreUserValue(context.Background(), "token", "abc"))
+	_, ok = UserValue(context.Background(), "token")
+	assert.False(t, ok)
+}
+
+func TestExecutorSharedContext_Detach(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	ctx := newExecutorSharedContext(parent)
+	cancel()
+	assert.Error(t, ctx.Err())
+
+	stopCtx, cancelStop := ctx.detach(time.Second)
+	defer cancelStop()
+	assert.NoError(t, stopCtx.Err())
+	_, ok := stopCtx.Deadline()
+	assert.True(t, ok)
+}
+
+func TestRenameResult(t *testing.T) {
+	ctx := newExecutorSharedContext(context.Background())
+	assert.EqualValues(t, "origin", takeResultName(ctx, "origin"))
+
+	renameResult(ctx, "renamed")
+	assert.EqualValues(t, "renamed", takeResultName(ctx, "origin"))
+	assert.EqualValues(t, "origin", takeResultName(ctx, "origin"))
+
+	renameResult(context.Background(), "renamed")
+	assert.EqualValues(t, "origin", takeResultName(context.Background(), "origin"))
+}
@@ -0,0 +1,141 @@
+package ultron
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/wosai/ultron/v2/pkg/statistics"
+)
+
+type (
+	// diagnosticError 携带诊断信息的错误，不改变原始错误的信息以及归类
+	diagnosticError struct {
+		err        error
+		diagnostic *statistics.Diagnostic
+	}
+
+	// diagnosticCollector master侧保留最近一次聚合报告中的诊断信息
+	diagnosticCollector struct {
+		plan        string
+		diagnostics map[string]map[string][]statistics.Diagnostic // attacker -> 错误类别 -> 诊断信息
+		mu          sync.RWMutex
+	}
+)
+
+const (
+	// DiagnosticPayloadLimit 诊断信息中请求体、响应体摘录的最大字节数
+	DiagnosticPayloadLimit = 1024
+	redactedHeaderValue    = "<redacted>"
+)
+
+var (
+	sensitiveHeaders = map[string]struct{}{
+		"Authorization":       {},
+		"Proxy-Authorization": {},
+		"Cookie":              {},
+		"Set-Cookie":          {},
+	}
+)
+
+// AttachDiagnostic 为失败的请求附加诊断信息，err为nil时返回nil
+func AttachDiagnostic(err error, d *statistics.Diagnostic) error {
+	if err == nil || d == nil {
+		return err
+	}
+	return &diagnosticError{err: err, diagnostic: d}
+}
+
+// DiagnosticOf 提取错误中的诊断信息
+func DiagnosticOf(err error) *statistics.Diagnostic {
+	var de *diagnosticError
+	if errors.As(err, &de) {
+		return de.diagnostic
+	}
+	return nil
+}
+
+func (e *diagnosticError) Error() string {
+	return e.err.Error()
+}
+
+func (e *diagnosticError) Unwrap() error {
+	return e.err
+}
+
+// ExcerptPayload 截取请求体、响应体的摘录
+func ExcerptPayload(data []byte) string {
+	if len(data) <= DiagnosticPayloadLimit {
+		return string(data)
+	}
+	cut := DiagnosticPayloadLimit
+	for cut > 0 && !utf8.RuneStart(data[cut]) {
+		cut--
+	}
+	return string(data[:cut]) + "..."
+}
+
+// RedactHeader 隐藏鉴权、cookie等敏感头部的值
+func RedactHeader(key, value string) string {
+	if _, ok := sensitiveHeaders[http.CanonicalHeaderKey(key)]; ok {
+		return redactedHeaderValue
+	}
+	return value
+}
+
+func flattenHTTPHeader(header http.Header) map[string]string {
+	if len(header) == 0 {
+		return nil
+	}
+	ret := make(map[string]string, len(header))
+	for key, values := range header {
+		ret[key] = RedactHeader(key, strings.Join(values, ", "))
+	}
+	return ret
+}
+
+func newDiagnosticCollector() *diagnosticCollector {
+	return &diagnosticCollector{diagnostics: make(map[string]map[string][]statistics.Diagnostic)}
+}
+
+func (dc *diagnosticCollector) handleReport() ReportHandleFunc {
+	return func(_ context.Context, report statistics.SummaryReport) {
+		diagnostics := make(map[string]map[string][]statistics.Diagnostic)
+		for name, r := range report.Reports {
+			if len(r.Diagnostics) > 0 {
+				diagnostics[name] = r.Diagnostics
+			}
+		}
+
+		dc.mu.Lock()
+		defer dc.mu.Unlock()
+		dc.plan = report.Extras[KeyPlan]
+		dc.diagnostics = diagnostics
+	}
+}
+
+// query attacker、class为空时不过滤
+func (dc *diagnosticCollector) query(attacker, class string) map[string]map[string][]statistics.Diagnostic {
+	dc.mu.RLock()
+	defer dc.mu.RUnlock()
+
+	ret := make(map[string]map[string][]statistics.Diagnostic)
+	for name, classes := range dc.diagnostics {
+		if attacker != "" && name != attacker {
+			continue
+		}
+		for key, samples := range classes {
+			if class != "" && key != class {
+				continue
+			}
+			if _, ok := ret[name]; !ok {
+				ret[name] = make(map[string][]statistics.Diagnostic)
+			}
+			ret[name][key] = samples
+		}
+	}
+	return ret
+}
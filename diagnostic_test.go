@@ -0,0 +1,79 @@
+package ultron
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wosai/ultron/v2/pkg/statistics"
+)
+
+func TestAttachDiagnostic(t *testing.T) {
+	assert.Nil(t, AttachDiagnostic(nil, &statistics.Diagnostic{}))
+
+	origin := errors.New("bad status code: 500")
+	err := AttachDiagnostic(origin, &statistics.Diagnostic{Status: "500"})
+	assert.EqualError(t, err, origin.Error())
+	assert.ErrorIs(t, err, origin)
+	assert.EqualValues(t, DiagnosticOf(err).Status, "500")
+	assert.Nil(t, DiagnosticOf(origin))
+}
+
+func TestExcerptPayload(t *testing.T) {
+	assert.EqualValues(t, ExcerptPayload([]byte("hello")), "hello")
+	excerpt := ExcerptPayload([]byte(strings.Repeat("中", DiagnosticPayloadLimit)))
+	assert.True(t, strings.HasSuffix(excerpt, "..."))
+	assert.LessOrEqual(t, len(excerpt), DiagnosticPayloadLimit+3)
+	assert.True(t, strings.HasPrefix(excerpt, "中中"))
+}
+
+func TestHTTPAttacker_Diagnose(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Set-Cookie", "session=secret")
+		rw.WriteHeader(http.StatusBadGateway)
+		rw.Write([]byte(`{"error":"upstream"}`))
+	}))
+	defer server.Close()
+
+	attacker := NewHTTPAttacker("diagnose", WithClient(server.Client()), WithPrepareFunc(func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/api?id=1", strings.NewReader(`{"id":1}`))
+		if err == nil {
+			req.Header.Set("Authorization", "Bearer token")
+		}
+		return req, err
+	}), WithCheckFuncs(CheckHTTPStatusCode))
+
+	err := attacker.Fire(context.Background())
+	assert.EqualError(t, err, "bad status code: 502")
+	d := DiagnosticOf(err)
+	assert.NotNil(t, d)
+	assert.EqualValues(t, d.Request, "POST "+server.URL+"/api?id=1 HTTP/1.1")
+	assert.EqualValues(t, d.RequestBody, `{"id":1}`)
+	assert.EqualValues(t, d.RequestHeaders["Authorization"], redactedHeaderValue)
+	assert.EqualValues(t, d.Status, "502 Bad Gateway")
+	assert.EqualValues(t, d.ResponseHeaders["Set-Cookie"], redactedHeaderValue)
+	assert.EqualValues(t, d.ResponseBody, `{"error":"upstream"}`)
+
+	attacker.Apply(WithDiagnostics(false))
+	assert.Nil(t, DiagnosticOf(attacker.Fire(context.Background())))
+}
+
+func TestDiagnosticCollector_Query(t *testing.T) {
+	collector := newDiagnosticCollector()
+	collector.handleReport()(context.Background(), statistics.SummaryReport{
+		Reports: map[string]statistics.AttackReport{
+			"foo": {Name: "foo", Diagnostics: map[string][]statistics.Diagnostic{"http 5xx": {{Status: "502"}}, "timeout": {{Error: "timeout"}}}},
+			"bar": {Name: "bar"},
+		},
+		Extras: map[string]string{KeyPlan: "plan"},
+	})
+
+	assert.EqualValues(t, len(collector.query("", "")["foo"]), 2)
+	assert.NotContains(t, collector.query("", ""), "bar")
+	assert.EqualValues(t, collector.query("foo", "timeout"), map[string]map[string][]statistics.Diagnostic{"foo": {"timeout": {{Error: "timeout"}}}})
+	assert.Empty(t, collector.query("bar", ""))
+}
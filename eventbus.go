@@ -5,6 +5,7 @@ import (
 	"runtime/debug"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/wosai/ultron/v2/pkg/statistics"
 	"go.uber.org/zap"
@@ -29,12 +30,35 @@ type (
 		publishResult(statistics.AttackResult)
 	}
 
+	// PlanEvent 测试计划生命周期事件
+	PlanEvent struct {
+		Type      PlanEventType `json:"type"`
+		Plan      string        `json:"plan,omitempty"`
+		Stage     int           `json:"stage"`
+		SlaveID   string        `json:"slave_id,omitempty"`
+		CreatedAt time.Time     `json:"created_at"`
+	}
+
+	// PlanEventType 测试计划生命周期事件类型
+	PlanEventType string
+
+	// PlanEventHandleFunc 测试计划生命周期事件处理函数
+	PlanEventHandleFunc func(context.Context, PlanEvent)
+
+	// planEventBus 测试计划生命周期事件总线
+	planEventBus interface {
+		subscribePlanEvent(PlanEventHandleFunc)
+		publishPlanEvent(PlanEvent)
+	}
+
 	eventbus struct {
 		cancel                context.CancelFunc
 		reportBus             chan statistics.SummaryReport
 		resultBuses           []chan statistics.AttackResult
+		planEventBus          chan PlanEvent
 		reportHandlers        []ReportHandleFunc
 		resultHandlers        []ResultHandleFunc
+		planEventHandlers     []PlanEventHandleFunc
 		numberOfSubchannels   uint32
 		counterForSubchannels uint32
 		closed                uint32
@@ -43,17 +67,29 @@ type (
 	}
 )
 
+const (
+	EventPlanStarted     PlanEventType = "plan-started"
+	EventStageStarted    PlanEventType = "stage-started"
+	EventPlanFinished    PlanEventType = "plan-finished"
+	EventPlanInterrupted PlanEventType = "plan-interrupted"
+	EventSlaveJoined     PlanEventType = "slave-joined"
+	EventSlaveLeft       PlanEventType = "slave-left"
+)
+
 var (
 	defaultEventBus *eventbus
-	_               reportBus = (*eventbus)(nil)
-	_               resultBus = (*eventbus)(nil)
+	_               reportBus    = (*eventbus)(nil)
+	_               resultBus    = (*eventbus)(nil)
+	_               planEventBus = (*eventbus)(nil)
 )
 
 func newEventBus() *eventbus {
 	bus := &eventbus{
 		reportBus:           make(chan statistics.SummaryReport, 3), // 低频通道
+		planEventBus:        make(chan PlanEvent, 100),
 		reportHandlers:      make([]ReportHandleFunc, 0),
 		resultHandlers:      make([]ResultHandleFunc, 0),
+		planEventHandlers:   make([]PlanEventHandleFunc, 0),
 		numberOfSubchannels: 30,
 	}
 	bus.resultBuses = make([]chan statistics.AttackResult, bus.numberOfSubchannels)
@@ -90,6 +126,27 @@ func (bus *eventbus) publishResult(ret statistics.AttackResult) {
 	}
 }
 
+func (bus *eventbus) subscribePlanEvent(fn PlanEventHandleFunc) {
+	if fn == nil {
+		return
+	}
+	bus.planEventHandlers = append(bus.planEventHandlers, fn)
+}
+
+// publishPlanEvent 生命周期事件不应阻塞调度，通道已满时丢弃
+func (bus *eventbus) publishPlanEvent(event PlanEvent) {
+	if atomic.LoadUint32(&bus.closed) == 0 {
+		if event.CreatedAt.IsZero() {
+			event.CreatedAt = time.Now()
+		}
+		select {
+		case bus.planEventBus <- event:
+		default:
+			Logger.Warn("plan event bus is full, dropped the event", zap.Any("event", event))
+		}
+	}
+}
+
 func (bus *eventbus) start() {
 	bus.once.Do(func() {
 		ctx, cancel := context.WithCancel(context.Background())
@@ -113,6 +170,24 @@ func (bus *eventbus) start() {
 			}
 		}()
 
+		bus.wg.Add(1)
+		go func() {
+			defer func() {
+				if rec := recover(); rec != nil {
+					debug.PrintStack()
+					Logger.DPanic("plan event bus is quit", zap.Any("recover", rec))
+				}
+				bus.wg.Done()
+				bus.close()
+			}()
+
+			for event := range bus.planEventBus {
+				for _, fn := range bus.planEventHandlers {
+					fn(ctx, event)
+				}
+			}
+		}()
+
 		for _, sub := range bus.resultBuses {
 			bus.wg.Add(1)
 			go func(c <-chan statistics.AttackResult) {
@@ -142,6 +217,7 @@ func (bus *eventbus) close() {
 		}
 
 		close(bus.reportBus)
+		close(bus.planEventBus)
 		for _, sub := range bus.resultBuses {
 			close(sub)
 		}
@@ -58,3 +58,19 @@ func TestEventBus_result(t *testing.T) {
 	current := atomic.LoadInt32(&called)
 	assert.EqualValues(t, current, 1)
 }
+
+func TestEventBus_planEvent(t *testing.T) {
+	eb := newEventBus()
+	eb.start()
+
+	var received PlanEvent
+	eb.subscribePlanEvent(func(c context.Context, event PlanEvent) {
+		received = event
+	})
+	eb.publishPlanEvent(PlanEvent{Type: EventSlaveJoined, SlaveID: "unittest"})
+	eb.close()
+
+	assert.EqualValues(t, received.Type, EventSlaveJoined)
+	assert.EqualValues(t, received.SlaveID, "unittest")
+	assert.False(t, received.CreatedAt.IsZero())
+}
@@ -0,0 +1,177 @@
+package ultron
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/wosai/ultron/v2/pkg/genproto"
+	"go.uber.org/zap"
+)
+
+type (
+	// execution slave上当前测试计划的执行信息，由slaveRunner在每个阶段开始前更新
+	execution struct {
+		plan       string
+		slave      string
+		stage      int32
+		slaveIndex uint32
+		slaveCount uint32       // 为0时master尚未分配slave序号
+		limiters   atomic.Value // *rateLimiters，该slave分到的请求速率上限
+		assertions atomic.Value // map[string][]HTTPCheckFunc，测试计划中按Attacker名称声明的断言
+		slots      userSlots    // 该slave上所有场景共用的用户编号
+	}
+
+	// userSlots 分配slave内的用户编号，默认场景与命名场景的用户从同一空间分配，避免编号重复
+	userSlots struct {
+		used       map[uint32]struct{}
+		lowestFree uint32 // 可能空闲的最小编号
+		mu         sync.Mutex
+	}
+
+	// virtualUser 虚拟用户的执行信息
+	virtualUser struct {
+		id        uint32 // 全局编号
+		iteration uint64 // 已开始执行的次数
+	}
+
+	executionKey   struct{}
+	virtualUserKey struct{}
+)
+
+func newExecution(plan, slave string) *execution {
+	return &execution{plan: plan, slave: slave}
+}
+
+func (e *execution) update(dto *genproto.ExecutionDTO) {
+	if dto == nil {
+		return
+	}
+	atomic.StoreInt32(&e.stage, dto.GetStageIndex())
+	if dto.GetSlaveCount() > 0 {
+		if prev := atomic.LoadUint32(&e.slaveCount); prev > 0 && prev != dto.GetSlaveCount() && e.slots.inUse() > 0 {
+			Logger.Warn("the slave count changed while virtual users are alive, their ids may be duplicated",
+				zap.Uint32("prev", prev), zap.Uint32("current", dto.GetSlaveCount()))
+		}
+		atomic.StoreUint32(&e.slaveIndex, dto.GetSlaveIndex())
+		atomic.StoreUint32(&e.slaveCount, dto.GetSlaveCount())
+	}
+}
+
+// setRateLimits 每个阶段开始前更新，为空时不限流
+func (e *execution) setRateLimits(limits []*genproto.RateLimitDTO) {
+	prev, _ := e.limiters.Load().(*rateLimiters)
+	e.limiters.Store(newRateLimiters(limits, prev))
+}
+
+// setAssertions 每个阶段开始前更新，转换失败时不执行断言
+func (e *execution) setAssertions(dtos []*genproto.AssertionDTO) error {
+	checks, err := newHTTPChecksFromDTO(dtos)
+	e.assertions.Store(checks)
+	return err
+}
+
+// acquire 分配最小的空闲编号，使存活的虚拟用户编号尽量连续
+func (us *userSlots) acquire() uint32 {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+	if us.used == nil {
+		us.used = make(map[uint32]struct{})
+	}
+	for {
+		if _, ok := us.used[us.lowestFree]; !ok {
+			us.used[us.lowestFree] = struct{}{}
+			return us.lowestFree
+		}
+		us.lowestFree++
+	}
+}
+
+// inUse 存活的虚拟用户数量
+func (us *userSlots) inUse() int {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+	return len(us.used)
+}
+
+func (us *userSlots) release(slot uint32) {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+	delete(us.used, slot)
+	if slot < us.lowestFree {
+		us.lowestFree = slot
+	}
+}
+
+// slice master分配的slave序号与slave数量，尚未分配时slave数量为0
+func (e *execution) slice() [2]uint32 {
+	return [2]uint32{atomic.LoadUint32(&e.slaveIndex), atomic.LoadUint32(&e.slaveCount)}
+}
+
+// globalUserID 将slave内的用户编号转换为全局编号，各个slave按序号交错编号，slave数量不变时全局唯一
+func (e *execution) globalUserID(local uint32) uint32 {
+	s := e.slice()
+	if s[1] == 0 {
+		return local
+	}
+	return local*s[1] + s[0]
+}
+
+func withExecution(ctx context.Context, e *execution) context.Context {
+	return context.WithValue(ctx, executionKey{}, e)
+}
+
+func executionFrom(ctx context.Context) (*execution, bool) {
+	e, ok := ctx.Value(executionKey{}).(*execution)
+	return e, ok && e != nil
+}
+
+func withVirtualUser(ctx context.Context, vu *virtualUser) context.Context {
+	return context.WithValue(ctx, virtualUserKey{}, vu)
+}
+
+func virtualUserFrom(ctx context.Context) (*virtualUser, bool) {
+	vu, ok := ctx.Value(virtualUserKey{}).(*virtualUser)
+	return vu, ok && vu != nil
+}
+
+// PlanName 当前执行的测试计划名称
+func PlanName(ctx context.Context) (string, bool) {
+	if e, ok := executionFrom(ctx); ok {
+		return e.plan, true
+	}
+	return "", false
+}
+
+// SlaveID 当前slave的ID
+func SlaveID(ctx context.Context) (string, bool) {
+	if e, ok := executionFrom(ctx); ok {
+		return e.slave, true
+	}
+	return "", false
+}
+
+// StageIndex 当前执行的阶段序号，从0开始
+func StageIndex(ctx context.Context) (int, bool) {
+	if e, ok := executionFrom(ctx); ok {
+		return int(atomic.LoadInt32(&e.stage)), true
+	}
+	return 0, false
+}
+
+// VirtualUserID 虚拟用户的全局编号，从0开始，创建时确定；slave数量不变时存活的虚拟用户之间编号不重复，可用于切分测试数据。
+// 阶段之间slave数量变化时，之前阶段创建的虚拟用户保留原编号，可能与新创建的虚拟用户重复
+func VirtualUserID(ctx context.Context) (uint32, bool) {
+	if vu, ok := virtualUserFrom(ctx); ok {
+		return vu.id, true
+	}
+	return 0, false
+}
+
+// Iteration 当前虚拟用户第几次执行Fire，从0开始
+func Iteration(ctx context.Context) (uint64, bool) {
+	if vu, ok := virtualUserFrom(ctx); ok {
+		return atomic.LoadUint64(&vu.iteration), true
+	}
+	return 0, false
+}
@@ -0,0 +1,77 @@
+package ultron
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wosai/ultron/v2/pkg/genproto"
+)
+
+func TestExecution_Accessors(t *testing.T) {
+	_, ok := PlanName(context.Background())
+	assert.False(t, ok)
+	_, ok = VirtualUserID(context.Background())
+	assert.False(t, ok)
+
+	e := newExecution("plan-1", "slave-1")
+	e.update(&genproto.ExecutionDTO{StageIndex: 2, SlaveIndex: 1, SlaveCount: 3})
+	ctx := withExecution(context.Background(), e)
+	ctx = withVirtualUser(ctx, &virtualUser{id: e.globalUserID(4), iteration: 7})
+
+	plan, ok := PlanName(ctx)
+	assert.True(t, ok)
+	assert.EqualValues(t, "plan-1", plan)
+	slave, ok := SlaveID(ctx)
+	assert.True(t, ok)
+	assert.EqualValues(t, "slave-1", slave)
+	stage, ok := StageIndex(ctx)
+	assert.True(t, ok)
+	assert.EqualValues(t, 2, stage)
+	id, ok := VirtualUserID(ctx)
+	assert.True(t, ok)
+	assert.EqualValues(t, 13, id)
+	iteration, ok := Iteration(ctx)
+	assert.True(t, ok)
+	assert.EqualValues(t, 7, iteration)
+
+	// 透过executorSharedContext读取
+	id, ok = VirtualUserID(newExecutorSharedContext(ctx))
+	assert.True(t, ok)
+	assert.EqualValues(t, 13, id)
+}
+
+func TestExecution_GlobalUserID(t *testing.T) {
+	e := newExecution("", "")
+	assert.EqualValues(t, 5, e.globalUserID(5))
+
+	// 3个slave分别有4、3、3个用户时，全局编号为0~9且不重复
+	seen := make(map[uint32]struct{})
+	for index, users := range []uint32{4, 3, 3} {
+		e.update(&genproto.ExecutionDTO{SlaveIndex: uint32(index), SlaveCount: 3})
+		for local := uint32(0); local < users; local++ {
+			seen[e.globalUserID(local)] = struct{}{}
+		}
+	}
+	assert.Len(t, seen, 10)
+	for i := uint32(0); i < 10; i++ {
+		assert.Contains(t, seen, i)
+	}
+
+	e.update(&genproto.ExecutionDTO{StageIndex: 1})
+	assert.EqualValues(t, 1, e.stage)
+	assert.EqualValues(t, 3, e.slaveCount) // 未携带slave信息时保持不变
+}
+
+func TestUserSlots(t *testing.T) {
+	var us userSlots
+	assert.EqualValues(t, 0, us.acquire())
+	assert.EqualValues(t, 1, us.acquire())
+	assert.EqualValues(t, 2, us.acquire())
+	assert.EqualValues(t, 3, us.inUse())
+
+	us.release(1)
+	assert.EqualValues(t, 2, us.inUse())
+	assert.EqualValues(t, 1, us.acquire())
+	assert.EqualValues(t, 3, us.acquire())
+}
@@ -0,0 +1,275 @@
+package ultron
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
+)
+
+type (
+	// Record 一条测试数据
+	Record map[string]interface{}
+
+	// FeedMode 测试数据的读取方式
+	FeedMode int
+
+	// FeederSource 加载全部测试数据
+	FeederSource func() ([]Record, error)
+
+	// Feeder 为Attacker提供测试数据，并发安全
+	Feeder interface {
+		Name() string
+		Next(context.Context) (Record, error)
+	}
+
+	// FeederOption Feeder的配置项
+	FeederOption func(*feeder)
+
+	feeder struct {
+		name      string
+		mode      FeedMode
+		source    FeederSource
+		partition bool
+		all       []Record         // 加载的全部数据
+		records   []int            // 当前slave可以使用的数据在all中的序号
+		used      map[int]struct{} // 本测试计划内已读取的数据，重新切分时跳过，仅FeedSequential、FeedUniqueOnce使用
+		exec      *execution       // records对应的执行信息，测试计划变化时重新切分
+		slice     [2]uint32        // records对应的slave序号与slave数量，每个阶段变化时重新切分
+		loaded    bool
+		cursor    int
+		mu        sync.Mutex
+	}
+)
+
+const (
+	FeedSequential FeedMode = iota // 按顺序读取，读完后返回ErrFeederExhausted
+	FeedCircular                   // 按顺序读取，读完后从头开始
+	FeedRandom                     // 随机读取，可能重复
+	FeedUniqueOnce                 // 打乱后读取，每条数据在集群内只使用一次，读完后返回ErrFeederExhausted
+)
+
+var (
+	ErrFeederExhausted = errors.New("feeder is exhausted")
+	ErrEmptyFeeder     = errors.New("feeder has no records")
+)
+
+var _ Feeder = (*feeder)(nil)
+
+// NewFeeder 创建Feeder，数据在第一次读取时加载；FeedUniqueOnce默认按slave切分数据
+func NewFeeder(name string, source FeederSource, mode FeedMode, opts ...FeederOption) Feeder {
+	if name == "" {
+		panic("feeder name cannot be empty")
+	}
+	if source == nil {
+		panic("invalid feeder source")
+	}
+	f := &feeder{
+		name:      name,
+		mode:      mode,
+		source:    source,
+		partition: mode == FeedUniqueOnce,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// WithPartition 是否按slave切分数据：slave只使用序号 % slave数量 == slave序号 的数据，切分方式由master每个阶段分配的slave序号决定；
+// 尚未分配序号的slave不读取数据。各个slave只跳过自己读取过的数据，slave数量在阶段之间变化时，不保证数据在集群内不重复或不遗漏
+func WithPartition(partition bool) FeederOption {
+	return func(f *feeder) {
+		f.partition = partition
+	}
+}
+
+// FromRecords 内存中的测试数据
+func FromRecords(records ...Record) FeederSource {
+	return func() ([]Record, error) {
+		return records, nil
+	}
+}
+
+// FromCSV 读取CSV文件，首行为表头
+func FromCSV(path string) FeederSource {
+	return func() ([]Record, error) {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return readCSVRecords(file)
+	}
+}
+
+// FromJSONLines 读取JSON Lines文件，每行为一个JSON对象，忽略空行
+func FromJSONLines(path string) FeederSource {
+	return func() ([]Record, error) {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return readJSONLinesRecords(file)
+	}
+}
+
+func readCSVRecords(r io.Reader) ([]Record, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var records []Record
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		record := make(Record, len(header))
+		for i, key := range header {
+			record[key] = row[i]
+		}
+		records = append(records, record)
+	}
+}
+
+func readJSONLinesRecords(r io.Reader) ([]Record, error) {
+	var records []Record
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		record := make(Record)
+		if err := json.Unmarshal([]byte(text), &record); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// String 读取字段，非字符串的值按fmt.Sprint格式化，字段不存在时返回空字符串
+func (r Record) String(key string) string {
+	v, ok := r[key]
+	if !ok || v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+func (f *feeder) Name() string {
+	return f.name
+}
+
+func (f *feeder) Next(ctx context.Context) (Record, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.prepare(ctx); err != nil {
+		return nil, err
+	}
+	if len(f.records) == 0 {
+		return nil, ErrEmptyFeeder
+	}
+
+	switch f.mode {
+	case FeedRandom:
+		return f.all[f.records[rand.Intn(len(f.records))]], nil
+
+	case FeedCircular:
+		record := f.all[f.records[f.cursor%len(f.records)]]
+		f.cursor = (f.cursor + 1) % len(f.records)
+		return record, nil
+
+	default: // FeedSequential, FeedUniqueOnce
+		if f.cursor >= len(f.records) {
+			return nil, ErrFeederExhausted
+		}
+		i := f.records[f.cursor]
+		f.cursor++
+		f.used[i] = struct{}{}
+		return f.all[i], nil
+	}
+}
+
+// prepare 加载数据，测试计划变化时重新读取，slave序号或数量变化时跳过已读取的数据重新切分，调用方需持有锁
+func (f *feeder) prepare(ctx context.Context) error {
+	if !f.loaded {
+		all, err := f.source()
+		if err != nil {
+			return fmt.Errorf("failed to load feeder %s: %w", f.name, err)
+		}
+		f.all = all
+		f.loaded = true
+		f.reset(nil)
+	}
+	exec, ok := executionFrom(ctx)
+	if !ok {
+		return nil
+	}
+	if exec != f.exec {
+		f.reset(exec)
+	} else if f.partition && f.slice != exec.slice() {
+		f.repartition()
+	}
+	return nil
+}
+
+func (f *feeder) reset(exec *execution) {
+	f.exec = exec
+	f.used = make(map[int]struct{})
+	f.repartition()
+}
+
+func (f *feeder) repartition() {
+	var index, count uint32 = 0, 1
+	if f.partition && f.exec != nil {
+		f.slice = f.exec.slice()
+		index, count = f.slice[0], f.slice[1]
+	}
+	f.cursor = 0
+	f.records = f.records[:0]
+	for i := int(index); count > 0 && i < len(f.all); i += int(count) { // 尚未分配slave序号时不读取数据
+		if _, ok := f.used[i]; !ok {
+			f.records = append(f.records, i)
+		}
+	}
+	if f.mode == FeedUniqueOnce {
+		rand.Shuffle(len(f.records), func(i, j int) { f.records[i], f.records[j] = f.records[j], f.records[i] })
+	}
+}
+
+// FeedUser 为当前虚拟用户读取一条数据，并在虚拟用户的整个生命周期内复用，适用于账号等数据
+func FeedUser(ctx context.Context, f Feeder) (Record, error) {
+	key := "feeder:" + f.Name()
+	if v, ok := UserValue(ctx, key); ok {
+		return v.(Record), nil
+	}
+	record, err := f.Next(ctx)
+	if err != nil {
+		return nil, err
+	}
+	StoreUserValue(ctx, key, record)
+	return record, nil
+}
@@ -0,0 +1,156 @@
+package ultron
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wosai/ultron/v2/pkg/genproto"
+)
+
+func newTestRecords(n int) []Record {
+	records := make([]Record, n)
+	for i := range records {
+		records[i] = Record{"id": i}
+	}
+	return records
+}
+
+func TestFeeder_Modes(t *testing.T) {
+	ctx := context.Background()
+
+	sequential := NewFeeder("sequential", FromRecords(newTestRecords(3)...), FeedSequential)
+	for i := 0; i < 3; i++ {
+		r, err := sequential.Next(ctx)
+		assert.Nil(t, err)
+		assert.EqualValues(t, i, r["id"])
+	}
+	_, err := sequential.Next(ctx)
+	assert.ErrorIs(t, err, ErrFeederExhausted)
+
+	circular := NewFeeder("circular", FromRecords(newTestRecords(2)...), FeedCircular)
+	for i := 0; i < 5; i++ {
+		r, err := circular.Next(ctx)
+		assert.Nil(t, err)
+		assert.EqualValues(t, i%2, r["id"])
+	}
+
+	random := NewFeeder("random", FromRecords(newTestRecords(3)...), FeedRandom)
+	for i := 0; i < 10; i++ {
+		r, err := random.Next(ctx)
+		assert.Nil(t, err)
+		assert.Contains(t, []interface{}{0, 1, 2}, r["id"])
+	}
+
+	unique := NewFeeder("unique", FromRecords(newTestRecords(10)...), FeedUniqueOnce)
+	seen := make(map[interface{}]struct{})
+	for i := 0; i < 10; i++ {
+		r, err := unique.Next(ctx)
+		assert.Nil(t, err)
+		seen[r["id"]] = struct{}{}
+	}
+	assert.Len(t, seen, 10)
+	_, err = unique.Next(ctx)
+	assert.ErrorIs(t, err, ErrFeederExhausted)
+
+	_, err = NewFeeder("empty", FromRecords(), FeedCircular).Next(ctx)
+	assert.ErrorIs(t, err, ErrEmptyFeeder)
+}
+
+func TestFeeder_Partition(t *testing.T) {
+	records := newTestRecords(10)
+	seen := make(map[interface{}]int)
+	for index := uint32(0); index < 3; index++ {
+		exec := newExecution("plan", "slave")
+		exec.update(&genproto.ExecutionDTO{SlaveIndex: index, SlaveCount: 3})
+		ctx := withExecution(context.Background(), exec)
+
+		f := NewFeeder("accounts", FromRecords(records...), FeedUniqueOnce)
+		for {
+			r, err := f.Next(ctx)
+			if err != nil {
+				assert.ErrorIs(t, err, ErrFeederExhausted)
+				break
+			}
+			seen[r["id"]]++
+		}
+	}
+	assert.Len(t, seen, 10)
+	for _, n := range seen {
+		assert.EqualValues(t, 1, n)
+	}
+
+	// 新的测试计划重新切分
+	f := NewFeeder("accounts", FromRecords(records...), FeedSequential, WithPartition(true))
+	exec := newExecution("plan-1", "slave")
+	exec.update(&genproto.ExecutionDTO{SlaveIndex: 1, SlaveCount: 2})
+	r, _ := f.Next(withExecution(context.Background(), exec))
+	assert.EqualValues(t, 1, r["id"])
+	r, _ = f.Next(withExecution(context.Background(), exec))
+	assert.EqualValues(t, 3, r["id"])
+
+	// 阶段之间slave数量变化时重新切分，跳过已读取的数据
+	exec.update(&genproto.ExecutionDTO{StageIndex: 1, SlaveIndex: 0, SlaveCount: 1})
+	var ids []interface{}
+	for {
+		r, err := f.Next(withExecution(context.Background(), exec))
+		if err != nil {
+			assert.ErrorIs(t, err, ErrFeederExhausted)
+			break
+		}
+		ids = append(ids, r["id"])
+	}
+	assert.EqualValues(t, []interface{}{0, 2, 4, 5, 6, 7, 8, 9}, ids)
+
+	// 尚未分配slave序号时不读取数据
+	_, err := f.Next(withExecution(context.Background(), newExecution("plan-2", "slave")))
+	assert.ErrorIs(t, err, ErrEmptyFeeder)
+	r, err = NewFeeder("all", FromRecords(records...), FeedSequential).Next(withExecution(context.Background(), newExecution("plan-2", "slave")))
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, r["id"])
+}
+
+func TestFeeder_Files(t *testing.T) {
+	dir := t.TempDir()
+	csvFile := filepath.Join(dir, "users.csv")
+	assert.Nil(t, os.WriteFile(csvFile, []byte("name,password\nalice,a1\nbob,b2\n"), 0644))
+	jsonlFile := filepath.Join(dir, "users.jsonl")
+	assert.Nil(t, os.WriteFile(jsonlFile, []byte("{\"name\":\"alice\",\"age\":18}\n\n{\"name\":\"bob\"}\n"), 0644))
+
+	f := NewFeeder("csv", FromCSV(csvFile), FeedSequential)
+	r, err := f.Next(context.Background())
+	assert.Nil(t, err)
+	assert.EqualValues(t, Record{"name": "alice", "password": "a1"}, r)
+
+	records, err := FromJSONLines(jsonlFile)()
+	assert.Nil(t, err)
+	assert.Len(t, records, 2)
+	assert.EqualValues(t, "18", records[0].String("age"))
+	assert.EqualValues(t, "", records[1].String("age"))
+
+	assert.Nil(t, os.WriteFile(jsonlFile, []byte("{\"name\":\"alice\"}\nnot json\n"), 0644))
+	_, err = FromJSONLines(jsonlFile)()
+	assert.ErrorContains(t, err, "line 2")
+
+	_, err = NewFeeder("missing", FromCSV(filepath.Join(dir, "missing.csv")), FeedSequential).Next(context.Background())
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestFeedUser(t *testing.T) {
+	f := NewFeeder("accounts", FromRecords(newTestRecords(5)...), FeedSequential)
+	user1 := newExecutorSharedContext(context.Background())
+	user2 := newExecutorSharedContext(context.Background())
+
+	r1, err := FeedUser(user1, f)
+	assert.Nil(t, err)
+	r2, err := FeedUser(user2, f)
+	assert.Nil(t, err)
+	again, err := FeedUser(AllocateStorageInContext(user1), f)
+	assert.Nil(t, err)
+
+	assert.EqualValues(t, 0, r1["id"])
+	assert.EqualValues(t, 1, r2["id"])
+	assert.EqualValues(t, r1, again)
+}
@@ -172,23 +172,24 @@ func (hdl *InfluxDBV1Handler) HandleReport() ultron.ReportHandleFunc {
 				hdl.measurementReport,
 				tags,
 				map[string]interface{}{
-					"tps":           report.TPS,
-					"successes":     int64(report.Requests),
-					"failures":      int64(report.Failures),
-					"failure_ratio": report.FailureRatio,
-					"min":           report.Min.Milliseconds(),
-					"max":           report.Max.Milliseconds(),
-					"avg":           report.Average.Milliseconds(),
-					"TP50":          report.Distributions["0.50"].Milliseconds(),
-					"TP60":          report.Distributions["0.60"].Milliseconds(),
-					"TP70":          report.Distributions["0.70"].Milliseconds(),
-					"TP80":          report.Distributions["0.80"].Milliseconds(),
-					"TP90":          report.Distributions["0.90"].Milliseconds(),
-					"TP95":          report.Distributions["0.95"].Milliseconds(),
-					"TP96":          report.Distributions["0.96"].Milliseconds(),
-					"TP97":          report.Distributions["0.97"].Milliseconds(),
-					"TP98":          report.Distributions["0.98"].Milliseconds(),
-					"TP99":          report.Distributions["0.99"].Milliseconds(),
+					"tps":                     report.TPS,
+					"successes":               int64(report.Requests),
+					"failures":                int64(report.Failures),
+					"failure_ratio":           report.FailureRatio,
+					"min":                     report.Min.Milliseconds(),
+					"max":                     report.Max.Milliseconds(),
+					"avg":                     report.Average.Milliseconds(),
+					"TP50":                    report.Distributions["0.50"].Milliseconds(),
+					"TP60":                    report.Distributions["0.60"].Milliseconds(),
+					"TP70":                    report.Distributions["0.70"].Milliseconds(),
+					"TP80":                    report.Distributions["0.80"].Milliseconds(),
+					"TP90":                    report.Distributions["0.90"].Milliseconds(),
+					"TP95":                    report.Distributions["0.95"].Milliseconds(),
+					"TP96":                    report.Distributions["0.96"].Milliseconds(),
+					"TP97":                    report.Distributions["0.97"].Milliseconds(),
+					"TP98":                    report.Distributions["0.98"].Milliseconds(),
+					"TP99":                    report.Distributions["0.99"].Milliseconds(),
+					ultron.KeyConcurrentUsers: int64(sr.ConcurrentUsers),
 				},
 				now,
 			)
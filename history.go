@@ -0,0 +1,196 @@
+package ultron
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/wosai/ultron/v2/pkg/statistics"
+)
+
+type (
+	// ReportPoint 报告时间序列中的一个点
+	ReportPoint struct {
+		At              time.Time                          `json:"at"`
+		Stage           string                             `json:"stage,omitempty"`
+		ConcurrentUsers int                                `json:"concurrent_users"`
+		TotalRequests   uint64                             `json:"total_requests"`
+		TotalFailures   uint64                             `json:"total_failures"`
+		TotalTPS        float64                            `json:"total_tps"`
+		FullHistory     bool                               `json:"full_history,omitempty"` // 测试计划结束时的完整报告
+		Reports         map[string]statistics.AttackReport `json:"reports,omitempty"`
+	}
+
+	// PlanHistory 单个测试计划的报告历史
+	PlanHistory struct {
+		Plan       string        `json:"plan"`
+		StartedAt  time.Time     `json:"started_at"`
+		FinishedAt time.Time     `json:"finished_at,omitempty"`
+		Resolution time.Duration `json:"resolution"` // 降采样后相邻两点的最小间隔，0表示未降采样
+		Points     []ReportPoint `json:"points"`
+	}
+
+	// HistoryQuery 查询条件
+	HistoryQuery struct {
+		Plan      string    // 为空时查询最近的测试计划
+		Since     time.Time // 只返回该时间之后的点
+		Attackers []string  // 只返回指定attacker的报告，为空时返回全部
+	}
+
+	// reportHistory master侧保留的有界报告历史
+	reportHistory struct {
+		plans     []*PlanHistory // 按开始时间升序
+		maxPlans  int
+		maxPoints int
+		mu        sync.RWMutex
+	}
+)
+
+var (
+	ErrHistoryNotFound = errors.New("cannot find report history of the plan")
+)
+
+func newReportHistory(maxPlans, maxPoints int) *reportHistory {
+	if maxPlans <= 0 {
+		maxPlans = 1
+	}
+	if maxPoints < 2 {
+		maxPoints = 2
+	}
+	return &reportHistory{
+		plans:     make([]*PlanHistory, 0, maxPlans),
+		maxPlans:  maxPlans,
+		maxPoints: maxPoints,
+	}
+}
+
+func newReportPoint(report statistics.SummaryReport, at time.Time) ReportPoint {
+	point := ReportPoint{
+		At:              at,
+		Stage:           report.Extras[KeyStage],
+		TotalRequests:   report.TotalRequests,
+		TotalFailures:   report.TotalFailures,
+		TotalTPS:        report.TotalTPS,
+		FullHistory:     report.FullHistory,
+		Reports:         make(map[string]statistics.AttackReport, len(report.Reports)),
+		ConcurrentUsers: report.ConcurrentUsers,
+	}
+	for name, r := range report.Reports {
+		r.Diagnostics = nil // 诊断信息只保留最新的
+		point.Reports[name] = r
+	}
+	return point
+}
+
+func (rh *reportHistory) handleReport() ReportHandleFunc {
+	return func(_ context.Context, report statistics.SummaryReport) {
+		rh.record(report, time.Now())
+	}
+}
+
+func (rh *reportHistory) record(report statistics.SummaryReport, at time.Time) {
+	rh.mu.Lock()
+	defer rh.mu.Unlock()
+
+	plan := report.Extras[KeyPlan]
+	var current *PlanHistory
+	if n := len(rh.plans); n > 0 {
+		current = rh.plans[n-1]
+	}
+	if current == nil || current.Plan != plan || !current.FinishedAt.IsZero() { // 新的测试计划
+		current = &PlanHistory{Plan: plan, StartedAt: at, Points: make([]ReportPoint, 0)}
+		rh.plans = append(rh.plans, current)
+		if len(rh.plans) > rh.maxPlans {
+			rh.plans = rh.plans[len(rh.plans)-rh.maxPlans:]
+		}
+	}
+
+	point := newReportPoint(report, at)
+	if report.FullHistory {
+		current.FinishedAt = at
+		current.Points = append(current.Points, point) // 完整报告总是保留
+		return
+	}
+	if n := len(current.Points); n > 0 && at.Sub(current.Points[n-1].At) < current.Resolution {
+		return
+	}
+	current.Points = append(current.Points, point)
+	if len(current.Points) > rh.maxPoints {
+		current.downsample()
+	}
+}
+
+// downsample 隔点抽样，保留首尾两个点，分辨率翻倍
+func (ph *PlanHistory) downsample() {
+	n := len(ph.Points)
+	if n < 3 {
+		return
+	}
+	last := ph.Points[n-1]
+	kept := ph.Points[:0]
+	for i := 0; i < n-1; i += 2 {
+		kept = append(kept, ph.Points[i])
+	}
+	kept = append(kept, last)
+	ph.Points = kept
+
+	if ph.Resolution == 0 {
+		ph.Resolution = ph.Points[1].At.Sub(ph.Points[0].At)
+	} else {
+		ph.Resolution *= 2
+	}
+}
+
+// list 返回保留的测试计划，不包含时间序列
+func (rh *reportHistory) list() []PlanHistory {
+	rh.mu.RLock()
+	defer rh.mu.RUnlock()
+
+	ret := make([]PlanHistory, len(rh.plans))
+	for i, ph := range rh.plans {
+		ret[i] = PlanHistory{Plan: ph.Plan, StartedAt: ph.StartedAt, FinishedAt: ph.FinishedAt, Resolution: ph.Resolution}
+	}
+	return ret
+}
+
+// query 按条件返回测试计划的时间序列，同名计划优先返回最近的一个
+func (rh *reportHistory) query(q HistoryQuery) (*PlanHistory, error) {
+	rh.mu.RLock()
+	defer rh.mu.RUnlock()
+
+	var found *PlanHistory
+	for i := len(rh.plans) - 1; i >= 0; i-- {
+		if q.Plan == "" || rh.plans[i].Plan == q.Plan {
+			found = rh.plans[i]
+			break
+		}
+	}
+	if found == nil {
+		return nil, ErrHistoryNotFound
+	}
+
+	ret := &PlanHistory{
+		Plan:       found.Plan,
+		StartedAt:  found.StartedAt,
+		FinishedAt: found.FinishedAt,
+		Resolution: found.Resolution,
+		Points:     make([]ReportPoint, 0, len(found.Points)),
+	}
+	for _, point := range found.Points {
+		if !q.Since.IsZero() && !point.At.After(q.Since) {
+			continue
+		}
+		if len(q.Attackers) > 0 {
+			reports := make(map[string]statistics.AttackReport)
+			for _, name := range q.Attackers {
+				if r, ok := point.Reports[name]; ok {
+					reports[name] = r
+				}
+			}
+			point.Reports = reports
+		}
+		ret.Points = append(ret.Points, point)
+	}
+	return ret, nil
+}
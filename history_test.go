@@ -0,0 +1,87 @@
+package ultron
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wosai/ultron/v2/pkg/statistics"
+)
+
+func newHistoryTestReport(plan string, stage int, requests uint64, full bool) statistics.SummaryReport {
+	return statistics.SummaryReport{
+		TotalRequests: requests,
+		FullHistory:   full,
+		Reports: map[string]statistics.AttackReport{
+			"foo": {Name: "foo", Requests: requests / 2},
+			"bar": {Name: "bar", Requests: requests - requests/2},
+		},
+		Extras:          map[string]string{KeyPlan: plan, KeyStage: strconv.Itoa(stage)},
+		ConcurrentUsers: 100,
+	}
+}
+
+func TestReportHistory_Record(t *testing.T) {
+	history := newReportHistory(2, 100)
+	begin := time.Now()
+	history.record(newHistoryTestReport("first", 0, 10, false), begin)
+	history.record(newHistoryTestReport("first", 0, 20, true), begin.Add(time.Second))
+	history.record(newHistoryTestReport("first", 0, 10, false), begin.Add(2*time.Second)) // 同名的新测试计划
+	history.record(newHistoryTestReport("second", 0, 10, false), begin.Add(3*time.Second))
+
+	plans := history.list()
+	assert.EqualValues(t, len(plans), 2)
+	assert.EqualValues(t, plans[0].Plan, "first")
+	assert.True(t, plans[0].FinishedAt.IsZero())
+	assert.EqualValues(t, plans[1].Plan, "second")
+
+	ph, err := history.query(HistoryQuery{})
+	assert.Nil(t, err)
+	assert.EqualValues(t, ph.Plan, "second")
+	assert.EqualValues(t, ph.Points[0].ConcurrentUsers, 100)
+	assert.EqualValues(t, ph.Points[0].Stage, "0")
+
+	_, err = history.query(HistoryQuery{Plan: "unknown"})
+	assert.ErrorIs(t, err, ErrHistoryNotFound)
+}
+
+func TestReportHistory_Downsample(t *testing.T) {
+	history := newReportHistory(1, 10)
+	begin := time.Now()
+	for i := 0; i < 100; i++ {
+		history.record(newHistoryTestReport("plan", 0, uint64(i), false), begin.Add(time.Duration(i)*time.Second))
+	}
+	history.record(newHistoryTestReport("plan", 0, 100, true), begin.Add(100*time.Second))
+
+	ph, err := history.query(HistoryQuery{})
+	assert.Nil(t, err)
+	assert.LessOrEqual(t, len(ph.Points), 11)
+	assert.EqualValues(t, ph.Resolution, 16*time.Second)
+	assert.EqualValues(t, ph.Points[0].At, begin)
+	assert.True(t, ph.Points[len(ph.Points)-1].FullHistory)
+	for i := 1; i < len(ph.Points); i++ {
+		assert.True(t, ph.Points[i].At.After(ph.Points[i-1].At))
+	}
+}
+
+func TestReportHistory_Query(t *testing.T) {
+	history := newReportHistory(1, 100)
+	begin := time.Now()
+	for i := 0; i < 10; i++ {
+		history.record(newHistoryTestReport("plan", i/5, uint64(i*10), false), begin.Add(time.Duration(i)*time.Second))
+	}
+
+	ph, err := history.query(HistoryQuery{Since: begin.Add(5 * time.Second), Attackers: []string{"foo", "unknown"}})
+	assert.Nil(t, err)
+	assert.EqualValues(t, len(ph.Points), 4)
+	for _, point := range ph.Points {
+		assert.EqualValues(t, len(point.Reports), 1)
+		assert.Contains(t, point.Reports, "foo")
+	}
+
+	// 过滤不影响保存的数据
+	ph, _ = history.query(HistoryQuery{})
+	assert.EqualValues(t, len(ph.Points), 10)
+	assert.EqualValues(t, len(ph.Points[9].Reports), 2)
+}
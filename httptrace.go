@@ -0,0 +1,102 @@
+package ultron
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/wosai/ultron/v2/pkg/statistics"
+)
+
+// HTTPAttacker开启追踪时统计的阶段，跟随重定向的请求各阶段耗时累加
+const (
+	HTTPPhaseDNS      = "dns"      // 域名解析
+	HTTPPhaseConnect  = "connect"  // TCP建连
+	HTTPPhaseTLS      = "tls"      // TLS握手
+	HTTPPhaseTTFB     = "ttfb"     // 请求发送完毕到收到响应首字节，即服务端处理时间
+	HTTPPhaseTransfer = "transfer" // 收到响应首字节到读完响应体
+)
+
+type (
+	// httpTracer 记录一次Fire中各阶段的耗时，连接池拨号时回调可能并发
+	httpTracer struct {
+		dnsStart     time.Time
+		connectStart time.Time // 多个地址并发拨号时取最早的开始时间
+		tlsStart     time.Time
+		wrote        time.Time
+		firstByte    time.Time
+		phases       map[string]time.Duration
+		reused       bool
+		mu           sync.Mutex
+	}
+)
+
+func newHTTPTracer() *httpTracer {
+	return &httpTracer{phases: make(map[string]time.Duration)}
+}
+
+func (ht *httpTracer) withContext(ctx context.Context) context.Context {
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { ht.start(&ht.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { ht.done(HTTPPhaseDNS, &ht.dnsStart) },
+		ConnectStart:         func(_, _ string) { ht.start(&ht.connectStart) },
+		ConnectDone:          func(_, _ string, _ error) { ht.done(HTTPPhaseConnect, &ht.connectStart) },
+		TLSHandshakeStart:    func() { ht.start(&ht.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { ht.done(HTTPPhaseTLS, &ht.tlsStart) },
+		GotConn:              ht.gotConn,
+		WroteRequest:         func(httptrace.WroteRequestInfo) { ht.start(&ht.wrote) },
+		GotFirstResponseByte: ht.gotFirstResponseByte,
+	})
+}
+
+// start 已经开始时忽略，保留最早的开始时间
+func (ht *httpTracer) start(at *time.Time) {
+	ht.mu.Lock()
+	defer ht.mu.Unlock()
+	if at.IsZero() {
+		*at = time.Now()
+	}
+}
+
+// done 累加阶段耗时并清除开始时间，没有对应的开始时忽略
+func (ht *httpTracer) done(phase string, at *time.Time) {
+	ht.mu.Lock()
+	defer ht.mu.Unlock()
+	if !at.IsZero() {
+		ht.phases[phase] += time.Since(*at)
+		*at = time.Time{}
+	}
+}
+
+func (ht *httpTracer) gotConn(info httptrace.GotConnInfo) {
+	ht.mu.Lock()
+	defer ht.mu.Unlock()
+	ht.reused = info.Reused
+}
+
+func (ht *httpTracer) gotFirstResponseByte() {
+	ht.done(HTTPPhaseTTFB, &ht.wrote)
+	ht.mu.Lock()
+	defer ht.mu.Unlock()
+	ht.firstByte = time.Now()
+}
+
+// bodyRead 读完响应体，nil时不做任何处理
+func (ht *httpTracer) bodyRead() {
+	if ht == nil {
+		return
+	}
+	ht.done(HTTPPhaseTransfer, &ht.firstByte)
+}
+
+func (ht *httpTracer) trace() *statistics.Trace {
+	ht.mu.Lock()
+	defer ht.mu.Unlock()
+	t := &statistics.Trace{Phases: make(map[string]time.Duration, len(ht.phases)), ConnReused: ht.reused}
+	for phase, d := range ht.phases {
+		t.Phases[phase] = d
+	}
+	return t
+}
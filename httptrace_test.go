@@ -0,0 +1,45 @@
+package ultron
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPAttacker_Trace(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	attacker := NewHTTPAttacker("trace", WithClient(server.Client()), WithHTTPTrace(true), WithPrepareFunc(func(context.Context) (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, server.URL, nil)
+	}))
+
+	ctx := newExecutorSharedContext(context.Background())
+	assert.Nil(t, attacker.Fire(ctx))
+	first := takeResultTrace(ctx)
+	assert.NotNil(t, first)
+	assert.False(t, first.ConnReused)
+	assert.Contains(t, first.Phases, HTTPPhaseConnect)
+	assert.Contains(t, first.Phases, HTTPPhaseTLS)
+	assert.Contains(t, first.Phases, HTTPPhaseTransfer)
+	assert.GreaterOrEqual(t, first.Phases[HTTPPhaseTTFB], 10*time.Millisecond)
+	assert.Nil(t, takeResultTrace(ctx))
+
+	assert.Nil(t, attacker.Fire(ctx))
+	second := takeResultTrace(ctx)
+	assert.NotNil(t, second)
+	assert.True(t, second.ConnReused)
+	assert.NotContains(t, second.Phases, HTTPPhaseConnect)
+	assert.NotContains(t, second.Phases, HTTPPhaseTLS)
+
+	attacker.Apply(WithHTTPTrace(false))
+	assert.Nil(t, attacker.Fire(ctx))
+	assert.Nil(t, takeResultTrace(ctx))
+}
@@ -0,0 +1,174 @@
+package ultron
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+)
+
+type (
+	// markovTask 虚拟用户按状态机执行Attacker：Attacker为状态，按转移权重随机选择下一个状态，
+	// 退出后从入口状态重新开始；配置需在执行前完成
+	markovTask struct {
+		states     map[string]*markovState
+		order      []string // 添加顺序，未设置入口权重时从第一个状态开始
+		entryTotal uint32
+		hooks      []VirtualUserHook
+		shared     markovCursor // 无法区分虚拟用户时共享的游标
+		mu         sync.Mutex   // 保护shared
+	}
+
+	markovState struct {
+		attacker    Attacker
+		entry       uint32 // 作为入口状态的权重
+		transitions []markovTransition
+		total       uint32
+	}
+
+	markovTransition struct {
+		to     string // 为空时表示退出
+		weight uint32
+	}
+
+	// markovCursor 虚拟用户当前所处的状态
+	markovCursor struct {
+		current string // 为空时从入口状态开始
+	}
+)
+
+var (
+	_ ContextTask     = (*markovTask)(nil)
+	_ VirtualUserHook = (*markovTask)(nil)
+)
+
+func NewMarkovTask() *markovTask {
+	return &markovTask{states: make(map[string]*markovState)}
+}
+
+// Add 添加状态，weight为作为入口状态的权重，为0时只能从其他状态转移进入
+func (t *markovTask) Add(a Attacker, weight uint32) {
+	if a == nil {
+		panic("invalid attacker")
+	}
+	if _, ok := t.states[a.Name()]; ok {
+		panic(fmt.Sprintf("duplicated state %s", a.Name()))
+	}
+	t.states[a.Name()] = &markovState{attacker: a, entry: weight}
+	t.order = append(t.order, a.Name())
+	t.entryTotal += weight
+}
+
+// Transition 添加状态之间的转移，同一状态的各个转移按权重占比随机选择
+func (t *markovTask) Transition(from, to string, weight uint32) *markovTask {
+	if _, ok := t.states[to]; !ok {
+		panic(fmt.Sprintf("cannot find state %s", to))
+	}
+	return t.transition(from, to, weight)
+}
+
+// Exit 以weight的权重从该状态退出，下一次从入口状态重新开始；没有任何转移的状态执行后直接退出
+func (t *markovTask) Exit(from string, weight uint32) *markovTask {
+	return t.transition(from, "", weight)
+}
+
+func (t *markovTask) transition(from, to string, weight uint32) *markovTask {
+	state, ok := t.states[from]
+	if !ok {
+		panic(fmt.Sprintf("cannot find state %s", from))
+	}
+	if weight == 0 {
+		return t
+	}
+	state.transitions = append(state.transitions, markovTransition{to: to, weight: weight})
+	state.total += weight
+	return t
+}
+
+// Hook 追加虚拟用户的生命周期钩子，实现了VirtualUserHook的Attacker无需再次添加
+func (t *markovTask) Hook(hooks ...VirtualUserHook) {
+	for _, hook := range hooks {
+		if hook == nil {
+			panic("invalid VirtualUserHook")
+		}
+	}
+	t.hooks = append(t.hooks, hooks...)
+}
+
+// PickUp 无法区分虚拟用户时，所有调用方共同沿状态机游走
+func (t *markovTask) PickUp() Attacker {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.next(&t.shared)
+}
+
+// PickUpContext 每个虚拟用户独立地沿状态机游走，游标保存在虚拟用户级别的数据中
+func (t *markovTask) PickUpContext(ctx context.Context) Attacker {
+	key := fmt.Sprintf("markov:%p", t)
+	if v, ok := UserValue(ctx, key); ok {
+		return t.next(v.(*markovCursor))
+	}
+	cursor := new(markovCursor)
+	if !StoreUserValue(ctx, key, cursor) {
+		return t.PickUp()
+	}
+	return t.next(cursor)
+}
+
+func (t *markovTask) next(cursor *markovCursor) Attacker {
+	if cursor.current != "" {
+		cursor.current = t.states[cursor.current].choose()
+	}
+	if cursor.current == "" {
+		cursor.current = t.enter()
+	}
+	return t.states[cursor.current].attacker
+}
+
+func (t *markovTask) enter() string {
+	if t.entryTotal == 0 {
+		return t.order[0]
+	}
+	r := uint32(rand.Int63n(int64(t.entryTotal)))
+	for _, name := range t.order {
+		entry := t.states[name].entry
+		if r < entry {
+			return name
+		}
+		r -= entry
+	}
+	panic("unreachable code")
+}
+
+// choose 返回下一个状态，退出时返回空字符串
+func (s *markovState) choose() string {
+	if s.total == 0 {
+		return ""
+	}
+	r := uint32(rand.Int63n(int64(s.total)))
+	for _, tr := range s.transitions {
+		if r < tr.weight {
+			return tr.to
+		}
+		r -= tr.weight
+	}
+	panic("unreachable code")
+}
+
+func (t *markovTask) allHooks() []VirtualUserHook {
+	attackers := make([]Attacker, len(t.order))
+	for i, name := range t.order {
+		attackers[i] = t.states[name].attacker
+	}
+	return collectHooks(t.hooks, attackers)
+}
+
+// OnStart 依次调用钩子，任意钩子返回error时，对已经启动成功的钩子调用OnStop
+func (t *markovTask) OnStart(ctx context.Context) error {
+	return startHooks(ctx, t.allHooks())
+}
+
+// OnStop 逆序调用钩子
+func (t *markovTask) OnStop(ctx context.Context) {
+	stopHooks(ctx, t.allHooks())
+}
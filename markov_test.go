@@ -0,0 +1,131 @@
+package ultron
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pathAttacker 记录每个虚拟用户依次执行的状态
+type pathAttacker struct {
+	name  string
+	paths map[uint32][]string
+	mu    *sync.Mutex
+}
+
+func (p *pathAttacker) Name() string {
+	return p.name
+}
+
+func (p *pathAttacker) Fire(ctx context.Context) error {
+	id, _ := VirtualUserID(ctx)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paths[id] = append(p.paths[id], p.name)
+	return nil
+}
+
+func newCheckoutFlow() *markovTask {
+	task := NewMarkovTask()
+	task.Add(NewHTTPAttacker("login"), 1)
+	task.Add(NewHTTPAttacker("browse"), 0)
+	task.Add(NewHTTPAttacker("checkout"), 0)
+	task.Transition("login", "browse", 1).Transition("browse", "checkout", 1).Exit("checkout", 1)
+	return task
+}
+
+func TestMarkovTask_PickUpContext(t *testing.T) {
+	task := newCheckoutFlow()
+	u1 := newExecutorSharedContext(context.Background())
+	u2 := newExecutorSharedContext(context.Background())
+
+	var path1, path2 []string
+	for i := 0; i < 6; i++ {
+		path1 = append(path1, task.PickUpContext(u1).Name())
+		if i%2 == 0 {
+			path2 = append(path2, task.PickUpContext(u2).Name())
+		}
+	}
+	assert.EqualValues(t, []string{"login", "browse", "checkout", "login", "browse", "checkout"}, path1)
+	assert.EqualValues(t, []string{"login", "browse", "checkout"}, path2)
+
+	// 无法区分虚拟用户时共享游标
+	assert.EqualValues(t, "login", task.PickUpContext(context.Background()).Name())
+	assert.EqualValues(t, "browse", task.PickUp().Name())
+}
+
+func TestMarkovTask_Probability(t *testing.T) {
+	task := NewMarkovTask()
+	task.Add(NewHTTPAttacker("home"), 3)
+	task.Add(NewHTTPAttacker("search"), 1)
+	task.Add(NewHTTPAttacker("detail"), 0) // 没有任何转移，执行后退出
+	task.Transition("home", "search", 1).Transition("home", "detail", 1)
+	task.Transition("search", "search", 3).Exit("search", 1)
+
+	counter := make(map[string]int)
+	for i := 0; i < 100000; i++ {
+		counter[task.PickUp().Name()]++
+	}
+	// 平稳分布下的访问比例 home:search:detail = 3:10:1.5
+	assert.InDelta(t, 3.0/14.5, float64(counter["home"])/100000, 0.02)
+	assert.InDelta(t, 10.0/14.5, float64(counter["search"])/100000, 0.02)
+	assert.InDelta(t, 1.5/14.5, float64(counter["detail"])/100000, 0.02)
+}
+
+func TestMarkovTask_Config(t *testing.T) {
+	task := NewMarkovTask()
+	task.Add(NewHTTPAttacker("a"), 0)
+	assert.Panics(t, func() { task.Add(NewHTTPAttacker("a"), 1) })
+	assert.Panics(t, func() { task.Add(nil, 1) })
+	assert.Panics(t, func() { task.Transition("a", "b", 1) })
+	assert.Panics(t, func() { task.Transition("b", "a", 1) })
+	assert.Panics(t, func() { task.Exit("b", 1) })
+	assert.Panics(t, func() { task.Hook(nil) })
+
+	// 未设置入口权重时从第一个状态开始
+	assert.EqualValues(t, "a", task.PickUp().Name())
+	assert.EqualValues(t, "a", task.PickUp().Name())
+}
+
+func TestMarkovTask_Executor(t *testing.T) {
+	mu := new(sync.Mutex)
+	paths := make(map[uint32][]string)
+	task := NewMarkovTask()
+	for _, name := range []string{"login", "browse", "checkout"} {
+		task.Add(&pathAttacker{name: name, paths: paths, mu: mu}, 0)
+	}
+	task.Transition("login", "browse", 1).Transition("browse", "checkout", 1).Exit("checkout", 1)
+
+	var calls []string
+	task.Hook(VirtualUserHookFuncs{Start: func(context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, "start")
+		return nil
+	}})
+
+	commander := newFixedConcurrentUsersStrategyCommander()
+	output := commander.Open(context.Background(), task)
+	go func() {
+		for range output {
+		}
+	}()
+	commander.Command(&FixedConcurrentUsers{ConcurrentUsers: 3}, &ConstantTimer{Wait: time.Millisecond})
+	<-time.After(100 * time.Millisecond)
+	commander.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(t, calls, 3)
+	assert.Len(t, paths, 3)
+	flow := []string{"login", "browse", "checkout"}
+	for id, path := range paths {
+		assert.NotEmpty(t, path, id)
+		for i, name := range path {
+			assert.EqualValues(t, flow[i%len(flow)], name, id)
+		}
+	}
+}
@@ -21,18 +21,23 @@ var (
 )
 
 var (
-	metricTags          = []string{KeyAttacker, KeyPlan}
-	descTotalRequests   = prometheus.NewDesc("ultron_attacker_requests_total", "total requests number of this attacker", metricTags, nil)
-	descTotalFailures   = prometheus.NewDesc("ultron_attacker_failures_total", "total failures number of this attacker", metricTags, nil)
-	descMinResponseTime = prometheus.NewDesc("ultron_attacker_response_time_min", "the min response time for this attacker", metricTags, nil)
-	descMaxResponseTime = prometheus.NewDesc("ultron_attacker_response_time_max", "the max response time for this attacker", metricTags, nil)
-	descAvgResponseTime = prometheus.NewDesc("ultron_attacker_response_time_avg", "the avg response time for this attacker", metricTags, nil)
-	descResponseTime    = prometheus.NewDesc("ultron_attacker_response_time", "the response time for this attacker", metricTags, nil)
-	descFailureRatio    = prometheus.NewDesc("ultron_attacker_failure_ratio", "the failure ratio of this attacker", metricTags, nil)
-	descCurrentTPS      = prometheus.NewDesc("ultron_attacker_tps_current", "current TPS of this attacker", metricTags, nil)
-	descTotalTPS        = prometheus.NewDesc("ultron_attacker_tps_total", "total TPS of this attacker", metricTags, nil)
-	descConcurrentUsers = prometheus.NewDesc("ultron_concurrent_users", "the number of concurrent users", []string{KeyPlan}, nil)
-	descSlaves          = prometheus.NewDesc("ultron_slaves", "the number of subscribing salves", []string{}, nil)
+	metricTags             = []string{KeyAttacker, KeyPlan}
+	descTotalRequests      = prometheus.NewDesc("ultron_attacker_requests_total", "total requests number of this attacker", metricTags, nil)
+	descTotalFailures      = prometheus.NewDesc("ultron_attacker_failures_total", "total failures number of this attacker", metricTags, nil)
+	descMinResponseTime    = prometheus.NewDesc("ultron_attacker_response_time_min", "the min response time for this attacker", metricTags, nil)
+	descMaxResponseTime    = prometheus.NewDesc("ultron_attacker_response_time_max", "the max response time for this attacker", metricTags, nil)
+	descAvgResponseTime    = prometheus.NewDesc("ultron_attacker_response_time_avg", "the avg response time for this attacker", metricTags, nil)
+	descResponseTime       = prometheus.NewDesc("ultron_attacker_response_time", "the response time for this attacker", metricTags, nil)
+	descFailureRatio       = prometheus.NewDesc("ultron_attacker_failure_ratio", "the failure ratio of this attacker", metricTags, nil)
+	descCurrentTPS         = prometheus.NewDesc("ultron_attacker_tps_current", "current TPS of this attacker", metricTags, nil)
+	descTotalTPS           = prometheus.NewDesc("ultron_attacker_tps_total", "total TPS of this attacker", metricTags, nil)
+	descRecentResponseTime = prometheus.NewDesc("ultron_attacker_response_time_recent", "the response time for this attacker in recent window", metricTags, nil)
+	descRecentFailureRatio = prometheus.NewDesc("ultron_attacker_failure_ratio_recent", "the failure ratio of this attacker in recent window", metricTags, nil)
+	descThrottledTime      = prometheus.NewDesc("ultron_attacker_throttled_seconds_total", "the time spent waiting for rate limit tokens of this attacker", metricTags, nil)
+	descPhaseTime          = prometheus.NewDesc("ultron_attacker_phase_time", "the time spent in each phase of traced requests of this attacker", []string{KeyAttacker, KeyPlan, KeyPhase}, nil)
+	descConnReused         = prometheus.NewDesc("ultron_attacker_conn_reused_total", "the number of traced requests of this attacker that reused a connection", metricTags, nil)
+	descConcurrentUsers    = prometheus.NewDesc("ultron_concurrent_users", "the number of concurrent users", []string{KeyPlan}, nil)
+	descSlaves             = prometheus.NewDesc("ultron_slaves", "the number of subscribing salves", []string{}, nil)
 )
 
 func newMetric(runner *masterRunner) *metric {
@@ -49,6 +54,11 @@ func (m *metric) Describe(ch chan<- *prometheus.Desc) {
 	ch <- descFailureRatio
 	ch <- descCurrentTPS
 	ch <- descTotalTPS
+	ch <- descRecentResponseTime
+	ch <- descRecentFailureRatio
+	ch <- descThrottledTime
+	ch <- descPhaseTime
+	ch <- descConnReused
 	ch <- descConcurrentUsers
 	ch <- descSlaves
 }
@@ -68,12 +78,12 @@ func (m *metric) Collect(ch chan<- prometheus.Metric) {
 		return
 	}
 
-	for _, report := range report.Reports {
-		ch <- prometheus.MustNewConstMetric(descTotalRequests, prometheus.CounterValue, float64(report.Requests), report.Name, plan)
-		ch <- prometheus.MustNewConstMetric(descTotalFailures, prometheus.CounterValue, float64(report.Failures), report.Name, plan)
-		ch <- prometheus.MustNewConstMetric(descMinResponseTime, prometheus.GaugeValue, float64(report.Min.Milliseconds()), report.Name, plan)
-		ch <- prometheus.MustNewConstMetric(descMaxResponseTime, prometheus.GaugeValue, float64(report.Max.Milliseconds()), report.Name, plan)
-		ch <- prometheus.MustNewConstMetric(descAvgResponseTime, prometheus.GaugeValue, float64(report.Average.Milliseconds()), report.Name, plan)
+	for key, report := range report.Reports { // 命名场景中的Attacker以"场景/名称"区分
+		ch <- prometheus.MustNewConstMetric(descTotalRequests, prometheus.CounterValue, float64(report.Requests), key, plan)
+		ch <- prometheus.MustNewConstMetric(descTotalFailures, prometheus.CounterValue, float64(report.Failures), key, plan)
+		ch <- prometheus.MustNewConstMetric(descMinResponseTime, prometheus.GaugeValue, float64(report.Min.Milliseconds()), key, plan)
+		ch <- prometheus.MustNewConstMetric(descMaxResponseTime, prometheus.GaugeValue, float64(report.Max.Milliseconds()), key, plan)
+		ch <- prometheus.MustNewConstMetric(descAvgResponseTime, prometheus.GaugeValue, float64(report.Average.Milliseconds()), key, plan)
 		ch <- prometheus.MustNewConstSummary(descResponseTime, report.Requests, float64(report.Average.Milliseconds())*float64(report.Requests), map[float64]float64{
 			0.00: float64(report.Min.Milliseconds()),
 			0.50: float64(report.Median.Milliseconds()),
@@ -86,12 +96,33 @@ func (m *metric) Collect(ch chan<- prometheus.Metric) {
 			0.98: float64(report.Distributions["0.98"].Milliseconds()),
 			0.99: float64(report.Distributions["0.99"].Milliseconds()),
 			1.00: float64(report.Distributions["1.00"].Milliseconds()),
-		}, report.Name, plan)
-		ch <- prometheus.MustNewConstMetric(descFailureRatio, prometheus.GaugeValue, report.FailureRatio, report.Name, plan)
+		}, key, plan)
+		ch <- prometheus.MustNewConstMetric(descFailureRatio, prometheus.GaugeValue, report.FailureRatio, key, plan)
+		ch <- prometheus.MustNewConstMetric(descThrottledTime, prometheus.CounterValue, report.ThrottledTime.Seconds(), key, plan)
+		if trace := report.Trace; trace != nil {
+			for phase, pr := range trace.Phases {
+				ch <- prometheus.MustNewConstSummary(descPhaseTime, pr.Count, float64(pr.Total.Milliseconds()), map[float64]float64{
+					0.00: float64(pr.Min.Milliseconds()),
+					1.00: float64(pr.Max.Milliseconds()),
+				}, key, plan, phase)
+			}
+			ch <- prometheus.MustNewConstMetric(descConnReused, prometheus.CounterValue, float64(trace.ConnReused), key, plan)
+		}
 		if report.FullHistory {
-			ch <- prometheus.MustNewConstMetric(descTotalTPS, prometheus.GaugeValue, report.TPS, report.Name, plan)
+			ch <- prometheus.MustNewConstMetric(descTotalTPS, prometheus.GaugeValue, report.TPS, key, plan)
 		} else {
-			ch <- prometheus.MustNewConstMetric(descCurrentTPS, prometheus.GaugeValue, report.TPS, report.Name, plan)
+			ch <- prometheus.MustNewConstMetric(descCurrentTPS, prometheus.GaugeValue, report.TPS, key, plan)
+		}
+		if recent := report.Recent; recent != nil {
+			ch <- prometheus.MustNewConstSummary(descRecentResponseTime, recent.Requests, float64(recent.Average.Milliseconds())*float64(recent.Requests), map[float64]float64{
+				0.00: float64(recent.Min.Milliseconds()),
+				0.50: float64(recent.Distributions["0.50"].Milliseconds()),
+				0.90: float64(recent.Distributions["0.90"].Milliseconds()),
+				0.95: float64(recent.Distributions["0.95"].Milliseconds()),
+				0.99: float64(recent.Distributions["0.99"].Milliseconds()),
+				1.00: float64(recent.Max.Milliseconds()),
+			}, key, plan)
+			ch <- prometheus.MustNewConstMetric(descRecentFailureRatio, prometheus.GaugeValue, recent.FailureRatio, key, plan)
 		}
 	}
 }
@@ -268,8 +268,13 @@ type SubscribeResponse struct {
 	//	*SubscribeResponse_PlanName
 	//	*SubscribeResponse_AttackStrategy
 	//	*SubscribeResponse_BatchId
-	Data  isSubscribeResponse_Data `protobuf_oneof:"data"`
-	Timer *TimerDTO                `protobuf:"bytes,5,opt,name=timer,proto3" json:"timer,omitempty"`
+	Data       isSubscribeResponse_Data `protobuf_oneof:"data"`
+	Timer      *TimerDTO                `protobuf:"bytes,5,opt,name=timer,proto3" json:"timer,omitempty"`
+	Execution  *ExecutionDTO            `protobuf:"bytes,6,opt,name=execution,proto3" json:"execution,omitempty"`                                                                                      // 仅NEXT_STAGE_STARTED事件携带
+	Weights    map[string]uint32        `protobuf:"bytes,7,rep,name=weights,proto3" json:"weights,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"` // 仅NEXT_STAGE_STARTED事件携带，为空时使用Task的默认权重
+	Scenarios  []*ScenarioDTO           `protobuf:"bytes,8,rep,name=scenarios,proto3" json:"scenarios,omitempty"`                                                                                      // 仅NEXT_STAGE_STARTED事件携带，与默认场景并行执行的命名场景
+	RateLimits []*RateLimitDTO          `protobuf:"bytes,9,rep,name=rate_limits,json=rateLimits,proto3" json:"rate_limits,omitempty"`                                                                  // 仅NEXT_STAGE_STARTED事件携带，该slave分到的请求速率上限
+	Assertions []*AssertionDTO          `protobuf:"bytes,10,rep,name=assertions,proto3" json:"assertions,omitempty"`                                                                                   // 仅NEXT_STAGE_STARTED事件携带，测试计划中声明的断言
 }
 
 func (x *SubscribeResponse) Reset() {
@@ -346,6 +351,41 @@ func (x *SubscribeResponse) GetTimer() *TimerDTO {
 	return nil
 }
 
+func (x *SubscribeResponse) GetExecution() *ExecutionDTO {
+	if x != nil {
+		return x.Execution
+	}
+	return nil
+}
+
+func (x *SubscribeResponse) GetWeights() map[string]uint32 {
+	if x != nil {
+		return x.Weights
+	}
+	return nil
+}
+
+func (x *SubscribeResponse) GetScenarios() []*ScenarioDTO {
+	if x != nil {
+		return x.Scenarios
+	}
+	return nil
+}
+
+func (x *SubscribeResponse) GetRateLimits() []*RateLimitDTO {
+	if x != nil {
+		return x.RateLimits
+	}
+	return nil
+}
+
+func (x *SubscribeResponse) GetAssertions() []*AssertionDTO {
+	if x != nil {
+		return x.Assertions
+	}
+	return nil
+}
+
 type isSubscribeResponse_Data interface {
 	isSubscribeResponse_Data()
 }
@@ -368,6 +408,270 @@ func (*SubscribeResponse_AttackStrategy) isSubscribeResponse_Data() {}
 
 func (*SubscribeResponse_BatchId) isSubscribeResponse_Data() {}
 
+// RateLimitDTO rps为0时不允许发送请求
+type RateLimitDTO struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Attacker string  `protobuf:"bytes,1,opt,name=attacker,proto3" json:"attacker,omitempty"` // 为空时为全局上限
+	Rps      float64 `protobuf:"fixed64,2,opt,name=rps,proto3" json:"rps,omitempty"`
+}
+
+func (x *RateLimitDTO) Reset() {
+	*x = RateLimitDTO{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ultron_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RateLimitDTO) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateLimitDTO) ProtoMessage() {}
+
+func (x *RateLimitDTO) ProtoReflect() protoreflect.Message {
+	mi := &file_ultron_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateLimitDTO.ProtoReflect.Descriptor instead.
+func (*RateLimitDTO) Descriptor() ([]byte, []int) {
+	return file_ultron_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RateLimitDTO) GetAttacker() string {
+	if x != nil {
+		return x.Attacker
+	}
+	return ""
+}
+
+func (x *RateLimitDTO) GetRps() float64 {
+	if x != nil {
+		return x.Rps
+	}
+	return 0
+}
+
+// AssertionDTO value为JSON编码的期望值
+type AssertionDTO struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Attacker string `protobuf:"bytes,1,opt,name=attacker,proto3" json:"attacker,omitempty"`
+	Type     string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Key      string `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	Value    []byte `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *AssertionDTO) Reset() {
+	*x = AssertionDTO{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ultron_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AssertionDTO) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssertionDTO) ProtoMessage() {}
+
+func (x *AssertionDTO) ProtoReflect() protoreflect.Message {
+	mi := &file_ultron_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssertionDTO.ProtoReflect.Descriptor instead.
+func (*AssertionDTO) Descriptor() ([]byte, []int) {
+	return file_ultron_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AssertionDTO) GetAttacker() string {
+	if x != nil {
+		return x.Attacker
+	}
+	return ""
+}
+
+func (x *AssertionDTO) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *AssertionDTO) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *AssertionDTO) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+// ScenarioDTO 命名场景，拥有独立的压测策略、Timer以及Attacker权重
+type ScenarioDTO struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name           string             `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	AttackStrategy *AttackStrategyDTO `protobuf:"bytes,2,opt,name=attack_strategy,json=attackStrategy,proto3" json:"attack_strategy,omitempty"`
+	Timer          *TimerDTO          `protobuf:"bytes,3,opt,name=timer,proto3" json:"timer,omitempty"`
+	Weights        map[string]uint32  `protobuf:"bytes,4,rep,name=weights,proto3" json:"weights,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"` // 仅执行列出的Attacker
+}
+
+func (x *ScenarioDTO) Reset() {
+	*x = ScenarioDTO{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ultron_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ScenarioDTO) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScenarioDTO) ProtoMessage() {}
+
+func (x *ScenarioDTO) ProtoReflect() protoreflect.Message {
+	mi := &file_ultron_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScenarioDTO.ProtoReflect.Descriptor instead.
+func (*ScenarioDTO) Descriptor() ([]byte, []int) {
+	return file_ultron_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ScenarioDTO) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ScenarioDTO) GetAttackStrategy() *AttackStrategyDTO {
+	if x != nil {
+		return x.AttackStrategy
+	}
+	return nil
+}
+
+func (x *ScenarioDTO) GetTimer() *TimerDTO {
+	if x != nil {
+		return x.Timer
+	}
+	return nil
+}
+
+func (x *ScenarioDTO) GetWeights() map[string]uint32 {
+	if x != nil {
+		return x.Weights
+	}
+	return nil
+}
+
+// ExecutionDTO 当前阶段在该slave上的执行信息
+type ExecutionDTO struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StageIndex int32  `protobuf:"varint,1,opt,name=stage_index,json=stageIndex,proto3" json:"stage_index,omitempty"`
+	SlaveIndex uint32 `protobuf:"varint,2,opt,name=slave_index,json=slaveIndex,proto3" json:"slave_index,omitempty"` // slave按ID排序后的序号
+	SlaveCount uint32 `protobuf:"varint,3,opt,name=slave_count,json=slaveCount,proto3" json:"slave_count,omitempty"`
+}
+
+func (x *ExecutionDTO) Reset() {
+	*x = ExecutionDTO{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ultron_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExecutionDTO) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecutionDTO) ProtoMessage() {}
+
+func (x *ExecutionDTO) ProtoReflect() protoreflect.Message {
+	mi := &file_ultron_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecutionDTO.ProtoReflect.Descriptor instead.
+func (*ExecutionDTO) Descriptor() ([]byte, []int) {
+	return file_ultron_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ExecutionDTO) GetStageIndex() int32 {
+	if x != nil {
+		return x.StageIndex
+	}
+	return 0
+}
+
+func (x *ExecutionDTO) GetSlaveIndex() uint32 {
+	if x != nil {
+		return x.SlaveIndex
+	}
+	return 0
+}
+
+func (x *ExecutionDTO) GetSlaveCount() uint32 {
+	if x != nil {
+		return x.SlaveCount
+	}
+	return 0
+}
+
 type SubmitRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -381,7 +685,7 @@ type SubmitRequest struct {
 func (x *SubmitRequest) Reset() {
 	*x = SubmitRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_ultron_proto_msgTypes[4]
+		mi := &file_ultron_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -394,7 +698,7 @@ func (x *SubmitRequest) String() string {
 func (*SubmitRequest) ProtoMessage() {}
 
 func (x *SubmitRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ultron_proto_msgTypes[4]
+	mi := &file_ultron_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -407,7 +711,7 @@ func (x *SubmitRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubmitRequest.ProtoReflect.Descriptor instead.
 func (*SubmitRequest) Descriptor() ([]byte, []int) {
-	return file_ultron_proto_rawDescGZIP(), []int{4}
+	return file_ultron_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *SubmitRequest) GetSlaveId() string {
@@ -443,7 +747,7 @@ type SendStatusRequest struct {
 func (x *SendStatusRequest) Reset() {
 	*x = SendStatusRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_ultron_proto_msgTypes[5]
+		mi := &file_ultron_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -456,7 +760,7 @@ func (x *SendStatusRequest) String() string {
 func (*SendStatusRequest) ProtoMessage() {}
 
 func (x *SendStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ultron_proto_msgTypes[5]
+	mi := &file_ultron_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -469,7 +773,7 @@ func (x *SendStatusRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SendStatusRequest.ProtoReflect.Descriptor instead.
 func (*SendStatusRequest) Descriptor() ([]byte, []int) {
-	return file_ultron_proto_rawDescGZIP(), []int{5}
+	return file_ultron_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *SendStatusRequest) GetSlaveId() string {
@@ -513,7 +817,7 @@ var file_ultron_proto_rawDesc = []byte{
 	0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x61, 0x74, 0x74,
 	0x61, 0x63, 0x6b, 0x5f, 0x73, 0x74, 0x72, 0x61, 0x74, 0x65, 0x67, 0x79, 0x18, 0x02, 0x20, 0x01,
 	0x28, 0x0c, 0x52, 0x0e, 0x61, 0x74, 0x74, 0x61, 0x63, 0x6b, 0x53, 0x74, 0x72, 0x61, 0x74, 0x65,
-	0x67, 0x79, 0x22, 0xfe, 0x01, 0x0a, 0x11, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65,
+	0x67, 0x79, 0x22, 0xee, 0x04, 0x0a, 0x11, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65,
 	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65,
 	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x77, 0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75,
 	0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52,
@@ -528,51 +832,109 @@ var file_ultron_proto_rawDesc = []byte{
 	0x28, 0x0d, 0x48, 0x00, 0x52, 0x07, 0x62, 0x61, 0x74, 0x63, 0x68, 0x49, 0x64, 0x12, 0x2c, 0x0a,
 	0x05, 0x74, 0x69, 0x6d, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x77,
 	0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x54, 0x69, 0x6d, 0x65,
-	0x72, 0x44, 0x54, 0x4f, 0x52, 0x05, 0x74, 0x69, 0x6d, 0x65, 0x72, 0x42, 0x06, 0x0a, 0x04, 0x64,
-	0x61, 0x74, 0x61, 0x22, 0x7f, 0x0a, 0x0d, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x6c, 0x61, 0x76, 0x65, 0x5f, 0x69, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x6c, 0x61, 0x76, 0x65, 0x49, 0x64, 0x12,
-	0x19, 0x0a, 0x08, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0d, 0x52, 0x07, 0x62, 0x61, 0x74, 0x63, 0x68, 0x49, 0x64, 0x12, 0x38, 0x0a, 0x05, 0x73, 0x74,
-	0x61, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x77, 0x6f, 0x73, 0x61,
-	0x69, 0x2e, 0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74,
-	0x69, 0x63, 0x69, 0x61, 0x6e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x44, 0x54, 0x4f, 0x52, 0x05, 0x73,
-	0x74, 0x61, 0x74, 0x73, 0x22, 0x59, 0x0a, 0x11, 0x53, 0x65, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x74,
-	0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x6c, 0x61,
-	0x76, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x6c, 0x61,
-	0x76, 0x65, 0x49, 0x64, 0x12, 0x29, 0x0a, 0x10, 0x63, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65,
-	0x6e, 0x74, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f,
-	0x63, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x55, 0x73, 0x65, 0x72, 0x73, 0x2a,
-	0xbc, 0x01, 0x0a, 0x09, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0b, 0x0a,
-	0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x50, 0x49,
-	0x4e, 0x47, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x43, 0x4f, 0x4e, 0x4e, 0x45, 0x43, 0x54, 0x45,
-	0x44, 0x10, 0x02, 0x12, 0x0e, 0x0a, 0x0a, 0x44, 0x49, 0x53, 0x43, 0x4f, 0x4e, 0x4e, 0x45, 0x43,
-	0x54, 0x10, 0x03, 0x12, 0x10, 0x0a, 0x0c, 0x50, 0x4c, 0x41, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x52,
-	0x54, 0x45, 0x44, 0x10, 0x04, 0x12, 0x11, 0x0a, 0x0d, 0x50, 0x4c, 0x41, 0x4e, 0x5f, 0x46, 0x49,
-	0x4e, 0x49, 0x53, 0x48, 0x45, 0x44, 0x10, 0x05, 0x12, 0x14, 0x0a, 0x10, 0x50, 0x4c, 0x41, 0x4e,
-	0x5f, 0x49, 0x4e, 0x54, 0x45, 0x52, 0x52, 0x55, 0x50, 0x54, 0x45, 0x44, 0x10, 0x06, 0x12, 0x16,
-	0x0a, 0x12, 0x4e, 0x45, 0x58, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x47, 0x45, 0x5f, 0x53, 0x54, 0x41,
-	0x52, 0x54, 0x45, 0x44, 0x10, 0x07, 0x12, 0x13, 0x0a, 0x0f, 0x53, 0x54, 0x41, 0x54, 0x53, 0x5f,
-	0x41, 0x47, 0x47, 0x52, 0x45, 0x47, 0x41, 0x54, 0x45, 0x10, 0x08, 0x12, 0x11, 0x0a, 0x0d, 0x53,
-	0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x52, 0x45, 0x50, 0x4f, 0x52, 0x54, 0x10, 0x09, 0x32, 0xe7,
-	0x01, 0x0a, 0x09, 0x55, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x41, 0x50, 0x49, 0x12, 0x50, 0x0a, 0x09,
-	0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x12, 0x1e, 0x2e, 0x77, 0x6f, 0x73, 0x61,
-	0x69, 0x2e, 0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
-	0x62, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x77, 0x6f, 0x73, 0x61,
-	0x69, 0x2e, 0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
-	0x62, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x3f,
-	0x0a, 0x06, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x12, 0x1b, 0x2e, 0x77, 0x6f, 0x73, 0x61, 0x69,
-	0x2e, 0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12,
-	0x47, 0x0a, 0x0a, 0x53, 0x65, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1f, 0x2e,
-	0x77, 0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x53, 0x65, 0x6e,
-	0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16,
-	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x42, 0x29, 0x5a, 0x27, 0x67, 0x69, 0x74, 0x68,
-	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x77, 0x6f, 0x73, 0x61, 0x69, 0x2f, 0x75, 0x6c, 0x74,
-	0x72, 0x6f, 0x6e, 0x2f, 0x76, 0x32, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x67, 0x65, 0x6e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x72, 0x44, 0x54, 0x4f, 0x52, 0x05, 0x74, 0x69, 0x6d, 0x65, 0x72, 0x12, 0x38, 0x0a, 0x09, 0x65,
+	0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x77, 0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x45, 0x78,
+	0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x54, 0x4f, 0x52, 0x09, 0x65, 0x78, 0x65, 0x63,
+	0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x46, 0x0a, 0x07, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x73,
+	0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x77, 0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75,
+	0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x73, 0x12, 0x37, 0x0a,
+	0x09, 0x73, 0x63, 0x65, 0x6e, 0x61, 0x72, 0x69, 0x6f, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x77, 0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e,
+	0x53, 0x63, 0x65, 0x6e, 0x61, 0x72, 0x69, 0x6f, 0x44, 0x54, 0x4f, 0x52, 0x09, 0x73, 0x63, 0x65,
+	0x6e, 0x61, 0x72, 0x69, 0x6f, 0x73, 0x12, 0x3b, 0x0a, 0x0b, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x6c,
+	0x69, 0x6d, 0x69, 0x74, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x77, 0x6f,
+	0x73, 0x61, 0x69, 0x2e, 0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x4c,
+	0x69, 0x6d, 0x69, 0x74, 0x44, 0x54, 0x4f, 0x52, 0x0a, 0x72, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d,
+	0x69, 0x74, 0x73, 0x12, 0x3a, 0x0a, 0x0a, 0x61, 0x73, 0x73, 0x65, 0x72, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x77, 0x6f, 0x73, 0x61, 0x69, 0x2e,
+	0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x72, 0x74, 0x69, 0x6f, 0x6e,
+	0x44, 0x54, 0x4f, 0x52, 0x0a, 0x61, 0x73, 0x73, 0x65, 0x72, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x1a,
+	0x3a, 0x0a, 0x0c, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x06, 0x0a, 0x04, 0x64,
+	0x61, 0x74, 0x61, 0x22, 0x3c, 0x0a, 0x0c, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x44, 0x54, 0x4f, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x74, 0x74, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x74, 0x74, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x12,
+	0x10, 0x0a, 0x03, 0x72, 0x70, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x72, 0x70,
+	0x73, 0x22, 0x66, 0x0a, 0x0c, 0x41, 0x73, 0x73, 0x65, 0x72, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x54,
+	0x4f, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x74, 0x74, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x74, 0x74, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x12, 0x12, 0x0a,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70,
+	0x65, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x97, 0x02, 0x0a, 0x0b, 0x53, 0x63,
+	0x65, 0x6e, 0x61, 0x72, 0x69, 0x6f, 0x44, 0x54, 0x4f, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x48, 0x0a,
+	0x0f, 0x61, 0x74, 0x74, 0x61, 0x63, 0x6b, 0x5f, 0x73, 0x74, 0x72, 0x61, 0x74, 0x65, 0x67, 0x79,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x77, 0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75,
+	0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x41, 0x74, 0x74, 0x61, 0x63, 0x6b, 0x53, 0x74, 0x72, 0x61,
+	0x74, 0x65, 0x67, 0x79, 0x44, 0x54, 0x4f, 0x52, 0x0e, 0x61, 0x74, 0x74, 0x61, 0x63, 0x6b, 0x53,
+	0x74, 0x72, 0x61, 0x74, 0x65, 0x67, 0x79, 0x12, 0x2c, 0x0a, 0x05, 0x74, 0x69, 0x6d, 0x65, 0x72,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x77, 0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75,
+	0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x44, 0x54, 0x4f, 0x52, 0x05,
+	0x74, 0x69, 0x6d, 0x65, 0x72, 0x12, 0x40, 0x0a, 0x07, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x73,
+	0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x77, 0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75,
+	0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x53, 0x63, 0x65, 0x6e, 0x61, 0x72, 0x69, 0x6f, 0x44, 0x54,
+	0x4f, 0x2e, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07,
+	0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x73, 0x1a, 0x3a, 0x0a, 0x0c, 0x57, 0x65, 0x69, 0x67, 0x68,
+	0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x22, 0x71, 0x0a, 0x0c, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e,
+	0x44, 0x54, 0x4f, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x74, 0x61, 0x67, 0x65, 0x5f, 0x69, 0x6e, 0x64,
+	0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x67, 0x65, 0x49,
+	0x6e, 0x64, 0x65, 0x78, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x6c, 0x61, 0x76, 0x65, 0x5f, 0x69, 0x6e,
+	0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x73, 0x6c, 0x61, 0x76, 0x65,
+	0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x6c, 0x61, 0x76, 0x65, 0x5f, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x73, 0x6c, 0x61, 0x76,
+	0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x7f, 0x0a, 0x0d, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x6c, 0x61, 0x76, 0x65,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x6c, 0x61, 0x76, 0x65,
+	0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x69, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x62, 0x61, 0x74, 0x63, 0x68, 0x49, 0x64, 0x12, 0x38, 0x0a,
+	0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x77,
+	0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x53, 0x74, 0x61, 0x74,
+	0x69, 0x73, 0x74, 0x69, 0x63, 0x69, 0x61, 0x6e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x44, 0x54, 0x4f,
+	0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x22, 0x59, 0x0a, 0x11, 0x53, 0x65, 0x6e, 0x64, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08,
+	0x73, 0x6c, 0x61, 0x76, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x73, 0x6c, 0x61, 0x76, 0x65, 0x49, 0x64, 0x12, 0x29, 0x0a, 0x10, 0x63, 0x6f, 0x6e, 0x63, 0x75,
+	0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x0f, 0x63, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x55, 0x73, 0x65,
+	0x72, 0x73, 0x2a, 0xbc, 0x01, 0x0a, 0x09, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x08, 0x0a,
+	0x04, 0x50, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x43, 0x4f, 0x4e, 0x4e, 0x45,
+	0x43, 0x54, 0x45, 0x44, 0x10, 0x02, 0x12, 0x0e, 0x0a, 0x0a, 0x44, 0x49, 0x53, 0x43, 0x4f, 0x4e,
+	0x4e, 0x45, 0x43, 0x54, 0x10, 0x03, 0x12, 0x10, 0x0a, 0x0c, 0x50, 0x4c, 0x41, 0x4e, 0x5f, 0x53,
+	0x54, 0x41, 0x52, 0x54, 0x45, 0x44, 0x10, 0x04, 0x12, 0x11, 0x0a, 0x0d, 0x50, 0x4c, 0x41, 0x4e,
+	0x5f, 0x46, 0x49, 0x4e, 0x49, 0x53, 0x48, 0x45, 0x44, 0x10, 0x05, 0x12, 0x14, 0x0a, 0x10, 0x50,
+	0x4c, 0x41, 0x4e, 0x5f, 0x49, 0x4e, 0x54, 0x45, 0x52, 0x52, 0x55, 0x50, 0x54, 0x45, 0x44, 0x10,
+	0x06, 0x12, 0x16, 0x0a, 0x12, 0x4e, 0x45, 0x58, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x47, 0x45, 0x5f,
+	0x53, 0x54, 0x41, 0x52, 0x54, 0x45, 0x44, 0x10, 0x07, 0x12, 0x13, 0x0a, 0x0f, 0x53, 0x54, 0x41,
+	0x54, 0x53, 0x5f, 0x41, 0x47, 0x47, 0x52, 0x45, 0x47, 0x41, 0x54, 0x45, 0x10, 0x08, 0x12, 0x11,
+	0x0a, 0x0d, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x52, 0x45, 0x50, 0x4f, 0x52, 0x54, 0x10,
+	0x09, 0x32, 0xe7, 0x01, 0x0a, 0x09, 0x55, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x41, 0x50, 0x49, 0x12,
+	0x50, 0x0a, 0x09, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x12, 0x1e, 0x2e, 0x77,
+	0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x53, 0x75, 0x62, 0x73,
+	0x63, 0x72, 0x69, 0x62, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x77,
+	0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x53, 0x75, 0x62, 0x73,
+	0x63, 0x72, 0x69, 0x62, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30,
+	0x01, 0x12, 0x3f, 0x0a, 0x06, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x12, 0x1b, 0x2e, 0x77, 0x6f,
+	0x73, 0x61, 0x69, 0x2e, 0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
+	0x22, 0x00, 0x12, 0x47, 0x0a, 0x0a, 0x53, 0x65, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x1f, 0x2e, 0x77, 0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e,
+	0x53, 0x65, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x42, 0x29, 0x5a, 0x27, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x77, 0x6f, 0x73, 0x61, 0x69, 0x2f,
+	0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2f, 0x76, 0x32, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x67, 0x65,
+	0x6e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -588,36 +950,50 @@ func file_ultron_proto_rawDescGZIP() []byte {
 }
 
 var file_ultron_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_ultron_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_ultron_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_ultron_proto_goTypes = []interface{}{
 	(EventType)(0),                          // 0: wosai.ultron.EventType
 	(*SubscribeRequest)(nil),                // 1: wosai.ultron.SubscribeRequest
 	(*TimerDTO)(nil),                        // 2: wosai.ultron.TimerDTO
 	(*AttackStrategyDTO)(nil),               // 3: wosai.ultron.AttackStrategyDTO
 	(*SubscribeResponse)(nil),               // 4: wosai.ultron.SubscribeResponse
-	(*SubmitRequest)(nil),                   // 5: wosai.ultron.SubmitRequest
-	(*SendStatusRequest)(nil),               // 6: wosai.ultron.SendStatusRequest
-	nil,                                     // 7: wosai.ultron.SubscribeRequest.ExtrasEntry
-	(*statistics.StatisticianGroupDTO)(nil), // 8: wosai.ultron.StatisticianGroupDTO
-	(*emptypb.Empty)(nil),                   // 9: google.protobuf.Empty
+	(*RateLimitDTO)(nil),                    // 5: wosai.ultron.RateLimitDTO
+	(*AssertionDTO)(nil),                    // 6: wosai.ultron.AssertionDTO
+	(*ScenarioDTO)(nil),                     // 7: wosai.ultron.ScenarioDTO
+	(*ExecutionDTO)(nil),                    // 8: wosai.ultron.ExecutionDTO
+	(*SubmitRequest)(nil),                   // 9: wosai.ultron.SubmitRequest
+	(*SendStatusRequest)(nil),               // 10: wosai.ultron.SendStatusRequest
+	nil,                                     // 11: wosai.ultron.SubscribeRequest.ExtrasEntry
+	nil,                                     // 12: wosai.ultron.SubscribeResponse.WeightsEntry
+	nil,                                     // 13: wosai.ultron.ScenarioDTO.WeightsEntry
+	(*statistics.StatisticianGroupDTO)(nil), // 14: wosai.ultron.StatisticianGroupDTO
+	(*emptypb.Empty)(nil),                   // 15: google.protobuf.Empty
 }
 var file_ultron_proto_depIdxs = []int32{
-	7, // 0: wosai.ultron.SubscribeRequest.extras:type_name -> wosai.ultron.SubscribeRequest.ExtrasEntry
-	0, // 1: wosai.ultron.SubscribeResponse.type:type_name -> wosai.ultron.EventType
-	3, // 2: wosai.ultron.SubscribeResponse.attack_strategy:type_name -> wosai.ultron.AttackStrategyDTO
-	2, // 3: wosai.ultron.SubscribeResponse.timer:type_name -> wosai.ultron.TimerDTO
-	8, // 4: wosai.ultron.SubmitRequest.stats:type_name -> wosai.ultron.StatisticianGroupDTO
-	1, // 5: wosai.ultron.UltronAPI.Subscribe:input_type -> wosai.ultron.SubscribeRequest
-	5, // 6: wosai.ultron.UltronAPI.Submit:input_type -> wosai.ultron.SubmitRequest
-	6, // 7: wosai.ultron.UltronAPI.SendStatus:input_type -> wosai.ultron.SendStatusRequest
-	4, // 8: wosai.ultron.UltronAPI.Subscribe:output_type -> wosai.ultron.SubscribeResponse
-	9, // 9: wosai.ultron.UltronAPI.Submit:output_type -> google.protobuf.Empty
-	9, // 10: wosai.ultron.UltronAPI.SendStatus:output_type -> google.protobuf.Empty
-	8, // [8:11] is the sub-list for method output_type
-	5, // [5:8] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	11, // 0: wosai.ultron.SubscribeRequest.extras:type_name -> wosai.ultron.SubscribeRequest.ExtrasEntry
+	0,  // 1: wosai.ultron.SubscribeResponse.type:type_name -> wosai.ultron.EventType
+	3,  // 2: wosai.ultron.SubscribeResponse.attack_strategy:type_name -> wosai.ultron.AttackStrategyDTO
+	2,  // 3: wosai.ultron.SubscribeResponse.timer:type_name -> wosai.ultron.TimerDTO
+	8,  // 4: wosai.ultron.SubscribeResponse.execution:type_name -> wosai.ultron.ExecutionDTO
+	12, // 5: wosai.ultron.SubscribeResponse.weights:type_name -> wosai.ultron.SubscribeResponse.WeightsEntry
+	7,  // 6: wosai.ultron.SubscribeResponse.scenarios:type_name -> wosai.ultron.ScenarioDTO
+	5,  // 7: wosai.ultron.SubscribeResponse.rate_limits:type_name -> wosai.ultron.RateLimitDTO
+	6,  // 8: wosai.ultron.SubscribeResponse.assertions:type_name -> wosai.ultron.AssertionDTO
+	3,  // 9: wosai.ultron.ScenarioDTO.attack_strategy:type_name -> wosai.ultron.AttackStrategyDTO
+	2,  // 10: wosai.ultron.ScenarioDTO.timer:type_name -> wosai.ultron.TimerDTO
+	13, // 11: wosai.ultron.ScenarioDTO.weights:type_name -> wosai.ultron.ScenarioDTO.WeightsEntry
+	14, // 12: wosai.ultron.SubmitRequest.stats:type_name -> wosai.ultron.StatisticianGroupDTO
+	1,  // 13: wosai.ultron.UltronAPI.Subscribe:input_type -> wosai.ultron.SubscribeRequest
+	9,  // 14: wosai.ultron.UltronAPI.Submit:input_type -> wosai.ultron.SubmitRequest
+	10, // 15: wosai.ultron.UltronAPI.SendStatus:input_type -> wosai.ultron.SendStatusRequest
+	4,  // 16: wosai.ultron.UltronAPI.Subscribe:output_type -> wosai.ultron.SubscribeResponse
+	15, // 17: wosai.ultron.UltronAPI.Submit:output_type -> google.protobuf.Empty
+	15, // 18: wosai.ultron.UltronAPI.SendStatus:output_type -> google.protobuf.Empty
+	16, // [16:19] is the sub-list for method output_type
+	13, // [13:16] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() { file_ultron_proto_init() }
@@ -675,7 +1051,7 @@ func file_ultron_proto_init() {
 			}
 		}
 		file_ultron_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SubmitRequest); i {
+			switch v := v.(*RateLimitDTO); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -687,6 +1063,54 @@ func file_ultron_proto_init() {
 			}
 		}
 		file_ultron_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AssertionDTO); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ultron_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ScenarioDTO); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ultron_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecutionDTO); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ultron_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubmitRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ultron_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SendStatusRequest); i {
 			case 0:
 				return &v.state
@@ -710,7 +1134,7 @@ func file_ultron_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_ultron_proto_rawDesc,
 			NumEnums:      1,
-			NumMessages:   7,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -0,0 +1,224 @@
+package statistics
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"syscall"
+)
+
+const (
+	DefaultMaxFailureKeys    = 50
+	DefaultFailureSamples    = 3
+	OtherFailures            = "other" // 超出错误类别上限后的归类
+	maxFailureMessageLength  = 256
+	failureMessageTruncation = "..."
+)
+
+type (
+	// ErrorClassifier 将错误归类，返回空字符串表示无法归类
+	ErrorClassifier func(error) string
+
+	// statusCoder 携带状态码的错误
+	statusCoder interface {
+		StatusCode() int
+	}
+
+	timeoutError interface {
+		Timeout() bool
+	}
+
+	// failureKeyer 自带错误类别的错误，如断言失败
+	failureKeyer interface {
+		FailureKey() string
+	}
+
+	// failureClassification 错误归类配置，未自定义时共享同一个默认配置
+	failureClassification struct {
+		classifier     ErrorClassifier // 错误归类
+		maxKeys        int             // 错误类别的数量上限
+		maxSamples     int             // 每个错误类别保留的样本数量
+		maxDiagnostics int             // 每个错误类别保留的诊断信息数量
+	}
+)
+
+var (
+	reStatusCode = regexp.MustCompile(`status code:?\s*(\d{3})\b`)
+	reURL        = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^\s"']+`)
+	reUUID       = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	reHex        = regexp.MustCompile(`\b(0x)?[0-9a-fA-F]{16,}\b`)
+	reNumber     = regexp.MustCompile(`\d+`)
+
+	// DefaultErrorClassifier 内置的错误归类，无法归类时使用归一化后的错误信息
+	DefaultErrorClassifier = ChainErrorClassifiers(ClassifyFailureKey, ClassifyContextError, ClassifyNetError, ClassifyHTTPStatus)
+
+	defaultFailureClassification = &failureClassification{
+		classifier:     DefaultErrorClassifier,
+		maxKeys:        DefaultMaxFailureKeys,
+		maxSamples:     DefaultFailureSamples,
+		maxDiagnostics: DefaultDiagnosticSamples,
+	}
+)
+
+// ChainErrorClassifiers 依次尝试多个ErrorClassifier，返回第一个非空的类别
+func ChainErrorClassifiers(classifiers ...ErrorClassifier) ErrorClassifier {
+	return func(err error) string {
+		for _, classify := range classifiers {
+			if classify == nil {
+				continue
+			}
+			if class := classify(err); class != "" {
+				return class
+			}
+		}
+		return ""
+	}
+}
+
+// ClassifyFailureKey 使用错误自带的类别，即实现了FailureKey() string的错误
+func ClassifyFailureKey(err error) string {
+	var fk failureKeyer
+	if errors.As(err, &fk) {
+		return truncateFailureMessage(fk.FailureKey())
+	}
+	return ""
+}
+
+// ClassifyContextError context取消
+func ClassifyContextError(err error) string {
+	if errors.Is(err, context.Canceled) {
+		return "context canceled"
+	}
+	return ""
+}
+
+// ClassifyNetError 超时、连接被拒绝/重置、DNS解析失败
+func ClassifyNetError(err error) string {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return "dns failure"
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return "connection refused"
+	}
+	if errors.Is(err, syscall.ECONNRESET) {
+		return "connection reset"
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) {
+		return "timeout"
+	}
+	var te timeoutError
+	if errors.As(err, &te) && te.Timeout() {
+		return "timeout"
+	}
+	return ""
+}
+
+// ClassifyHTTPStatus 按HTTP状态码的分类（4xx、5xx）归类
+func ClassifyHTTPStatus(err error) string {
+	code := 0
+	var sc statusCoder
+	if errors.As(err, &sc) {
+		code = sc.StatusCode()
+	} else if matches := reStatusCode.FindStringSubmatch(err.Error()); len(matches) == 2 {
+		code, _ = strconv.Atoi(matches[1])
+	}
+	if code < 100 || code > 599 {
+		return ""
+	}
+	return "http " + strconv.Itoa(code/100) + "xx"
+}
+
+// NormalizeErrorMessage 将错误信息中的URL、UUID、十六进制串以及数字替换为占位符，并截断过长的信息
+func NormalizeErrorMessage(err error) string {
+	msg := reURL.ReplaceAllString(err.Error(), "<url>")
+	msg = reUUID.ReplaceAllString(msg, "<uuid>")
+	msg = reHex.ReplaceAllString(msg, "<hex>")
+	msg = reNumber.ReplaceAllString(msg, "<n>")
+	return truncateFailureMessage(msg)
+}
+
+func truncateFailureMessage(msg string) string {
+	if len(msg) <= maxFailureMessageLength {
+		return msg
+	}
+	return msg[:maxFailureMessageLength-len(failureMessageTruncation)] + failureMessageTruncation
+}
+
+// WithErrorClassifier 自定义错误归类，无法归类时依次使用内置的归类、归一化后的错误信息
+func WithErrorClassifier(classifier ErrorClassifier) StatisticianOption {
+	return func(as *AttackStatistician) {
+		if classifier != nil {
+			as.customizeClassification().classifier = ChainErrorClassifiers(classifier, DefaultErrorClassifier)
+		}
+	}
+}
+
+// WithMaxFailureKeys 错误类别的数量上限（含OtherFailures），超出后归入OtherFailures
+func WithMaxFailureKeys(n int) StatisticianOption {
+	return func(as *AttackStatistician) {
+		if n > 0 {
+			as.customizeClassification().maxKeys = n
+		}
+	}
+}
+
+// WithFailureSamples 每个错误类别保留的原始错误信息数量
+func WithFailureSamples(n int) StatisticianOption {
+	return func(as *AttackStatistician) {
+		if n >= 0 {
+			as.customizeClassification().maxSamples = n
+		}
+	}
+}
+
+// customizeClassification 修改前复制默认配置
+func (ara *AttackStatistician) customizeClassification() *failureClassification {
+	if ara.classification == defaultFailureClassification {
+		c := *defaultFailureClassification
+		ara.classification = &c
+	}
+	return ara.classification
+}
+
+func (ara *AttackStatistician) classify(err error) string {
+	if class := ara.classification.classifier(err); class != "" {
+		return truncateFailureMessage(class)
+	}
+	return NormalizeErrorMessage(err)
+}
+
+// failureKey 为OtherFailures预留一个类别，超出类别上限时返回OtherFailures，调用方需持有锁
+func (ara *AttackStatistician) failureKey(class string) string {
+	if _, ok := ara.failureBucket[class]; ok {
+		return class
+	}
+	keys := len(ara.failureBucket)
+	if _, ok := ara.failureBucket[OtherFailures]; ok {
+		keys--
+	}
+	if keys < ara.classification.maxKeys-1 {
+		return class
+	}
+	return OtherFailures
+}
+
+// addFailureSamples 调用方需持有锁
+func (ara *AttackStatistician) addFailureSamples(key string, samples ...string) {
+next:
+	for _, sample := range samples {
+		if len(ara.failureSamples[key]) >= ara.classification.maxSamples {
+			return
+		}
+		sample = truncateFailureMessage(sample)
+		for _, existed := range ara.failureSamples[key] {
+			if existed == sample {
+				continue next
+			}
+		}
+		ara.failureSamples[key] = append(ara.failureSamples[key], sample)
+	}
+}
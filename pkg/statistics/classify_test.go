@@ -0,0 +1,85 @@
+package statistics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type statusError int
+
+func (e statusError) Error() string {
+	return fmt.Sprintf("unexpected response: %d", int(e))
+}
+
+func (e statusError) StatusCode() int {
+	return int(e)
+}
+
+type keyedError string
+
+func (e keyedError) Error() string {
+	return string(e) + ", actual: 42"
+}
+
+func (e keyedError) FailureKey() string {
+	return string(e)
+}
+
+func TestDefaultErrorClassifier(t *testing.T) {
+	cases := map[error]string{
+		context.Canceled: "context canceled",
+		fmt.Errorf("Get \"http://localhost\": %w", context.DeadlineExceeded):             "timeout",
+		&net.OpError{Op: "read", Err: os.ErrDeadlineExceeded}:                            "timeout",
+		&net.OpError{Op: "dial", Err: &os.SyscallError{Err: syscall.ECONNREFUSED}}:       "connection refused",
+		&net.OpError{Op: "read", Err: &os.SyscallError{Err: syscall.ECONNRESET}}:         "connection reset",
+		&net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", Name: "a.b.c"}}: "dns failure",
+		errors.New("bad status code: 502"):                                               "http 5xx",
+		fmt.Errorf("check failed: %w", statusError(404)):                                 "http 4xx",
+		fmt.Errorf("check failed: %w", keyedError("assert: $.code != 0")):                "assert: $.code != 0",
+		errors.New("unknown"): "",
+	}
+	for err, expected := range cases {
+		assert.EqualValues(t, expected, DefaultErrorClassifier(err), err.Error())
+	}
+}
+
+func TestNormalizeErrorMessage(t *testing.T) {
+	msg := NormalizeErrorMessage(errors.New("order 1234 of https://example.com/api?id=5 failed, request id: 3f2a4e1c-9b7d-4c55-8a21-0d6e5f4b3a2c, trace: deadbeefdeadbeef01"))
+	assert.EqualValues(t, msg, "order <n> of <url> failed, request id: <uuid>, trace: <hex>")
+	assert.LessOrEqual(t, len(NormalizeErrorMessage(errors.New(strings.Repeat("x", 1000)))), maxFailureMessageLength)
+}
+
+func TestAttackStatistician_FailureClassification(t *testing.T) {
+	as := NewAttackStatistician("foobar", WithMaxFailureKeys(3), WithFailureSamples(2))
+	for i := 0; i < 10; i++ {
+		as.Record(AttackResult{Name: "foobar", Error: fmt.Errorf("request %d failed", i)})
+	}
+	as.Record(AttackResult{Name: "foobar", Error: context.Canceled})
+	as.Record(AttackResult{Name: "foobar", Error: errors.New("bad status code: 500")})
+
+	report := as.Report(true)
+	assert.EqualValues(t, report.FailureDetails, map[string]uint64{"request <n> failed": 10, "context canceled": 1, OtherFailures: 1})
+	assert.EqualValues(t, report.FailureSamples["request <n> failed"], []string{"request 0 failed", "request 1 failed"})
+	assert.EqualValues(t, report.FailureSamples[OtherFailures], []string{"bad status code: 500"})
+
+	// 合并时同样受上限约束
+	other := NewAttackStatistician("foobar", WithErrorClassifier(func(err error) string { return "custom" }))
+	other.Record(AttackResult{Name: "foobar", Error: errors.New("whatever")})
+	assert.Nil(t, as.merge(other))
+	assert.EqualValues(t, as.Report(true).FailureDetails[OtherFailures], 2)
+	assert.Len(t, as.Report(true).FailureDetails, 3)
+
+	single := NewAttackStatistician("foobar", WithMaxFailureKeys(1))
+	single.Record(AttackResult{Name: "foobar", Error: context.Canceled})
+	single.Record(AttackResult{Name: "foobar", Error: errors.New("bad status code: 500")})
+	assert.EqualValues(t, single.Report(true).FailureDetails, map[string]uint64{OtherFailures: 2})
+	assert.EqualValues(t, other.Report(true).FailureDetails, map[string]uint64{"custom": 1})
+}
@@ -40,7 +40,7 @@ func NewStatisticianGroupFromDTO(dto *StatisticianGroupDTO) (*StatisticianGroup,
 	}
 	var err error
 	for _, v := range dto.GetContainer() {
-		if sg.container[v.Name], err = NewAttackStatisticianFromDTO(v); err != nil {
+		if sg.container[ScenarioKey(v.Scenario, v.Name)], err = NewAttackStatisticianFromDTO(v); err != nil {
 			return nil, err
 		}
 	}
@@ -56,6 +56,7 @@ func ConvertAttackStatistician(as *AttackStatistician) (*AttackStatisticsDTO, er
 
 	dto := &AttackStatisticsDTO{
 		Name:                as.name,
+		Scenario:            as.scenario,
 		Requests:            as.requests,
 		Failures:            as.failures,
 		TotalResponseTime:   durationpb.New(as.totalResponseTime),
@@ -68,6 +69,8 @@ func ConvertAttackStatistician(as *AttackStatistician) (*AttackStatisticsDTO, er
 		FirstAttack:         timestamppb.New(as.firstAttack),
 		LastAttack:          timestamppb.New(as.lastAttack),
 		Interval:            durationpb.New(as.interval),
+		Throttled:           as.throttled,
+		TotalThrottled:      durationpb.New(as.totalThrottled),
 	}
 
 	for k, v := range as.recentSuccessBucket.container {
@@ -82,19 +85,179 @@ func ConvertAttackStatistician(as *AttackStatistician) (*AttackStatisticsDTO, er
 	for k, v := range as.failureBucket {
 		dto.FailureBucket[k] = v
 	}
+	if len(as.failureSamples) > 0 {
+		dto.FailureSamples = make(map[string]*FailureSamplesDTO)
+		for k, v := range as.failureSamples {
+			dto.FailureSamples[k] = &FailureSamplesDTO{Messages: append([]string(nil), v...)}
+		}
+	}
+	if len(as.diagnostics) > 0 {
+		dto.Diagnostics = make(map[string]*DiagnosticReservoirDTO)
+		for k, v := range as.diagnostics {
+			dto.Diagnostics[k] = convertDiagnosticReservoir(v)
+		}
+	}
+	if as.timeline != nil {
+		dto.Timeline = convertTimeline(as.timeline)
+	}
+	if as.trace != nil {
+		dto.Trace = convertTraceStats(as.trace)
+	}
+	dto.Window = convertSlidingWindow(as.window)
 	return dto, nil
 }
 
+func convertSlidingWindow(sw *slidingWindow) *SlidingWindowDTO {
+	dto := &SlidingWindowDTO{
+		Size:  durationpb.New(sw.size),
+		Slots: make(map[int64]*WindowSlotDTO),
+	}
+	for k, slot := range sw.slots {
+		s := &WindowSlotDTO{
+			Requests:          slot.requests,
+			Failures:          slot.failures,
+			TotalResponseTime: durationpb.New(slot.totalResponseTime),
+			MinResponseTime:   durationpb.New(slot.minResponseTime),
+			MaxResponseTime:   durationpb.New(slot.maxResponseTime),
+			ResponseBucket:    make(map[int64]uint64),
+		}
+		for bucket, count := range slot.responseBucket {
+			s.ResponseBucket[int64(bucket)] = count
+		}
+		dto.Slots[k] = s
+	}
+	return dto
+}
+
+func newSlidingWindowFromDTO(dto *SlidingWindowDTO) *slidingWindow {
+	size := dto.GetSize().AsDuration()
+	if size < time.Second {
+		size = DefaultRecentWindow
+	}
+	sw := newSlidingWindow(size)
+	for k, v := range dto.GetSlots() {
+		slot := &windowSlot{
+			requests:          v.GetRequests(),
+			failures:          v.GetFailures(),
+			totalResponseTime: v.GetTotalResponseTime().AsDuration(),
+			minResponseTime:   v.GetMinResponseTime().AsDuration(),
+			maxResponseTime:   v.GetMaxResponseTime().AsDuration(),
+			responseBucket:    make(map[time.Duration]uint64),
+		}
+		for bucket, count := range v.GetResponseBucket() {
+			slot.responseBucket[time.Duration(bucket)] = count
+		}
+		sw.slots[k] = slot
+	}
+	return sw
+}
+
+func convertDiagnosticReservoir(r *diagnosticReservoir) *DiagnosticReservoirDTO {
+	dto := &DiagnosticReservoirDTO{Seen: r.seen, Samples: make([]*DiagnosticDTO, len(r.samples))}
+	for i, d := range r.samples {
+		dto.Samples[i] = &DiagnosticDTO{
+			Request:         d.Request,
+			RequestHeaders:  d.RequestHeaders,
+			RequestBody:     d.RequestBody,
+			Status:          d.Status,
+			ResponseHeaders: d.ResponseHeaders,
+			ResponseBody:    d.ResponseBody,
+			Error:           d.Error,
+			At:              timestamppb.New(d.At),
+		}
+	}
+	return dto
+}
+
+func newDiagnosticReservoirFromDTO(dto *DiagnosticReservoirDTO) *diagnosticReservoir {
+	r := &diagnosticReservoir{seen: dto.GetSeen(), samples: make([]*Diagnostic, len(dto.GetSamples()))}
+	for i, d := range dto.GetSamples() {
+		r.samples[i] = &Diagnostic{
+			Request:         d.GetRequest(),
+			RequestHeaders:  d.GetRequestHeaders(),
+			RequestBody:     d.GetRequestBody(),
+			Status:          d.GetStatus(),
+			ResponseHeaders: d.GetResponseHeaders(),
+			ResponseBody:    d.GetResponseBody(),
+			Error:           d.GetError(),
+			At:              d.GetAt().AsTime(),
+		}
+	}
+	return r
+}
+
+func convertTraceStats(ts *traceStats) *TraceDTO {
+	dto := &TraceDTO{Requests: ts.requests, ConnReused: ts.connReused, Phases: make(map[string]*PhaseDTO)}
+	for name, ps := range ts.phases {
+		dto.Phases[name] = &PhaseDTO{
+			Count: ps.count,
+			Total: durationpb.New(ps.total),
+			Min:   durationpb.New(ps.min),
+			Max:   durationpb.New(ps.max),
+		}
+	}
+	return dto
+}
+
+func newTraceStatsFromDTO(dto *TraceDTO) *traceStats {
+	ts := newTraceStats()
+	ts.requests = dto.GetRequests()
+	ts.connReused = dto.GetConnReused()
+	for name, ps := range dto.GetPhases() {
+		ts.phases[name] = &phaseStats{
+			count: ps.GetCount(),
+			total: ps.GetTotal().AsDuration(),
+			min:   ps.GetMin().AsDuration(),
+			max:   ps.GetMax().AsDuration(),
+		}
+	}
+	return ts
+}
+
+func convertTimeline(tl *timeline) *TimelineDTO {
+	dto := &TimelineDTO{
+		Resolution: durationpb.New(tl.resolution),
+		MaxSlots:   int64(tl.maxSlots),
+		Slots:      make(map[int64]*TimelineSlotDTO),
+	}
+	for k, slot := range tl.slots {
+		s := &TimelineSlotDTO{Requests: slot.requests, Failures: slot.failures, Sketch: make(map[int32]uint64)}
+		for bucket, count := range slot.sketch {
+			s.Sketch[bucket] = count
+		}
+		dto.Slots[k] = s
+	}
+	return dto
+}
+
+func newTimelineFromDTO(dto *TimelineDTO) (*timeline, error) {
+	resolution := dto.GetResolution().AsDuration()
+	if resolution <= 0 {
+		return nil, errors.New("bad resolution of timeline")
+	}
+	tl := newTimeline(resolution, int(dto.GetMaxSlots()))
+	for k, v := range dto.GetSlots() {
+		slot := &timelineSlot{requests: v.GetRequests(), failures: v.GetFailures(), sketch: make(latencySketch)}
+		for bucket, count := range v.GetSketch() {
+			slot.sketch[bucket] = count
+		}
+		tl.slots[k] = slot
+	}
+	return tl, nil
+}
+
 func NewAttackStatisticianFromDTO(dto *AttackStatisticsDTO) (*AttackStatistician, error) {
 	if dto == nil {
 		return nil, errors.New("failed to new AttackStatistician: <nil>")
 	}
-	as := NewAttackStatistician(dto.Name)
+	as := NewAttackStatistician(dto.Name, WithScenario(dto.Scenario))
 	as.requests = dto.Requests
 	as.failures = dto.Failures
 	as.totalResponseTime = dto.TotalResponseTime.AsDuration()
 	as.minResponseTime = dto.MinResponseTime.AsDuration()
 	as.maxResponseTime = dto.MaxResponseTime.AsDuration()
+	as.throttled = dto.GetThrottled()
+	as.totalThrottled = dto.GetTotalThrottled().AsDuration()
 	for k, v := range dto.RecentSuccessBucket {
 		as.recentSuccessBucket.accumulate(k, v)
 	}
@@ -107,8 +270,27 @@ func NewAttackStatisticianFromDTO(dto *AttackStatisticsDTO) (*AttackStatistician
 	for k, v := range dto.FailureBucket {
 		as.failureBucket[k] = v
 	}
+	for k, v := range dto.FailureSamples {
+		as.failureSamples[k] = append([]string(nil), v.GetMessages()...)
+	}
+	for k, v := range dto.Diagnostics {
+		as.diagnostics[k] = newDiagnosticReservoirFromDTO(v)
+	}
 	as.firstAttack = dto.FirstAttack.AsTime()
 	as.lastAttack = dto.LastAttack.AsTime()
+	if dto.Window != nil {
+		as.window = newSlidingWindowFromDTO(dto.Window)
+	}
+	if dto.Trace != nil {
+		as.trace = newTraceStatsFromDTO(dto.Trace)
+	}
+	if dto.Timeline != nil {
+		tl, err := newTimelineFromDTO(dto.Timeline)
+		if err != nil {
+			return nil, err
+		}
+		as.timeline = tl
+	}
 
 	return as, nil
 }
@@ -2,6 +2,7 @@ package statistics
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 
@@ -20,3 +21,18 @@ func TestConvertStatisticianGroup(t *testing.T) {
 	d2, _ := json.Marshal(entity1.Report(true))
 	assert.EqualValues(t, d1, d2)
 }
+
+func TestConvertStatisticianGroup_Timeline(t *testing.T) {
+	entity := NewStatisticianGroup(WithTimeline(time.Second, 60))
+	entity.Record(AttackResult{Name: "foobar", Duration: 3 * time.Millisecond})
+	entity.Record(AttackResult{Name: "foobar", Error: errors.New("unknown")})
+	dto, err := ConvertStatisticianGroup(entity)
+	assert.Nil(t, err)
+	assert.NotNil(t, dto.Container["foobar"].Timeline)
+
+	entity1, err := NewStatisticianGroupFromDTO(dto)
+	assert.Nil(t, err)
+	timeline := entity1.Report(true).Reports["foobar"].Timeline
+	assert.NotEmpty(t, timeline)
+	assert.EqualValues(t, entity.Report(true).Reports["foobar"].Timeline, timeline)
+}
@@ -0,0 +1,106 @@
+package statistics
+
+import (
+	"math/rand"
+	"time"
+)
+
+const (
+	DefaultDiagnosticSamples = 3
+)
+
+type (
+	// Diagnostic 失败请求的诊断信息，由Attacker附加在AttackResult上
+	Diagnostic struct {
+		Request         string            `json:"request,omitempty"`          // 请求行，如：GET http://example.com/ HTTP/1.1
+		RequestHeaders  map[string]string `json:"request_headers,omitempty"`  // 请求头
+		RequestBody     string            `json:"request_body,omitempty"`     // 请求体摘录
+		Status          string            `json:"status,omitempty"`           // 响应状态
+		ResponseHeaders map[string]string `json:"response_headers,omitempty"` // 响应头
+		ResponseBody    string            `json:"response_body,omitempty"`    // 响应体摘录
+		Error           string            `json:"error,omitempty"`            // 原始错误信息
+		At              time.Time         `json:"at"`                         // 发生时间
+	}
+
+	// diagnosticReservoir 蓄水池抽样，seen为参与抽样的总数
+	diagnosticReservoir struct {
+		seen    uint64
+		samples []*Diagnostic
+	}
+)
+
+// WithDiagnosticSamples 每个错误类别保留的诊断信息数量，为0时不保留
+func WithDiagnosticSamples(n int) StatisticianOption {
+	return func(as *AttackStatistician) {
+		if n >= 0 {
+			as.customizeClassification().maxDiagnostics = n
+		}
+	}
+}
+
+func (r *diagnosticReservoir) add(d *Diagnostic, k int) {
+	r.seen++
+	if len(r.samples) < k {
+		r.samples = append(r.samples, d)
+		return
+	}
+	if j := rand.Int63n(int64(r.seen)); j < int64(k) {
+		r.samples[j] = d
+	}
+}
+
+// merge 按两个蓄水池各自的总数加权抽取，近似于对全部诊断信息的均匀抽样
+func (r *diagnosticReservoir) merge(other *diagnosticReservoir, k int) {
+	pools := [2][]*Diagnostic{
+		append([]*Diagnostic(nil), r.samples...),
+		append([]*Diagnostic(nil), other.samples...),
+	}
+	weights := [2]uint64{r.seen, other.seen}
+	merged := make([]*Diagnostic, 0, k)
+
+	for len(merged) < k && len(pools[0])+len(pools[1]) > 0 {
+		n := 0
+		switch {
+		case len(pools[0]) == 0:
+			n = 1
+		case len(pools[1]) == 0:
+			n = 0
+		case weights[0]+weights[1] > 0 && uint64(rand.Int63n(int64(weights[0]+weights[1]))) >= weights[0]:
+			n = 1
+		}
+		i := rand.Intn(len(pools[n]))
+		merged = append(merged, pools[n][i])
+		pools[n] = append(pools[n][:i], pools[n][i+1:]...)
+	}
+
+	r.seen += other.seen
+	r.samples = merged
+}
+
+// addDiagnostic 调用方需持有锁
+func (ara *AttackStatistician) addDiagnostic(key string, d *Diagnostic) {
+	k := ara.classification.maxDiagnostics
+	if d == nil || k <= 0 {
+		return
+	}
+	r, ok := ara.diagnostics[key]
+	if !ok {
+		r = &diagnosticReservoir{}
+		ara.diagnostics[key] = r
+	}
+	r.add(d, k)
+}
+
+// mergeDiagnostics 调用方需持有锁
+func (ara *AttackStatistician) mergeDiagnostics(key string, other *diagnosticReservoir) {
+	k := ara.classification.maxDiagnostics
+	if other == nil || k <= 0 {
+		return
+	}
+	r, ok := ara.diagnostics[key]
+	if !ok {
+		r = &diagnosticReservoir{}
+		ara.diagnostics[key] = r
+	}
+	r.merge(other, k)
+}
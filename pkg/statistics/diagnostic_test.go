@@ -0,0 +1,57 @@
+package statistics
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiagnosticReservoir_Add(t *testing.T) {
+	r := &diagnosticReservoir{}
+	for i := 0; i < 1000; i++ {
+		r.add(&Diagnostic{Request: strconv.Itoa(i)}, 3)
+	}
+	assert.EqualValues(t, r.seen, 1000)
+	assert.EqualValues(t, len(r.samples), 3)
+}
+
+func TestDiagnosticReservoir_Merge(t *testing.T) {
+	r := &diagnosticReservoir{}
+	r.add(&Diagnostic{Request: "a"}, 3)
+	other := &diagnosticReservoir{}
+	for i := 0; i < 10; i++ {
+		other.add(&Diagnostic{Request: "b"}, 3)
+	}
+
+	r.merge(other, 3)
+	assert.EqualValues(t, r.seen, 11)
+	assert.EqualValues(t, len(r.samples), 3)
+	assert.EqualValues(t, len(other.samples), 3) // 不影响被合并的对象
+}
+
+func TestAttackStatistician_Diagnostics(t *testing.T) {
+	as := NewAttackStatistician("foobar", WithDiagnosticSamples(2))
+	as.Record(AttackResult{Name: "foobar", Error: errors.New("bad status code: 500"), Diagnostic: &Diagnostic{Request: "GET / HTTP/1.1", Status: "500"}})
+	as.Record(AttackResult{Name: "foobar", Error: errors.New("unknown")}) // 没有诊断信息
+
+	report := as.Report(false)
+	assert.EqualValues(t, len(report.Diagnostics), 1)
+	d := report.Diagnostics["http 5xx"][0]
+	assert.EqualValues(t, d.Request, "GET / HTTP/1.1")
+	assert.EqualValues(t, d.Error, "bad status code: 500")
+	assert.False(t, d.At.IsZero())
+
+	dto, err := ConvertAttackStatistician(as)
+	assert.Nil(t, err)
+	restored, err := NewAttackStatisticianFromDTO(dto)
+	assert.Nil(t, err)
+	merged := NewAttackStatistician("foobar")
+	assert.Nil(t, merged.merge(restored))
+	assert.EqualValues(t, merged.Report(true).Diagnostics["http 5xx"][0].Request, "GET / HTTP/1.1")
+
+	disabled := NewAttackStatistician("foobar", WithDiagnosticSamples(0))
+	disabled.Record(AttackResult{Name: "foobar", Error: errors.New("unknown"), Diagnostic: &Diagnostic{}})
+	assert.Empty(t, disabled.Report(true).Diagnostics)
+}
@@ -19,44 +19,65 @@ const (
 type (
 	// AttackResult 事务执行结果
 	AttackResult struct {
-		Name     string
-		Duration time.Duration
-		Error    error
+		Name       string
+		Duration   time.Duration
+		Error      error
+		Diagnostic *Diagnostic   // 失败请求的诊断信息，可以为空
+		Scenario   string        // 所属场景，为空时属于默认场景
+		Throttled  time.Duration // 等待限流令牌的时长，不计入Duration
+		Trace      *Trace        // 各阶段的耗时，未开启追踪时为空
 	}
 
 	AttackStatistician struct {
-		name                string                   // 事务名称
-		requests            uint64                   // 成功请求数
-		failures            uint64                   // 失败请求数
-		totalResponseTime   time.Duration            // 原始响应时间汇总
-		minResponseTime     time.Duration            // 最小响应时间
-		maxResponseTime     time.Duration            // 最长响应时间
-		recentSuccessBucket *timeRangeContainer      // 最近的成功请求数量
-		recentFailureBucket *timeRangeContainer      // 最近的失败请求数量
-		responseBucket      map[time.Duration]uint64 // 成功请求的响应时间桶
-		failureBucket       map[string]uint64        // 失败请求的错误原因桶
-		firstAttack         time.Time                // 请求开始时间
-		lastAttack          time.Time                // 最后一次收到响应结果的时间
-		interval            time.Duration            // 统计CurrentTPS（）的时间区间
+		name                string                          // 事务名称
+		scenario            string                          // 所属场景
+		requests            uint64                          // 成功请求数
+		failures            uint64                          // 失败请求数
+		totalResponseTime   time.Duration                   // 原始响应时间汇总
+		minResponseTime     time.Duration                   // 最小响应时间
+		maxResponseTime     time.Duration                   // 最长响应时间
+		throttled           uint64                          // 被限流的请求数
+		totalThrottled      time.Duration                   // 等待限流令牌的总时长
+		trace               *traceStats                     // 分阶段统计，没有开启追踪的请求时为nil
+		recentSuccessBucket *timeRangeContainer             // 最近的成功请求数量
+		recentFailureBucket *timeRangeContainer             // 最近的失败请求数量
+		responseBucket      map[time.Duration]uint64        // 成功请求的响应时间桶
+		failureBucket       map[string]uint64               // 失败请求的错误类别桶
+		failureSamples      map[string][]string             // 每个错误类别的原始错误信息样本
+		diagnostics         map[string]*diagnosticReservoir // 每个错误类别的诊断信息抽样
+		classification      *failureClassification          // 错误归类配置
+		firstAttack         time.Time                       // 请求开始时间
+		lastAttack          time.Time                       // 最后一次收到响应结果的时间
+		interval            time.Duration                   // 统计CurrentTPS（）的时间区间
+		timeline            *timeline                       // 时间线，为nil时不统计
+		window              *slidingWindow                  // 最近一段时间的请求统计
 		mu                  sync.Mutex
 	}
 
 	// AttackReport 聚合报告
 	AttackReport struct {
 		Name           string                   `json:"name"`                      // 事务名称
+		Scenario       string                   `json:"scenario,omitempty"`        // 所属场景，为空时属于默认场景
 		Requests       uint64                   `json:"requests"`                  // 成功请求总数
 		Failures       uint64                   `json:"failures"`                  // 失败请求总数
 		Min            time.Duration            `json:"min"`                       // 最小延迟
 		Max            time.Duration            `json:"max"`                       // 最大延迟
 		Median         time.Duration            `json:"median"`                    // 中位数
 		Average        time.Duration            `json:"average"`                   // 平均数
+		Throttled      uint64                   `json:"throttled,omitempty"`       // 被限流的请求数
+		ThrottledTime  time.Duration            `json:"throttled_time,omitempty"`  // 等待限流令牌的总时长，不计入延迟
+		Trace          *TraceReport             `json:"trace,omitempty"`           // 分阶段统计，仅在开启追踪时输出
 		TPS            float64                  `json:"tps"`                       // 每秒事务数
 		Distributions  map[string]time.Duration `json:"distributions,omitempty"`   // 百分位分布
 		FailureRatio   float64                  `json:"failure_ratio"`             // 错误率
 		FailureDetails map[string]uint64        `json:"failure_details,omitempty"` // 错误详情分布
+		FailureSamples map[string][]string      `json:"failure_samples,omitempty"` // 每个错误类别的原始错误信息样本
+		Diagnostics    map[string][]Diagnostic  `json:"diagnostics,omitempty"`     // 每个错误类别的诊断信息抽样
 		FullHistory    bool                     `json:"full_history"`              // 是否是该阶段完整的报告
 		FirstAttack    time.Time                `json:"first_attack"`              // 第一请求发生时间
 		LastAttack     time.Time                `json:"last_attack"`               // 最后一次请求结束时间
+		Timeline       []TimelinePoint          `json:"timeline,omitempty"`        // 时间线，仅在完整报告中输出
+		Recent         *WindowReport            `json:"recent,omitempty"`          // 最近窗口内的统计，仅在实时报告中输出
 	}
 
 	SummaryReport struct {
@@ -68,6 +89,8 @@ type (
 		FullHistory   bool                    `json:"full_history"`
 		Reports       map[string]AttackReport `json:"reports,omitempty"`
 		Extras        map[string]string       `json:"extras,omitempty"`
+		// ConcurrentUsers 生成周期报告时的并发用户数，由master填写；取值随加压变化，不适合作为Extras中的标签
+		ConcurrentUsers int `json:"concurrent_users,omitempty"`
 	}
 
 	timeRangeContainer struct {
@@ -76,6 +99,7 @@ type (
 	}
 
 	StatisticianGroup struct {
+		opts      []StatisticianOption
 		tags      map[string]Tag
 		container map[string]*AttackStatistician // 优于sync.Map
 		mu        sync.Mutex                     // 写多读少场景，互斥锁更好
@@ -124,15 +148,42 @@ func (ar *AttackResult) IsFailure() bool {
 	return ar.Error != nil
 }
 
-func NewAttackStatistician(name string) *AttackStatistician {
-	return &AttackStatistician{
+func NewAttackStatistician(name string, opts ...StatisticianOption) *AttackStatistician {
+	as := &AttackStatistician{
 		name:                name,
 		recentSuccessBucket: newTimeRangeContainer(15),
 		recentFailureBucket: newTimeRangeContainer(15),
 		responseBucket:      make(map[time.Duration]uint64),
 		failureBucket:       make(map[string]uint64),
+		failureSamples:      make(map[string][]string),
+		diagnostics:         make(map[string]*diagnosticReservoir),
+		classification:      defaultFailureClassification,
 		interval:            CurrentTPSTimeRange,
+		window:              newSlidingWindow(DefaultRecentWindow),
 	}
+	for _, opt := range opts {
+		opt(as)
+	}
+	return as
+}
+
+// WithScenario 所属场景
+func WithScenario(scenario string) StatisticianOption {
+	return func(as *AttackStatistician) {
+		as.scenario = scenario
+	}
+}
+
+// ScenarioKey 统计组以及报告中的key，默认场景直接使用事务名称，其他场景为"场景/事务名称"
+func ScenarioKey(scenario, name string) string {
+	if scenario == "" {
+		return name
+	}
+	return scenario + "/" + name
+}
+
+func (ara *AttackStatistician) key() string {
+	return ScenarioKey(ara.scenario, ara.name)
 }
 
 func (ara *AttackStatistician) recordSuccess(ret AttackResult) {
@@ -144,6 +195,8 @@ func (ara *AttackStatistician) recordSuccess(ret AttackResult) {
 
 	ara.requests++
 	ara.totalResponseTime += ret.Duration
+	ara.recordThrottled(ret)
+	ara.recordTrace(ret)
 
 	now := time.Now()
 	if ara.firstAttack.IsZero() { // 第一次记录，且是成功请求
@@ -165,6 +218,10 @@ func (ara *AttackStatistician) recordSuccess(ret AttackResult) {
 
 	ara.recentSuccessBucket.accumulate(now.Unix(), 1)
 	ara.responseBucket[findResponseBucket(ret.Duration)]++
+	ara.window.recordSuccess(now, ret.Duration)
+	if ara.timeline != nil {
+		ara.timeline.recordSuccess(now, ret.Duration)
+	}
 }
 
 func (ara *AttackStatistician) recordFailure(ret AttackResult) {
@@ -176,6 +233,8 @@ func (ara *AttackStatistician) recordFailure(ret AttackResult) {
 	defer ara.mu.Unlock()
 
 	ara.failures++
+	ara.recordThrottled(ret)
+	ara.recordTrace(ret)
 
 	now := time.Now()
 	if ara.firstAttack.IsZero() {
@@ -183,8 +242,43 @@ func (ara *AttackStatistician) recordFailure(ret AttackResult) {
 	}
 	ara.lastAttack = now
 
-	ara.failureBucket[ret.Error.Error()]++
+	key := ara.failureKey(ara.classify(ret.Error))
+	ara.failureBucket[key]++
+	ara.addFailureSamples(key, ret.Error.Error())
+	if ret.Diagnostic != nil {
+		d := *ret.Diagnostic
+		if d.Error == "" {
+			d.Error = ret.Error.Error()
+		}
+		if d.At.IsZero() {
+			d.At = now
+		}
+		ara.addDiagnostic(key, &d)
+	}
 	ara.recentFailureBucket.accumulate(now.Unix(), 1)
+	ara.window.recordFailure(now)
+	if ara.timeline != nil {
+		ara.timeline.recordFailure(now)
+	}
+}
+
+// recordThrottled 调用方需持有锁
+func (ara *AttackStatistician) recordThrottled(ret AttackResult) {
+	if ret.Throttled > 0 {
+		ara.throttled++
+		ara.totalThrottled += ret.Throttled
+	}
+}
+
+// recordTrace 调用方需持有锁
+func (ara *AttackStatistician) recordTrace(ret AttackResult) {
+	if ret.Trace == nil {
+		return
+	}
+	if ara.trace == nil {
+		ara.trace = newTraceStats()
+	}
+	ara.trace.record(ret.Trace)
 }
 
 func (ara *AttackStatistician) Record(ret AttackResult) {
@@ -294,14 +388,18 @@ func (ara *AttackStatistician) Report(full bool) AttackReport {
 
 	report := AttackReport{
 		Name:           ara.name,
+		Scenario:       ara.scenario,
 		Requests:       ara.requests,
 		Failures:       ara.failures,
 		Min:            ara.min(),
 		Max:            ara.max(),
 		Average:        ara.average(),
+		Throttled:      ara.throttled,
+		ThrottledTime:  ara.totalThrottled,
 		Distributions:  make(map[string]time.Duration),
 		FailureRatio:   ara.failureRatio(),
 		FailureDetails: make(map[string]uint64),
+		FailureSamples: make(map[string][]string),
 		FullHistory:    full,
 		FirstAttack:    ara.firstAttack,
 		LastAttack:     ara.lastAttack,
@@ -310,6 +408,7 @@ func (ara *AttackStatistician) Report(full bool) AttackReport {
 		report.TPS = ara.totalTPS()
 	} else {
 		report.TPS = ara.currentTPS()
+		report.Recent = ara.window.report(time.Now())
 	}
 	pers := ara.percentile(timeDistributions...)
 	for index, d := range timeDistributions {
@@ -321,6 +420,23 @@ func (ara *AttackStatistician) Report(full bool) AttackReport {
 	for key, value := range ara.failureBucket {
 		report.FailureDetails[key] = value
 	}
+	for key, samples := range ara.failureSamples {
+		report.FailureSamples[key] = append([]string(nil), samples...)
+	}
+	if len(ara.diagnostics) > 0 {
+		report.Diagnostics = make(map[string][]Diagnostic)
+		for key, r := range ara.diagnostics {
+			for _, d := range r.samples {
+				report.Diagnostics[key] = append(report.Diagnostics[key], *d)
+			}
+		}
+	}
+	if full && ara.timeline != nil {
+		report.Timeline = ara.timeline.points()
+	}
+	if ara.trace != nil {
+		report.Trace = ara.trace.report()
+	}
 	return report
 }
 
@@ -328,7 +444,7 @@ func (ara *AttackStatistician) merge(other *AttackStatistician) error {
 	if other == nil {
 		return nil
 	}
-	if ara.name != other.name {
+	if ara.name != other.name || ara.scenario != other.scenario {
 		return errors.New("cannot merge two different types report")
 	}
 
@@ -340,6 +456,8 @@ func (ara *AttackStatistician) merge(other *AttackStatistician) error {
 	ara.requests += other.requests
 	ara.failures += other.failures
 	ara.totalResponseTime += other.totalResponseTime
+	ara.throttled += other.throttled
+	ara.totalThrottled += other.totalThrottled
 	if (other.minResponseTime < ara.minResponseTime && other.minResponseTime > 0) || ara.minResponseTime == 0 {
 		ara.minResponseTime = other.minResponseTime
 	}
@@ -356,7 +474,23 @@ func (ara *AttackStatistician) merge(other *AttackStatistician) error {
 		ara.responseBucket[k] += v
 	}
 	for k, v := range other.failureBucket {
-		ara.failureBucket[k] += v
+		key := ara.failureKey(k)
+		ara.failureBucket[key] += v
+		ara.addFailureSamples(key, other.failureSamples[k]...)
+		ara.mergeDiagnostics(key, other.diagnostics[k])
+	}
+	ara.window.merge(other.window)
+	if other.trace != nil {
+		if ara.trace == nil {
+			ara.trace = newTraceStats()
+		}
+		ara.trace.merge(other.trace)
+	}
+	if other.timeline != nil {
+		if ara.timeline == nil {
+			ara.timeline = newTimeline(other.timeline.resolution, other.timeline.maxSlots)
+		}
+		ara.timeline.merge(other.timeline)
 	}
 	if (!other.firstAttack.IsZero() && other.firstAttack.Before(ara.firstAttack)) || ara.firstAttack.IsZero() {
 		ara.firstAttack = other.firstAttack
@@ -377,8 +511,10 @@ func (ara *AttackStatistician) BatchMerge(others ...*AttackStatistician) error {
 	return nil
 }
 
-func NewStatisticianGroup() *StatisticianGroup {
+// NewStatisticianGroup opts作用于组内新建的每个AttackStatistician
+func NewStatisticianGroup(opts ...StatisticianOption) *StatisticianGroup {
 	return &StatisticianGroup{
+		opts:      opts,
 		container: make(map[string]*AttackStatistician),
 		tags:      make(map[string]Tag),
 	}
@@ -426,15 +562,25 @@ func (s *StatisticianGroup) Record(result AttackResult) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if agg, ok := s.container[result.Name]; !ok {
-		agg = NewAttackStatistician(result.Name)
+	key := ScenarioKey(result.Scenario, result.Name)
+	if agg, ok := s.container[key]; !ok {
+		agg = s.newStatistician(result.Name, result.Scenario)
 		agg.Record(result)
-		s.container[result.Name] = agg
+		s.container[key] = agg
 	} else {
 		agg.Record(result)
 	}
 }
 
+// newStatistician 调用方需持有锁
+func (s *StatisticianGroup) newStatistician(name, scenario string) *AttackStatistician {
+	if scenario == "" {
+		return NewAttackStatistician(name, s.opts...)
+	}
+	opts := append(append([]StatisticianOption(nil), s.opts...), WithScenario(scenario))
+	return NewAttackStatistician(name, opts...)
+}
+
 // Reset 重置统计组状态
 func (s *StatisticianGroup) Reset() {
 	s.mu.Lock()
@@ -457,10 +603,17 @@ func (s *StatisticianGroup) ReplaceStatistician(agg *AttackStatistician) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.container[agg.name] = agg
+	s.container[agg.key()] = agg
 	return nil
 }
 
+// Apply 追加配置项，仅对之后新建的AttackStatistician生效
+func (s *StatisticianGroup) Apply(opts ...StatisticianOption) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.opts = append(s.opts, opts...)
+}
+
 // Attach 附加tag
 func (s *StatisticianGroup) Attach(tag Tag) {
 	s.mu.Lock()
@@ -497,7 +650,7 @@ func (s *StatisticianGroup) Merge(other *StatisticianGroup) {
 
 	for key, value := range other.container {
 		if _, ok := s.container[key]; !ok {
-			s.container[key] = NewAttackStatistician(key)
+			s.container[key] = s.newStatistician(value.name, value.scenario)
 		}
 		s.container[key].merge(value)
 	}
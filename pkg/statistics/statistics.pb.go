@@ -27,19 +27,27 @@ type AttackStatisticsDTO struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name                string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Requests            uint64                 `protobuf:"varint,2,opt,name=requests,proto3" json:"requests,omitempty"`
-	Failures            uint64                 `protobuf:"varint,3,opt,name=failures,proto3" json:"failures,omitempty"`
-	TotalResponseTime   *durationpb.Duration   `protobuf:"bytes,4,opt,name=total_response_time,json=totalResponseTime,proto3" json:"total_response_time,omitempty"`
-	MinResponseTime     *durationpb.Duration   `protobuf:"bytes,5,opt,name=min_response_time,json=minResponseTime,proto3" json:"min_response_time,omitempty"`
-	MaxResponseTime     *durationpb.Duration   `protobuf:"bytes,6,opt,name=max_response_time,json=maxResponseTime,proto3" json:"max_response_time,omitempty"`
-	RecentSuccessBucket map[int64]int64        `protobuf:"bytes,7,rep,name=recent_success_bucket,json=recentSuccessBucket,proto3" json:"recent_success_bucket,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
-	RecentFailureBucket map[int64]int64        `protobuf:"bytes,8,rep,name=recent_failure_bucket,json=recentFailureBucket,proto3" json:"recent_failure_bucket,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
-	ResponseBucket      map[int64]uint64       `protobuf:"bytes,9,rep,name=response_bucket,json=responseBucket,proto3" json:"response_bucket,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
-	FailureBucket       map[string]uint64      `protobuf:"bytes,10,rep,name=failure_bucket,json=failureBucket,proto3" json:"failure_bucket,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
-	FirstAttack         *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=first_attack,json=firstAttack,proto3" json:"first_attack,omitempty"`
-	LastAttack          *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=last_attack,json=lastAttack,proto3" json:"last_attack,omitempty"`
-	Interval            *durationpb.Duration   `protobuf:"bytes,13,opt,name=interval,proto3" json:"interval,omitempty"`
+	Name                string                             `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Requests            uint64                             `protobuf:"varint,2,opt,name=requests,proto3" json:"requests,omitempty"`
+	Failures            uint64                             `protobuf:"varint,3,opt,name=failures,proto3" json:"failures,omitempty"`
+	TotalResponseTime   *durationpb.Duration               `protobuf:"bytes,4,opt,name=total_response_time,json=totalResponseTime,proto3" json:"total_response_time,omitempty"`
+	MinResponseTime     *durationpb.Duration               `protobuf:"bytes,5,opt,name=min_response_time,json=minResponseTime,proto3" json:"min_response_time,omitempty"`
+	MaxResponseTime     *durationpb.Duration               `protobuf:"bytes,6,opt,name=max_response_time,json=maxResponseTime,proto3" json:"max_response_time,omitempty"`
+	RecentSuccessBucket map[int64]int64                    `protobuf:"bytes,7,rep,name=recent_success_bucket,json=recentSuccessBucket,proto3" json:"recent_success_bucket,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	RecentFailureBucket map[int64]int64                    `protobuf:"bytes,8,rep,name=recent_failure_bucket,json=recentFailureBucket,proto3" json:"recent_failure_bucket,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	ResponseBucket      map[int64]uint64                   `protobuf:"bytes,9,rep,name=response_bucket,json=responseBucket,proto3" json:"response_bucket,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	FailureBucket       map[string]uint64                  `protobuf:"bytes,10,rep,name=failure_bucket,json=failureBucket,proto3" json:"failure_bucket,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	FirstAttack         *timestamppb.Timestamp             `protobuf:"bytes,11,opt,name=first_attack,json=firstAttack,proto3" json:"first_attack,omitempty"`
+	LastAttack          *timestamppb.Timestamp             `protobuf:"bytes,12,opt,name=last_attack,json=lastAttack,proto3" json:"last_attack,omitempty"`
+	Interval            *durationpb.Duration               `protobuf:"bytes,13,opt,name=interval,proto3" json:"interval,omitempty"`
+	Timeline            *TimelineDTO                       `protobuf:"bytes,14,opt,name=timeline,proto3" json:"timeline,omitempty"`
+	Window              *SlidingWindowDTO                  `protobuf:"bytes,15,opt,name=window,proto3" json:"window,omitempty"`
+	FailureSamples      map[string]*FailureSamplesDTO      `protobuf:"bytes,16,rep,name=failure_samples,json=failureSamples,proto3" json:"failure_samples,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Diagnostics         map[string]*DiagnosticReservoirDTO `protobuf:"bytes,17,rep,name=diagnostics,proto3" json:"diagnostics,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Scenario            string                             `protobuf:"bytes,18,opt,name=scenario,proto3" json:"scenario,omitempty"`
+	Throttled           uint64                             `protobuf:"varint,19,opt,name=throttled,proto3" json:"throttled,omitempty"`
+	TotalThrottled      *durationpb.Duration               `protobuf:"bytes,20,opt,name=total_throttled,json=totalThrottled,proto3" json:"total_throttled,omitempty"`
+	Trace               *TraceDTO                          `protobuf:"bytes,21,opt,name=trace,proto3" json:"trace,omitempty"`
 }
 
 func (x *AttackStatisticsDTO) Reset() {
@@ -165,32 +173,592 @@ func (x *AttackStatisticsDTO) GetInterval() *durationpb.Duration {
 	return nil
 }
 
+func (x *AttackStatisticsDTO) GetTimeline() *TimelineDTO {
+	if x != nil {
+		return x.Timeline
+	}
+	return nil
+}
+
+func (x *AttackStatisticsDTO) GetWindow() *SlidingWindowDTO {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+func (x *AttackStatisticsDTO) GetFailureSamples() map[string]*FailureSamplesDTO {
+	if x != nil {
+		return x.FailureSamples
+	}
+	return nil
+}
+
+func (x *AttackStatisticsDTO) GetDiagnostics() map[string]*DiagnosticReservoirDTO {
+	if x != nil {
+		return x.Diagnostics
+	}
+	return nil
+}
+
+func (x *AttackStatisticsDTO) GetScenario() string {
+	if x != nil {
+		return x.Scenario
+	}
+	return ""
+}
+
+func (x *AttackStatisticsDTO) GetThrottled() uint64 {
+	if x != nil {
+		return x.Throttled
+	}
+	return 0
+}
+
+func (x *AttackStatisticsDTO) GetTotalThrottled() *durationpb.Duration {
+	if x != nil {
+		return x.TotalThrottled
+	}
+	return nil
+}
+
+func (x *AttackStatisticsDTO) GetTrace() *TraceDTO {
+	if x != nil {
+		return x.Trace
+	}
+	return nil
+}
+
+type PhaseDTO struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Count uint64               `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Total *durationpb.Duration `protobuf:"bytes,2,opt,name=total,proto3" json:"total,omitempty"`
+	Min   *durationpb.Duration `protobuf:"bytes,3,opt,name=min,proto3" json:"min,omitempty"`
+	Max   *durationpb.Duration `protobuf:"bytes,4,opt,name=max,proto3" json:"max,omitempty"`
+}
+
+func (x *PhaseDTO) Reset() {
+	*x = PhaseDTO{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_statistics_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PhaseDTO) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PhaseDTO) ProtoMessage() {}
+
+func (x *PhaseDTO) ProtoReflect() protoreflect.Message {
+	mi := &file_statistics_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PhaseDTO.ProtoReflect.Descriptor instead.
+func (*PhaseDTO) Descriptor() ([]byte, []int) {
+	return file_statistics_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PhaseDTO) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *PhaseDTO) GetTotal() *durationpb.Duration {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *PhaseDTO) GetMin() *durationpb.Duration {
+	if x != nil {
+		return x.Min
+	}
+	return nil
+}
+
+func (x *PhaseDTO) GetMax() *durationpb.Duration {
+	if x != nil {
+		return x.Max
+	}
+	return nil
+}
+
+type TraceDTO struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Requests   uint64               `protobuf:"varint,1,opt,name=requests,proto3" json:"requests,omitempty"`
+	ConnReused uint64               `protobuf:"varint,2,opt,name=conn_reused,json=connReused,proto3" json:"conn_reused,omitempty"`
+	Phases     map[string]*PhaseDTO `protobuf:"bytes,3,rep,name=phases,proto3" json:"phases,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *TraceDTO) Reset() {
+	*x = TraceDTO{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_statistics_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TraceDTO) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TraceDTO) ProtoMessage() {}
+
+func (x *TraceDTO) ProtoReflect() protoreflect.Message {
+	mi := &file_statistics_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraceDTO.ProtoReflect.Descriptor instead.
+func (*TraceDTO) Descriptor() ([]byte, []int) {
+	return file_statistics_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TraceDTO) GetRequests() uint64 {
+	if x != nil {
+		return x.Requests
+	}
+	return 0
+}
+
+func (x *TraceDTO) GetConnReused() uint64 {
+	if x != nil {
+		return x.ConnReused
+	}
+	return 0
+}
+
+func (x *TraceDTO) GetPhases() map[string]*PhaseDTO {
+	if x != nil {
+		return x.Phases
+	}
+	return nil
+}
+
+type FailureSamplesDTO struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Messages []string `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+}
+
+func (x *FailureSamplesDTO) Reset() {
+	*x = FailureSamplesDTO{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_statistics_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FailureSamplesDTO) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FailureSamplesDTO) ProtoMessage() {}
+
+func (x *FailureSamplesDTO) ProtoReflect() protoreflect.Message {
+	mi := &file_statistics_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FailureSamplesDTO.ProtoReflect.Descriptor instead.
+func (*FailureSamplesDTO) Descriptor() ([]byte, []int) {
+	return file_statistics_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FailureSamplesDTO) GetMessages() []string {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+type TimelineSlotDTO struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Requests uint64           `protobuf:"varint,1,opt,name=requests,proto3" json:"requests,omitempty"`
+	Failures uint64           `protobuf:"varint,2,opt,name=failures,proto3" json:"failures,omitempty"`
+	Sketch   map[int32]uint64 `protobuf:"bytes,3,rep,name=sketch,proto3" json:"sketch,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+}
+
+func (x *TimelineSlotDTO) Reset() {
+	*x = TimelineSlotDTO{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_statistics_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TimelineSlotDTO) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimelineSlotDTO) ProtoMessage() {}
+
+func (x *TimelineSlotDTO) ProtoReflect() protoreflect.Message {
+	mi := &file_statistics_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimelineSlotDTO.ProtoReflect.Descriptor instead.
+func (*TimelineSlotDTO) Descriptor() ([]byte, []int) {
+	return file_statistics_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TimelineSlotDTO) GetRequests() uint64 {
+	if x != nil {
+		return x.Requests
+	}
+	return 0
+}
+
+func (x *TimelineSlotDTO) GetFailures() uint64 {
+	if x != nil {
+		return x.Failures
+	}
+	return 0
+}
+
+func (x *TimelineSlotDTO) GetSketch() map[int32]uint64 {
+	if x != nil {
+		return x.Sketch
+	}
+	return nil
+}
+
+type TimelineDTO struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Resolution *durationpb.Duration       `protobuf:"bytes,1,opt,name=resolution,proto3" json:"resolution,omitempty"`
+	MaxSlots   int64                      `protobuf:"varint,2,opt,name=max_slots,json=maxSlots,proto3" json:"max_slots,omitempty"`
+	Slots      map[int64]*TimelineSlotDTO `protobuf:"bytes,3,rep,name=slots,proto3" json:"slots,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *TimelineDTO) Reset() {
+	*x = TimelineDTO{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_statistics_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TimelineDTO) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimelineDTO) ProtoMessage() {}
+
+func (x *TimelineDTO) ProtoReflect() protoreflect.Message {
+	mi := &file_statistics_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimelineDTO.ProtoReflect.Descriptor instead.
+func (*TimelineDTO) Descriptor() ([]byte, []int) {
+	return file_statistics_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TimelineDTO) GetResolution() *durationpb.Duration {
+	if x != nil {
+		return x.Resolution
+	}
+	return nil
+}
+
+func (x *TimelineDTO) GetMaxSlots() int64 {
+	if x != nil {
+		return x.MaxSlots
+	}
+	return 0
+}
+
+func (x *TimelineDTO) GetSlots() map[int64]*TimelineSlotDTO {
+	if x != nil {
+		return x.Slots
+	}
+	return nil
+}
+
 type TagDTO struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Key   string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
-	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Key   string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *TagDTO) Reset() {
+	*x = TagDTO{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_statistics_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TagDTO) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TagDTO) ProtoMessage() {}
+
+func (x *TagDTO) ProtoReflect() protoreflect.Message {
+	mi := &file_statistics_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TagDTO.ProtoReflect.Descriptor instead.
+func (*TagDTO) Descriptor() ([]byte, []int) {
+	return file_statistics_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TagDTO) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *TagDTO) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type StatisticianGroupDTO struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Container map[string]*AttackStatisticsDTO `protobuf:"bytes,1,rep,name=container,proto3" json:"container,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Tags      []*TagDTO                       `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
+}
+
+func (x *StatisticianGroupDTO) Reset() {
+	*x = StatisticianGroupDTO{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_statistics_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatisticianGroupDTO) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatisticianGroupDTO) ProtoMessage() {}
+
+func (x *StatisticianGroupDTO) ProtoReflect() protoreflect.Message {
+	mi := &file_statistics_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatisticianGroupDTO.ProtoReflect.Descriptor instead.
+func (*StatisticianGroupDTO) Descriptor() ([]byte, []int) {
+	return file_statistics_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *StatisticianGroupDTO) GetContainer() map[string]*AttackStatisticsDTO {
+	if x != nil {
+		return x.Container
+	}
+	return nil
+}
+
+func (x *StatisticianGroupDTO) GetTags() []*TagDTO {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type WindowSlotDTO struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Requests          uint64               `protobuf:"varint,1,opt,name=requests,proto3" json:"requests,omitempty"`
+	Failures          uint64               `protobuf:"varint,2,opt,name=failures,proto3" json:"failures,omitempty"`
+	TotalResponseTime *durationpb.Duration `protobuf:"bytes,3,opt,name=total_response_time,json=totalResponseTime,proto3" json:"total_response_time,omitempty"`
+	MinResponseTime   *durationpb.Duration `protobuf:"bytes,4,opt,name=min_response_time,json=minResponseTime,proto3" json:"min_response_time,omitempty"`
+	MaxResponseTime   *durationpb.Duration `protobuf:"bytes,5,opt,name=max_response_time,json=maxResponseTime,proto3" json:"max_response_time,omitempty"`
+	ResponseBucket    map[int64]uint64     `protobuf:"bytes,6,rep,name=response_bucket,json=responseBucket,proto3" json:"response_bucket,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+}
+
+func (x *WindowSlotDTO) Reset() {
+	*x = WindowSlotDTO{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_statistics_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WindowSlotDTO) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WindowSlotDTO) ProtoMessage() {}
+
+func (x *WindowSlotDTO) ProtoReflect() protoreflect.Message {
+	mi := &file_statistics_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WindowSlotDTO.ProtoReflect.Descriptor instead.
+func (*WindowSlotDTO) Descriptor() ([]byte, []int) {
+	return file_statistics_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *WindowSlotDTO) GetRequests() uint64 {
+	if x != nil {
+		return x.Requests
+	}
+	return 0
+}
+
+func (x *WindowSlotDTO) GetFailures() uint64 {
+	if x != nil {
+		return x.Failures
+	}
+	return 0
+}
+
+func (x *WindowSlotDTO) GetTotalResponseTime() *durationpb.Duration {
+	if x != nil {
+		return x.TotalResponseTime
+	}
+	return nil
+}
+
+func (x *WindowSlotDTO) GetMinResponseTime() *durationpb.Duration {
+	if x != nil {
+		return x.MinResponseTime
+	}
+	return nil
+}
+
+func (x *WindowSlotDTO) GetMaxResponseTime() *durationpb.Duration {
+	if x != nil {
+		return x.MaxResponseTime
+	}
+	return nil
+}
+
+func (x *WindowSlotDTO) GetResponseBucket() map[int64]uint64 {
+	if x != nil {
+		return x.ResponseBucket
+	}
+	return nil
+}
+
+type SlidingWindowDTO struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Size  *durationpb.Duration     `protobuf:"bytes,1,opt,name=size,proto3" json:"size,omitempty"`
+	Slots map[int64]*WindowSlotDTO `protobuf:"bytes,2,rep,name=slots,proto3" json:"slots,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
-func (x *TagDTO) Reset() {
-	*x = TagDTO{}
+func (x *SlidingWindowDTO) Reset() {
+	*x = SlidingWindowDTO{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_statistics_proto_msgTypes[1]
+		mi := &file_statistics_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *TagDTO) String() string {
+func (x *SlidingWindowDTO) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TagDTO) ProtoMessage() {}
+func (*SlidingWindowDTO) ProtoMessage() {}
 
-func (x *TagDTO) ProtoReflect() protoreflect.Message {
-	mi := &file_statistics_proto_msgTypes[1]
+func (x *SlidingWindowDTO) ProtoReflect() protoreflect.Message {
+	mi := &file_statistics_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -201,51 +769,154 @@ func (x *TagDTO) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TagDTO.ProtoReflect.Descriptor instead.
-func (*TagDTO) Descriptor() ([]byte, []int) {
-	return file_statistics_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use SlidingWindowDTO.ProtoReflect.Descriptor instead.
+func (*SlidingWindowDTO) Descriptor() ([]byte, []int) {
+	return file_statistics_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *TagDTO) GetKey() string {
+func (x *SlidingWindowDTO) GetSize() *durationpb.Duration {
 	if x != nil {
-		return x.Key
+		return x.Size
+	}
+	return nil
+}
+
+func (x *SlidingWindowDTO) GetSlots() map[int64]*WindowSlotDTO {
+	if x != nil {
+		return x.Slots
+	}
+	return nil
+}
+
+type DiagnosticDTO struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Request         string                 `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+	RequestHeaders  map[string]string      `protobuf:"bytes,2,rep,name=request_headers,json=requestHeaders,proto3" json:"request_headers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	RequestBody     string                 `protobuf:"bytes,3,opt,name=request_body,json=requestBody,proto3" json:"request_body,omitempty"`
+	Status          string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	ResponseHeaders map[string]string      `protobuf:"bytes,5,rep,name=response_headers,json=responseHeaders,proto3" json:"response_headers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	ResponseBody    string                 `protobuf:"bytes,6,opt,name=response_body,json=responseBody,proto3" json:"response_body,omitempty"`
+	Error           string                 `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	At              *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=at,proto3" json:"at,omitempty"`
+}
+
+func (x *DiagnosticDTO) Reset() {
+	*x = DiagnosticDTO{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_statistics_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DiagnosticDTO) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiagnosticDTO) ProtoMessage() {}
+
+func (x *DiagnosticDTO) ProtoReflect() protoreflect.Message {
+	mi := &file_statistics_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiagnosticDTO.ProtoReflect.Descriptor instead.
+func (*DiagnosticDTO) Descriptor() ([]byte, []int) {
+	return file_statistics_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DiagnosticDTO) GetRequest() string {
+	if x != nil {
+		return x.Request
 	}
 	return ""
 }
 
-func (x *TagDTO) GetValue() string {
+func (x *DiagnosticDTO) GetRequestHeaders() map[string]string {
 	if x != nil {
-		return x.Value
+		return x.RequestHeaders
+	}
+	return nil
+}
+
+func (x *DiagnosticDTO) GetRequestBody() string {
+	if x != nil {
+		return x.RequestBody
 	}
 	return ""
 }
 
-type StatisticianGroupDTO struct {
+func (x *DiagnosticDTO) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *DiagnosticDTO) GetResponseHeaders() map[string]string {
+	if x != nil {
+		return x.ResponseHeaders
+	}
+	return nil
+}
+
+func (x *DiagnosticDTO) GetResponseBody() string {
+	if x != nil {
+		return x.ResponseBody
+	}
+	return ""
+}
+
+func (x *DiagnosticDTO) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *DiagnosticDTO) GetAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+type DiagnosticReservoirDTO struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Container map[string]*AttackStatisticsDTO `protobuf:"bytes,1,rep,name=container,proto3" json:"container,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	Tags      []*TagDTO                       `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
+	Seen    uint64           `protobuf:"varint,1,opt,name=seen,proto3" json:"seen,omitempty"`
+	Samples []*DiagnosticDTO `protobuf:"bytes,2,rep,name=samples,proto3" json:"samples,omitempty"`
 }
 
-func (x *StatisticianGroupDTO) Reset() {
-	*x = StatisticianGroupDTO{}
+func (x *DiagnosticReservoirDTO) Reset() {
+	*x = DiagnosticReservoirDTO{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_statistics_proto_msgTypes[2]
+		mi := &file_statistics_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *StatisticianGroupDTO) String() string {
+func (x *DiagnosticReservoirDTO) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StatisticianGroupDTO) ProtoMessage() {}
+func (*DiagnosticReservoirDTO) ProtoMessage() {}
 
-func (x *StatisticianGroupDTO) ProtoReflect() protoreflect.Message {
-	mi := &file_statistics_proto_msgTypes[2]
+func (x *DiagnosticReservoirDTO) ProtoReflect() protoreflect.Message {
+	mi := &file_statistics_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -256,21 +927,21 @@ func (x *StatisticianGroupDTO) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StatisticianGroupDTO.ProtoReflect.Descriptor instead.
-func (*StatisticianGroupDTO) Descriptor() ([]byte, []int) {
-	return file_statistics_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use DiagnosticReservoirDTO.ProtoReflect.Descriptor instead.
+func (*DiagnosticReservoirDTO) Descriptor() ([]byte, []int) {
+	return file_statistics_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *StatisticianGroupDTO) GetContainer() map[string]*AttackStatisticsDTO {
+func (x *DiagnosticReservoirDTO) GetSeen() uint64 {
 	if x != nil {
-		return x.Container
+		return x.Seen
 	}
-	return nil
+	return 0
 }
 
-func (x *StatisticianGroupDTO) GetTags() []*TagDTO {
+func (x *DiagnosticReservoirDTO) GetSamples() []*DiagnosticDTO {
 	if x != nil {
-		return x.Tags
+		return x.Samples
 	}
 	return nil
 }
@@ -284,7 +955,7 @@ var file_statistics_proto_rawDesc = []byte{
 	0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
 	0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
 	0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x22, 0x9f, 0x09, 0x0a, 0x13, 0x41, 0x74, 0x74, 0x61, 0x63, 0x6b, 0x53, 0x74, 0x61, 0x74,
+	0x6f, 0x22, 0xba, 0x0e, 0x0a, 0x13, 0x41, 0x74, 0x74, 0x61, 0x63, 0x6b, 0x53, 0x74, 0x61, 0x74,
 	0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x44, 0x54, 0x4f, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
 	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a,
 	0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52,
@@ -340,46 +1011,225 @@ var file_statistics_proto_rawDesc = []byte{
 	0x74, 0x41, 0x74, 0x74, 0x61, 0x63, 0x6b, 0x12, 0x35, 0x0a, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72,
 	0x76, 0x61, 0x6c, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
 	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x1a, 0x46,
-	0x0a, 0x18, 0x52, 0x65, 0x63, 0x65, 0x6e, 0x74, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x42,
-	0x75, 0x63, 0x6b, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
-	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x76, 0x61, 0x6c,
-	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x46, 0x0a, 0x18, 0x52, 0x65, 0x63, 0x65, 0x6e, 0x74,
-	0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x45, 0x6e, 0x74,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x12, 0x35,
+	0x0a, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x77, 0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x6c, 0x69, 0x6e, 0x65, 0x44, 0x54, 0x4f, 0x52, 0x08, 0x74, 0x69, 0x6d,
+	0x65, 0x6c, 0x69, 0x6e, 0x65, 0x12, 0x36, 0x0a, 0x06, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x18,
+	0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x77, 0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75, 0x6c,
+	0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x53, 0x6c, 0x69, 0x64, 0x69, 0x6e, 0x67, 0x57, 0x69, 0x6e, 0x64,
+	0x6f, 0x77, 0x44, 0x54, 0x4f, 0x52, 0x06, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x12, 0x5e, 0x0a,
+	0x0f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x5f, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73,
+	0x18, 0x10, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x35, 0x2e, 0x77, 0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75,
+	0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x41, 0x74, 0x74, 0x61, 0x63, 0x6b, 0x53, 0x74, 0x61, 0x74,
+	0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x44, 0x54, 0x4f, 0x2e, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72,
+	0x65, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0e, 0x66,
+	0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x12, 0x54, 0x0a,
+	0x0b, 0x64, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x73, 0x18, 0x11, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x32, 0x2e, 0x77, 0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75, 0x6c, 0x74, 0x72, 0x6f,
+	0x6e, 0x2e, 0x41, 0x74, 0x74, 0x61, 0x63, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69,
+	0x63, 0x73, 0x44, 0x54, 0x4f, 0x2e, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x64, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74,
+	0x69, 0x63, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x63, 0x65, 0x6e, 0x61, 0x72, 0x69, 0x6f, 0x18,
+	0x12, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x63, 0x65, 0x6e, 0x61, 0x72, 0x69, 0x6f, 0x12,
+	0x1c, 0x0a, 0x09, 0x74, 0x68, 0x72, 0x6f, 0x74, 0x74, 0x6c, 0x65, 0x64, 0x18, 0x13, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x09, 0x74, 0x68, 0x72, 0x6f, 0x74, 0x74, 0x6c, 0x65, 0x64, 0x12, 0x42, 0x0a,
+	0x0f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x74, 0x68, 0x72, 0x6f, 0x74, 0x74, 0x6c, 0x65, 0x64,
+	0x18, 0x14, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x0e, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x54, 0x68, 0x72, 0x6f, 0x74, 0x74, 0x6c, 0x65,
+	0x64, 0x12, 0x2c, 0x0a, 0x05, 0x74, 0x72, 0x61, 0x63, 0x65, 0x18, 0x15, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x16, 0x2e, 0x77, 0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e,
+	0x54, 0x72, 0x61, 0x63, 0x65, 0x44, 0x54, 0x4f, 0x52, 0x05, 0x74, 0x72, 0x61, 0x63, 0x65, 0x1a,
+	0x46, 0x0a, 0x18, 0x52, 0x65, 0x63, 0x65, 0x6e, 0x74, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73,
+	0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x46, 0x0a, 0x18, 0x52, 0x65, 0x63, 0x65, 0x6e,
+	0x74, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a,
+	0x41, 0x0a, 0x13, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x75, 0x63, 0x6b, 0x65,
+	0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
+	0x38, 0x01, 0x1a, 0x40, 0x0a, 0x12, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x42, 0x75, 0x63,
+	0x6b, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x3a, 0x02, 0x38, 0x01, 0x1a, 0x62, 0x0a, 0x13, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x53,
+	0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x35, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x77,
+	0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x46, 0x61, 0x69, 0x6c,
+	0x75, 0x72, 0x65, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x44, 0x54, 0x4f, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x64, 0x0a, 0x10, 0x44, 0x69, 0x61, 0x67,
+	0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x3a,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e,
+	0x77, 0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x44, 0x69, 0x61,
+	0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x6f, 0x69, 0x72,
+	0x44, 0x54, 0x4f, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xab,
+	0x01, 0x0a, 0x08, 0x50, 0x68, 0x61, 0x73, 0x65, 0x44, 0x54, 0x4f, 0x12, 0x14, 0x0a, 0x05, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x12, 0x2f, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x05, 0x74, 0x6f, 0x74,
+	0x61, 0x6c, 0x12, 0x2b, 0x0a, 0x03, 0x6d, 0x69, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x03, 0x6d, 0x69, 0x6e, 0x12,
+	0x2b, 0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x03, 0x6d, 0x61, 0x78, 0x22, 0xd6, 0x01, 0x0a,
+	0x08, 0x54, 0x72, 0x61, 0x63, 0x65, 0x44, 0x54, 0x4f, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x6e, 0x5f, 0x72, 0x65,
+	0x75, 0x73, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x6e,
+	0x52, 0x65, 0x75, 0x73, 0x65, 0x64, 0x12, 0x3a, 0x0a, 0x06, 0x70, 0x68, 0x61, 0x73, 0x65, 0x73,
+	0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x77, 0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75,
+	0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x65, 0x44, 0x54, 0x4f, 0x2e, 0x50,
+	0x68, 0x61, 0x73, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x70, 0x68, 0x61, 0x73,
+	0x65, 0x73, 0x1a, 0x51, 0x0a, 0x0b, 0x50, 0x68, 0x61, 0x73, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x2c, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x77, 0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75, 0x6c, 0x74, 0x72, 0x6f,
+	0x6e, 0x2e, 0x50, 0x68, 0x61, 0x73, 0x65, 0x44, 0x54, 0x4f, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x2f, 0x0a, 0x11, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65,
+	0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x44, 0x54, 0x4f, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x22, 0xc7, 0x01, 0x0a, 0x0f, 0x54, 0x69, 0x6d, 0x65, 0x6c,
+	0x69, 0x6e, 0x65, 0x53, 0x6c, 0x6f, 0x74, 0x44, 0x54, 0x4f, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x72, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72,
+	0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72,
+	0x65, 0x73, 0x12, 0x41, 0x0a, 0x06, 0x73, 0x6b, 0x65, 0x74, 0x63, 0x68, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x29, 0x2e, 0x77, 0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75, 0x6c, 0x74, 0x72, 0x6f,
+	0x6e, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x69, 0x6e, 0x65, 0x53, 0x6c, 0x6f, 0x74, 0x44, 0x54,
+	0x4f, 0x2e, 0x53, 0x6b, 0x65, 0x74, 0x63, 0x68, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x73,
+	0x6b, 0x65, 0x74, 0x63, 0x68, 0x1a, 0x39, 0x0a, 0x0b, 0x53, 0x6b, 0x65, 0x74, 0x63, 0x68, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x22, 0xfa, 0x01, 0x0a, 0x0b, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x69, 0x6e, 0x65, 0x44, 0x54, 0x4f,
+	0x12, 0x39, 0x0a, 0x0a, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x0a, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x6d,
+	0x61, 0x78, 0x5f, 0x73, 0x6c, 0x6f, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08,
+	0x6d, 0x61, 0x78, 0x53, 0x6c, 0x6f, 0x74, 0x73, 0x12, 0x3a, 0x0a, 0x05, 0x73, 0x6c, 0x6f, 0x74,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x77, 0x6f, 0x73, 0x61, 0x69, 0x2e,
+	0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x69, 0x6e, 0x65, 0x44,
+	0x54, 0x4f, 0x2e, 0x53, 0x6c, 0x6f, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x73,
+	0x6c, 0x6f, 0x74, 0x73, 0x1a, 0x57, 0x0a, 0x0a, 0x53, 0x6c, 0x6f, 0x74, 0x73, 0x45, 0x6e, 0x74,
 	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x03, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x41,
-	0x0a, 0x13, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74,
-	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x03, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
-	0x01, 0x1a, 0x40, 0x0a, 0x12, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x42, 0x75, 0x63, 0x6b,
-	0x65, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x33, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x77, 0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75, 0x6c, 0x74, 0x72,
+	0x6f, 0x6e, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x69, 0x6e, 0x65, 0x53, 0x6c, 0x6f, 0x74, 0x44,
+	0x54, 0x4f, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x30, 0x0a,
+	0x06, 0x54, 0x61, 0x67, 0x44, 0x54, 0x4f, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
-	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
-	0x02, 0x38, 0x01, 0x22, 0x30, 0x0a, 0x06, 0x54, 0x61, 0x67, 0x44, 0x54, 0x4f, 0x12, 0x10, 0x0a,
-	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
-	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0xf2, 0x01, 0x0a, 0x14, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73,
-	0x74, 0x69, 0x63, 0x69, 0x61, 0x6e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x44, 0x54, 0x4f, 0x12, 0x4f,
-	0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x18, 0x01, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x31, 0x2e, 0x77, 0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e,
-	0x2e, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x69, 0x61, 0x6e, 0x47, 0x72, 0x6f,
-	0x75, 0x70, 0x44, 0x54, 0x4f, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x45,
-	0x6e, 0x74, 0x72, 0x79, 0x52, 0x09, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x12,
-	0x28, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e,
-	0x77, 0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x54, 0x61, 0x67,
-	0x44, 0x54, 0x4f, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x1a, 0x5f, 0x0a, 0x0e, 0x43, 0x6f, 0x6e,
-	0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
-	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x37, 0x0a,
-	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x77,
-	0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x41, 0x74, 0x74, 0x61,
-	0x63, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x44, 0x54, 0x4f, 0x52,
-	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x2b, 0x5a, 0x29, 0x67, 0x69,
-	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x77, 0x6f, 0x73, 0x61, 0x69, 0x2f, 0x75,
-	0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2f, 0x76, 0x32, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x73, 0x74, 0x61,
-	0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22,
+	0xf2, 0x01, 0x0a, 0x14, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x69, 0x61, 0x6e,
+	0x47, 0x72, 0x6f, 0x75, 0x70, 0x44, 0x54, 0x4f, 0x12, 0x4f, 0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x74,
+	0x61, 0x69, 0x6e, 0x65, 0x72, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x31, 0x2e, 0x77, 0x6f,
+	0x73, 0x61, 0x69, 0x2e, 0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x69,
+	0x73, 0x74, 0x69, 0x63, 0x69, 0x61, 0x6e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x44, 0x54, 0x4f, 0x2e,
+	0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x09,
+	0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x12, 0x28, 0x0a, 0x04, 0x74, 0x61, 0x67,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x77, 0x6f, 0x73, 0x61, 0x69, 0x2e,
+	0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x54, 0x61, 0x67, 0x44, 0x54, 0x4f, 0x52, 0x04, 0x74,
+	0x61, 0x67, 0x73, 0x1a, 0x5f, 0x0a, 0x0e, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x37, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x77, 0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75,
+	0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x41, 0x74, 0x74, 0x61, 0x63, 0x6b, 0x53, 0x74, 0x61, 0x74,
+	0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x44, 0x54, 0x4f, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x3a, 0x02, 0x38, 0x01, 0x22, 0xbd, 0x03, 0x0a, 0x0d, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x53,
+	0x6c, 0x6f, 0x74, 0x44, 0x54, 0x4f, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x12, 0x49,
+	0x0a, 0x13, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x11, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x45, 0x0a, 0x11, 0x6d, 0x69, 0x6e,
+	0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x0f, 0x6d, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x54, 0x69, 0x6d, 0x65,
+	0x12, 0x45, 0x0a, 0x11, 0x6d, 0x61, 0x78, 0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0f, 0x6d, 0x61, 0x78, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x58, 0x0a, 0x0f, 0x72, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x5f, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x2f, 0x2e, 0x77, 0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e,
+	0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x53, 0x6c, 0x6f, 0x74, 0x44, 0x54, 0x4f, 0x2e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x0e, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x75, 0x63, 0x6b, 0x65,
+	0x74, 0x1a, 0x41, 0x0a, 0x13, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x75, 0x63,
+	0x6b, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x3a, 0x02, 0x38, 0x01, 0x22, 0xd9, 0x01, 0x0a, 0x10, 0x53, 0x6c, 0x69, 0x64, 0x69, 0x6e, 0x67,
+	0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x44, 0x54, 0x4f, 0x12, 0x2d, 0x0a, 0x04, 0x73, 0x69, 0x7a,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x3f, 0x0a, 0x05, 0x73, 0x6c, 0x6f, 0x74,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x77, 0x6f, 0x73, 0x61, 0x69, 0x2e,
+	0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x53, 0x6c, 0x69, 0x64, 0x69, 0x6e, 0x67, 0x57, 0x69,
+	0x6e, 0x64, 0x6f, 0x77, 0x44, 0x54, 0x4f, 0x2e, 0x53, 0x6c, 0x6f, 0x74, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x52, 0x05, 0x73, 0x6c, 0x6f, 0x74, 0x73, 0x1a, 0x55, 0x0a, 0x0a, 0x53, 0x6c, 0x6f,
+	0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x31, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x77, 0x6f, 0x73, 0x61, 0x69,
+	0x2e, 0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x53, 0x6c,
+	0x6f, 0x74, 0x44, 0x54, 0x4f, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x22, 0x89, 0x04, 0x0a, 0x0d, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x44,
+	0x54, 0x4f, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x58, 0x0a, 0x0f,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x77, 0x6f, 0x73, 0x61, 0x69, 0x2e, 0x75, 0x6c,
+	0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x44,
+	0x54, 0x4f, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0e, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48,
+	0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x5f, 0x62, 0x6f, 0x64, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x72, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x42, 0x6f, 0x64, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x5b, 0x0a, 0x10, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x5f, 0x68, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x30, 0x2e, 0x77, 0x6f,
+	0x73, 0x61, 0x69, 0x2e, 0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x44, 0x69, 0x61, 0x67, 0x6e,
+	0x6f, 0x73, 0x74, 0x69, 0x63, 0x44, 0x54, 0x4f, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0f, 0x72,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x12, 0x23,
+	0x0a, 0x0d, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x5f, 0x62, 0x6f, 0x64, 0x79, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42,
+	0x6f, 0x64, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x2a, 0x0a, 0x02, 0x61, 0x74, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x02, 0x61, 0x74, 0x1a, 0x41, 0x0a, 0x13, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x42, 0x0a, 0x14, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x63, 0x0a, 0x16,
+	0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76,
+	0x6f, 0x69, 0x72, 0x44, 0x54, 0x4f, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x65, 0x65, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x73, 0x65, 0x65, 0x6e, 0x12, 0x35, 0x0a, 0x07, 0x73, 0x61,
+	0x6d, 0x70, 0x6c, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x77, 0x6f,
+	0x73, 0x61, 0x69, 0x2e, 0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2e, 0x44, 0x69, 0x61, 0x67, 0x6e,
+	0x6f, 0x73, 0x74, 0x69, 0x63, 0x44, 0x54, 0x4f, 0x52, 0x07, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65,
+	0x73, 0x42, 0x2b, 0x5a, 0x29, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x77, 0x6f, 0x73, 0x61, 0x69, 0x2f, 0x75, 0x6c, 0x74, 0x72, 0x6f, 0x6e, 0x2f, 0x76, 0x32, 0x2f,
+	0x70, 0x6b, 0x67, 0x2f, 0x73, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -394,38 +1244,84 @@ func file_statistics_proto_rawDescGZIP() []byte {
 	return file_statistics_proto_rawDescData
 }
 
-var file_statistics_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_statistics_proto_msgTypes = make([]protoimpl.MessageInfo, 26)
 var file_statistics_proto_goTypes = []interface{}{
-	(*AttackStatisticsDTO)(nil),   // 0: wosai.ultron.AttackStatisticsDTO
-	(*TagDTO)(nil),                // 1: wosai.ultron.TagDTO
-	(*StatisticianGroupDTO)(nil),  // 2: wosai.ultron.StatisticianGroupDTO
-	nil,                           // 3: wosai.ultron.AttackStatisticsDTO.RecentSuccessBucketEntry
-	nil,                           // 4: wosai.ultron.AttackStatisticsDTO.RecentFailureBucketEntry
-	nil,                           // 5: wosai.ultron.AttackStatisticsDTO.ResponseBucketEntry
-	nil,                           // 6: wosai.ultron.AttackStatisticsDTO.FailureBucketEntry
-	nil,                           // 7: wosai.ultron.StatisticianGroupDTO.ContainerEntry
-	(*durationpb.Duration)(nil),   // 8: google.protobuf.Duration
-	(*timestamppb.Timestamp)(nil), // 9: google.protobuf.Timestamp
+	(*AttackStatisticsDTO)(nil),    // 0: wosai.ultron.AttackStatisticsDTO
+	(*PhaseDTO)(nil),               // 1: wosai.ultron.PhaseDTO
+	(*TraceDTO)(nil),               // 2: wosai.ultron.TraceDTO
+	(*FailureSamplesDTO)(nil),      // 3: wosai.ultron.FailureSamplesDTO
+	(*TimelineSlotDTO)(nil),        // 4: wosai.ultron.TimelineSlotDTO
+	(*TimelineDTO)(nil),            // 5: wosai.ultron.TimelineDTO
+	(*TagDTO)(nil),                 // 6: wosai.ultron.TagDTO
+	(*StatisticianGroupDTO)(nil),   // 7: wosai.ultron.StatisticianGroupDTO
+	(*WindowSlotDTO)(nil),          // 8: wosai.ultron.WindowSlotDTO
+	(*SlidingWindowDTO)(nil),       // 9: wosai.ultron.SlidingWindowDTO
+	(*DiagnosticDTO)(nil),          // 10: wosai.ultron.DiagnosticDTO
+	(*DiagnosticReservoirDTO)(nil), // 11: wosai.ultron.DiagnosticReservoirDTO
+	nil,                            // 12: wosai.ultron.AttackStatisticsDTO.RecentSuccessBucketEntry
+	nil,                            // 13: wosai.ultron.AttackStatisticsDTO.RecentFailureBucketEntry
+	nil,                            // 14: wosai.ultron.AttackStatisticsDTO.ResponseBucketEntry
+	nil,                            // 15: wosai.ultron.AttackStatisticsDTO.FailureBucketEntry
+	nil,                            // 16: wosai.ultron.AttackStatisticsDTO.FailureSamplesEntry
+	nil,                            // 17: wosai.ultron.AttackStatisticsDTO.DiagnosticsEntry
+	nil,                            // 18: wosai.ultron.TraceDTO.PhasesEntry
+	nil,                            // 19: wosai.ultron.TimelineSlotDTO.SketchEntry
+	nil,                            // 20: wosai.ultron.TimelineDTO.SlotsEntry
+	nil,                            // 21: wosai.ultron.StatisticianGroupDTO.ContainerEntry
+	nil,                            // 22: wosai.ultron.WindowSlotDTO.ResponseBucketEntry
+	nil,                            // 23: wosai.ultron.SlidingWindowDTO.SlotsEntry
+	nil,                            // 24: wosai.ultron.DiagnosticDTO.RequestHeadersEntry
+	nil,                            // 25: wosai.ultron.DiagnosticDTO.ResponseHeadersEntry
+	(*durationpb.Duration)(nil),    // 26: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil),  // 27: google.protobuf.Timestamp
 }
 var file_statistics_proto_depIdxs = []int32{
-	8,  // 0: wosai.ultron.AttackStatisticsDTO.total_response_time:type_name -> google.protobuf.Duration
-	8,  // 1: wosai.ultron.AttackStatisticsDTO.min_response_time:type_name -> google.protobuf.Duration
-	8,  // 2: wosai.ultron.AttackStatisticsDTO.max_response_time:type_name -> google.protobuf.Duration
-	3,  // 3: wosai.ultron.AttackStatisticsDTO.recent_success_bucket:type_name -> wosai.ultron.AttackStatisticsDTO.RecentSuccessBucketEntry
-	4,  // 4: wosai.ultron.AttackStatisticsDTO.recent_failure_bucket:type_name -> wosai.ultron.AttackStatisticsDTO.RecentFailureBucketEntry
-	5,  // 5: wosai.ultron.AttackStatisticsDTO.response_bucket:type_name -> wosai.ultron.AttackStatisticsDTO.ResponseBucketEntry
-	6,  // 6: wosai.ultron.AttackStatisticsDTO.failure_bucket:type_name -> wosai.ultron.AttackStatisticsDTO.FailureBucketEntry
-	9,  // 7: wosai.ultron.AttackStatisticsDTO.first_attack:type_name -> google.protobuf.Timestamp
-	9,  // 8: wosai.ultron.AttackStatisticsDTO.last_attack:type_name -> google.protobuf.Timestamp
-	8,  // 9: wosai.ultron.AttackStatisticsDTO.interval:type_name -> google.protobuf.Duration
-	7,  // 10: wosai.ultron.StatisticianGroupDTO.container:type_name -> wosai.ultron.StatisticianGroupDTO.ContainerEntry
-	1,  // 11: wosai.ultron.StatisticianGroupDTO.tags:type_name -> wosai.ultron.TagDTO
-	0,  // 12: wosai.ultron.StatisticianGroupDTO.ContainerEntry.value:type_name -> wosai.ultron.AttackStatisticsDTO
-	13, // [13:13] is the sub-list for method output_type
-	13, // [13:13] is the sub-list for method input_type
-	13, // [13:13] is the sub-list for extension type_name
-	13, // [13:13] is the sub-list for extension extendee
-	0,  // [0:13] is the sub-list for field type_name
+	26, // 0: wosai.ultron.AttackStatisticsDTO.total_response_time:type_name -> google.protobuf.Duration
+	26, // 1: wosai.ultron.AttackStatisticsDTO.min_response_time:type_name -> google.protobuf.Duration
+	26, // 2: wosai.ultron.AttackStatisticsDTO.max_response_time:type_name -> google.protobuf.Duration
+	12, // 3: wosai.ultron.AttackStatisticsDTO.recent_success_bucket:type_name -> wosai.ultron.AttackStatisticsDTO.RecentSuccessBucketEntry
+	13, // 4: wosai.ultron.AttackStatisticsDTO.recent_failure_bucket:type_name -> wosai.ultron.AttackStatisticsDTO.RecentFailureBucketEntry
+	14, // 5: wosai.ultron.AttackStatisticsDTO.response_bucket:type_name -> wosai.ultron.AttackStatisticsDTO.ResponseBucketEntry
+	15, // 6: wosai.ultron.AttackStatisticsDTO.failure_bucket:type_name -> wosai.ultron.AttackStatisticsDTO.FailureBucketEntry
+	27, // 7: wosai.ultron.AttackStatisticsDTO.first_attack:type_name -> google.protobuf.Timestamp
+	27, // 8: wosai.ultron.AttackStatisticsDTO.last_attack:type_name -> google.protobuf.Timestamp
+	26, // 9: wosai.ultron.AttackStatisticsDTO.interval:type_name -> google.protobuf.Duration
+	5,  // 10: wosai.ultron.AttackStatisticsDTO.timeline:type_name -> wosai.ultron.TimelineDTO
+	9,  // 11: wosai.ultron.AttackStatisticsDTO.window:type_name -> wosai.ultron.SlidingWindowDTO
+	16, // 12: wosai.ultron.AttackStatisticsDTO.failure_samples:type_name -> wosai.ultron.AttackStatisticsDTO.FailureSamplesEntry
+	17, // 13: wosai.ultron.AttackStatisticsDTO.diagnostics:type_name -> wosai.ultron.AttackStatisticsDTO.DiagnosticsEntry
+	26, // 14: wosai.ultron.AttackStatisticsDTO.total_throttled:type_name -> google.protobuf.Duration
+	2,  // 15: wosai.ultron.AttackStatisticsDTO.trace:type_name -> wosai.ultron.TraceDTO
+	26, // 16: wosai.ultron.PhaseDTO.total:type_name -> google.protobuf.Duration
+	26, // 17: wosai.ultron.PhaseDTO.min:type_name -> google.protobuf.Duration
+	26, // 18: wosai.ultron.PhaseDTO.max:type_name -> google.protobuf.Duration
+	18, // 19: wosai.ultron.TraceDTO.phases:type_name -> wosai.ultron.TraceDTO.PhasesEntry
+	19, // 20: wosai.ultron.TimelineSlotDTO.sketch:type_name -> wosai.ultron.TimelineSlotDTO.SketchEntry
+	26, // 21: wosai.ultron.TimelineDTO.resolution:type_name -> google.protobuf.Duration
+	20, // 22: wosai.ultron.TimelineDTO.slots:type_name -> wosai.ultron.TimelineDTO.SlotsEntry
+	21, // 23: wosai.ultron.StatisticianGroupDTO.container:type_name -> wosai.ultron.StatisticianGroupDTO.ContainerEntry
+	6,  // 24: wosai.ultron.StatisticianGroupDTO.tags:type_name -> wosai.ultron.TagDTO
+	26, // 25: wosai.ultron.WindowSlotDTO.total_response_time:type_name -> google.protobuf.Duration
+	26, // 26: wosai.ultron.WindowSlotDTO.min_response_time:type_name -> google.protobuf.Duration
+	26, // 27: wosai.ultron.WindowSlotDTO.max_response_time:type_name -> google.protobuf.Duration
+	22, // 28: wosai.ultron.WindowSlotDTO.response_bucket:type_name -> wosai.ultron.WindowSlotDTO.ResponseBucketEntry
+	26, // 29: wosai.ultron.SlidingWindowDTO.size:type_name -> google.protobuf.Duration
+	23, // 30: wosai.ultron.SlidingWindowDTO.slots:type_name -> wosai.ultron.SlidingWindowDTO.SlotsEntry
+	24, // 31: wosai.ultron.DiagnosticDTO.request_headers:type_name -> wosai.ultron.DiagnosticDTO.RequestHeadersEntry
+	25, // 32: wosai.ultron.DiagnosticDTO.response_headers:type_name -> wosai.ultron.DiagnosticDTO.ResponseHeadersEntry
+	27, // 33: wosai.ultron.DiagnosticDTO.at:type_name -> google.protobuf.Timestamp
+	10, // 34: wosai.ultron.DiagnosticReservoirDTO.samples:type_name -> wosai.ultron.DiagnosticDTO
+	3,  // 35: wosai.ultron.AttackStatisticsDTO.FailureSamplesEntry.value:type_name -> wosai.ultron.FailureSamplesDTO
+	11, // 36: wosai.ultron.AttackStatisticsDTO.DiagnosticsEntry.value:type_name -> wosai.ultron.DiagnosticReservoirDTO
+	1,  // 37: wosai.ultron.TraceDTO.PhasesEntry.value:type_name -> wosai.ultron.PhaseDTO
+	4,  // 38: wosai.ultron.TimelineDTO.SlotsEntry.value:type_name -> wosai.ultron.TimelineSlotDTO
+	0,  // 39: wosai.ultron.StatisticianGroupDTO.ContainerEntry.value:type_name -> wosai.ultron.AttackStatisticsDTO
+	8,  // 40: wosai.ultron.SlidingWindowDTO.SlotsEntry.value:type_name -> wosai.ultron.WindowSlotDTO
+	41, // [41:41] is the sub-list for method output_type
+	41, // [41:41] is the sub-list for method input_type
+	41, // [41:41] is the sub-list for extension type_name
+	41, // [41:41] is the sub-list for extension extendee
+	0,  // [0:41] is the sub-list for field type_name
 }
 
 func init() { file_statistics_proto_init() }
@@ -447,7 +1343,7 @@ func file_statistics_proto_init() {
 			}
 		}
 		file_statistics_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*TagDTO); i {
+			switch v := v.(*PhaseDTO); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -459,6 +1355,66 @@ func file_statistics_proto_init() {
 			}
 		}
 		file_statistics_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TraceDTO); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_statistics_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FailureSamplesDTO); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_statistics_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TimelineSlotDTO); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_statistics_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TimelineDTO); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_statistics_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TagDTO); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_statistics_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*StatisticianGroupDTO); i {
 			case 0:
 				return &v.state
@@ -470,6 +1426,54 @@ func file_statistics_proto_init() {
 				return nil
 			}
 		}
+		file_statistics_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WindowSlotDTO); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_statistics_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SlidingWindowDTO); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_statistics_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DiagnosticDTO); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_statistics_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DiagnosticReservoirDTO); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -477,7 +1481,7 @@ func file_statistics_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_statistics_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   26,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -178,6 +178,56 @@ func TestAttackResultAggregator_MergeEmptry(t *testing.T) {
 	log.Println(string(data))
 }
 
+func TestStatisticianGroup_Scenario(t *testing.T) {
+	sg := NewStatisticianGroup()
+	sg.Record(AttackResult{Name: "read", Duration: time.Millisecond})
+	sg.Record(AttackResult{Name: "read", Duration: time.Millisecond, Scenario: "checkout"})
+	sg.Record(AttackResult{Name: "read", Duration: time.Millisecond, Scenario: "checkout"})
+
+	other := NewStatisticianGroup()
+	other.Record(AttackResult{Name: "read", Duration: time.Millisecond, Scenario: "checkout"})
+	sg.Merge(other)
+
+	dto, err := ConvertStatisticianGroup(sg)
+	assert.Nil(t, err)
+	converted, err := NewStatisticianGroupFromDTO(dto)
+	assert.Nil(t, err)
+
+	for _, group := range []*StatisticianGroup{sg, converted} {
+		report := group.Report(true)
+		assert.Len(t, report.Reports, 2)
+		assert.EqualValues(t, 1, report.Reports["read"].Requests)
+		assert.EqualValues(t, "", report.Reports["read"].Scenario)
+		assert.EqualValues(t, 3, report.Reports[ScenarioKey("checkout", "read")].Requests)
+		assert.EqualValues(t, "checkout", report.Reports["checkout/read"].Scenario)
+	}
+
+	a1 := NewAttackStatistician("read")
+	assert.Error(t, a1.merge(NewAttackStatistician("read", WithScenario("checkout"))))
+}
+
+func TestAttackStatistician_Throttled(t *testing.T) {
+	as := NewAttackStatistician("pay")
+	as.Record(AttackResult{Name: "pay", Duration: time.Millisecond, Throttled: 100 * time.Millisecond})
+	as.Record(AttackResult{Name: "pay", Duration: time.Millisecond})
+	as.Record(AttackResult{Name: "pay", Error: errors.New("unknown"), Throttled: 50 * time.Millisecond})
+
+	other := NewAttackStatistician("pay")
+	other.Record(AttackResult{Name: "pay", Duration: time.Millisecond, Throttled: 50 * time.Millisecond})
+	assert.Nil(t, as.merge(other))
+
+	dto, err := ConvertAttackStatistician(as)
+	assert.Nil(t, err)
+	converted, err := NewAttackStatisticianFromDTO(dto)
+	assert.Nil(t, err)
+	for _, s := range []*AttackStatistician{as, converted} {
+		report := s.Report(true)
+		assert.EqualValues(t, 3, report.Throttled)
+		assert.EqualValues(t, 200*time.Millisecond, report.ThrottledTime)
+		assert.EqualValues(t, time.Millisecond, report.Max) // 等待令牌的时长不计入延迟
+	}
+}
+
 func TestStatisticianGroup_Attach(t *testing.T) {
 	sg := NewStatisticianGroup()
 	sg.Attach(Tag{Key: "plan", Value: "hello"})
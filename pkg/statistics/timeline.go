@@ -0,0 +1,210 @@
+package statistics
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	sketchGamma = 1.05 // 相邻桶的比例，相对误差约2.5%
+)
+
+var (
+	timelineDistributions = []float64{0.5, 0.9, 0.95, 0.99}
+	sketchLogGamma        = math.Log(sketchGamma)
+)
+
+type (
+	// StatisticianOption AttackStatistician的配置项
+	StatisticianOption func(*AttackStatistician)
+
+	// TimelinePoint 时间线上一个时间片的统计
+	TimelinePoint struct {
+		Start         time.Time                `json:"start"`
+		Requests      uint64                   `json:"requests"`
+		Failures      uint64                   `json:"failures"`
+		TPS           float64                  `json:"tps"`
+		Distributions map[string]time.Duration `json:"distributions,omitempty"` // 该时间片内成功请求的百分位分布
+	}
+
+	// latencySketch 对数分桶的响应时间草图，可以直接相加合并
+	latencySketch map[int32]uint64
+
+	timelineSlot struct {
+		requests uint64
+		failures uint64
+		sketch   latencySketch
+	}
+
+	// timeline 按固定时间片统计的时间线，时间片数量超出上限时分辨率翻倍
+	timeline struct {
+		resolution time.Duration
+		maxSlots   int
+		slots      map[int64]*timelineSlot // key: unix纳秒 / resolution
+	}
+)
+
+// WithTimeline 开启时间线统计，resolution为时间片长度，maxSlots为时间片数量上限
+func WithTimeline(resolution time.Duration, maxSlots int) StatisticianOption {
+	return func(as *AttackStatistician) {
+		if resolution <= 0 {
+			return
+		}
+		as.timeline = newTimeline(resolution, maxSlots)
+	}
+}
+
+func newTimeline(resolution time.Duration, maxSlots int) *timeline {
+	if maxSlots < 1 {
+		maxSlots = 1
+	}
+	return &timeline{
+		resolution: resolution,
+		maxSlots:   maxSlots,
+		slots:      make(map[int64]*timelineSlot),
+	}
+}
+
+func findSketchBucket(d time.Duration) int32 {
+	us := float64(d) / float64(time.Microsecond)
+	if us <= 1 {
+		return 0
+	}
+	return int32(math.Ceil(math.Log(us) / sketchLogGamma))
+}
+
+// sketchBucketValue 桶的代表值，取桶上下界的中点
+func sketchBucketValue(index int32) time.Duration {
+	if index <= 0 {
+		return time.Microsecond
+	}
+	upper := math.Pow(sketchGamma, float64(index))
+	return time.Duration(2 * upper / (sketchGamma + 1) * float64(time.Microsecond))
+}
+
+func (ls latencySketch) percentile(total uint64, ps ...float64) []time.Duration {
+	keys := make([]int32, 0, len(ls))
+	for k := range ls {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	results := make([]time.Duration, len(ps))
+	if total == 0 || len(keys) == 0 {
+		return results
+	}
+	for n, per := range ps {
+		rank := uint64(math.Ceil(float64(total) * per))
+		if rank == 0 {
+			rank = 1
+		}
+		var count uint64
+		results[n] = sketchBucketValue(keys[len(keys)-1])
+		for _, k := range keys {
+			count += ls[k]
+			if count >= rank {
+				results[n] = sketchBucketValue(k)
+				break
+			}
+		}
+	}
+	return results
+}
+
+func (tl *timeline) slotOf(t time.Time) *timelineSlot {
+	key := t.UnixNano() / int64(tl.resolution)
+	slot, ok := tl.slots[key]
+	if !ok {
+		slot = &timelineSlot{sketch: make(latencySketch)}
+		tl.slots[key] = slot
+		tl.compact()
+	}
+	return slot
+}
+
+func (tl *timeline) recordSuccess(t time.Time, d time.Duration) {
+	slot := tl.slotOf(t)
+	slot.requests++
+	slot.sketch[findSketchBucket(d)]++
+}
+
+func (tl *timeline) recordFailure(t time.Time) {
+	tl.slotOf(t).failures++
+}
+
+func (slot *timelineSlot) add(other *timelineSlot) {
+	slot.requests += other.requests
+	slot.failures += other.failures
+	for k, v := range other.sketch {
+		slot.sketch[k] += v
+	}
+}
+
+// rescale 将时间片调整为更粗的分辨率
+func (tl *timeline) rescale(resolution time.Duration) {
+	if resolution <= tl.resolution {
+		return
+	}
+	slots := make(map[int64]*timelineSlot)
+	for key, slot := range tl.slots {
+		k := key * int64(tl.resolution) / int64(resolution)
+		if s, ok := slots[k]; ok {
+			s.add(slot)
+		} else {
+			slots[k] = slot
+		}
+	}
+	tl.resolution = resolution
+	tl.slots = slots
+}
+
+func (tl *timeline) compact() {
+	for len(tl.slots) > tl.maxSlots {
+		tl.rescale(2 * tl.resolution)
+	}
+}
+
+func (tl *timeline) merge(other *timeline) {
+	if other.maxSlots > tl.maxSlots {
+		tl.maxSlots = other.maxSlots
+	}
+	tl.rescale(other.resolution)
+	for key, slot := range other.slots {
+		k := key * int64(other.resolution) / int64(tl.resolution)
+		s, ok := tl.slots[k]
+		if !ok {
+			s = &timelineSlot{sketch: make(latencySketch)}
+			tl.slots[k] = s
+		}
+		s.add(slot)
+	}
+	tl.compact()
+}
+
+// points 按时间升序输出时间线
+func (tl *timeline) points() []TimelinePoint {
+	keys := make([]int64, 0, len(tl.slots))
+	for k := range tl.slots {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	ret := make([]TimelinePoint, len(keys))
+	for i, k := range keys {
+		slot := tl.slots[k]
+		ret[i] = TimelinePoint{
+			Start:         time.Unix(0, k*int64(tl.resolution)),
+			Requests:      slot.requests,
+			Failures:      slot.failures,
+			TPS:           float64(slot.requests) / tl.resolution.Seconds(),
+			Distributions: make(map[string]time.Duration),
+		}
+		pers := slot.sketch.percentile(slot.requests, timelineDistributions...)
+		for n, d := range timelineDistributions {
+			ret[i].Distributions[strconv.FormatFloat(d, 'f', 2, 64)] = pers[n]
+		}
+	}
+	return ret
+}
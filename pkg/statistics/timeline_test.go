@@ -0,0 +1,72 @@
+package statistics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatencySketch_Percentile(t *testing.T) {
+	sketch := make(latencySketch)
+	for i := 1; i <= 1000; i++ {
+		sketch[findSketchBucket(time.Duration(i)*time.Millisecond)]++
+	}
+	pers := sketch.percentile(1000, 0.5, 0.95, 0.99)
+	for n, expected := range []time.Duration{500 * time.Millisecond, 950 * time.Millisecond, 990 * time.Millisecond} {
+		assert.LessOrEqual(t, math.Abs(float64(pers[n]-expected))/float64(expected), 0.03)
+	}
+	assert.EqualValues(t, latencySketch{}.percentile(0, 0.5), []time.Duration{0})
+}
+
+func TestTimeline_Compact(t *testing.T) {
+	tl := newTimeline(time.Second, 10)
+	begin := time.Unix(1600000000, 0)
+	for i := 0; i < 100; i++ {
+		tl.recordSuccess(begin.Add(time.Duration(i)*time.Second), 10*time.Millisecond)
+	}
+	tl.recordFailure(begin)
+	assert.LessOrEqual(t, len(tl.slots), 10)
+	assert.EqualValues(t, tl.resolution, 16*time.Second)
+
+	var requests, failures uint64
+	for _, p := range tl.points() {
+		requests += p.Requests
+		failures += p.Failures
+	}
+	assert.EqualValues(t, requests, 100)
+	assert.EqualValues(t, failures, 1)
+}
+
+func TestTimeline_Merge(t *testing.T) {
+	begin := time.Unix(1600000000, 0)
+	fine := newTimeline(time.Second, 100)
+	coarse := newTimeline(2*time.Second, 100)
+	for i := 0; i < 10; i++ {
+		fine.recordSuccess(begin.Add(time.Duration(i)*time.Second), 10*time.Millisecond)
+		coarse.recordSuccess(begin.Add(time.Duration(i)*time.Second), 20*time.Millisecond)
+	}
+
+	fine.merge(coarse)
+	assert.EqualValues(t, fine.resolution, 2*time.Second)
+	points := fine.points()
+	assert.EqualValues(t, len(points), 5)
+	for _, p := range points {
+		assert.EqualValues(t, p.Requests, 4)
+		assert.EqualValues(t, p.TPS, 2)
+		assert.True(t, p.Start.Sub(begin)%(2*time.Second) == 0)
+	}
+}
+
+func TestAttackStatistician_Timeline(t *testing.T) {
+	as := NewAttackStatistician("foobar", WithTimeline(time.Second, 60))
+	as.Record(AttackResult{Name: "foobar", Duration: 100 * time.Millisecond})
+	assert.Empty(t, as.Report(false).Timeline)
+	assert.EqualValues(t, len(as.Report(true).Timeline), 1)
+
+	other := NewAttackStatistician("foobar")
+	assert.Nil(t, other.merge(as))
+	assert.EqualValues(t, other.Report(true).Timeline[0].Requests, 1)
+	assert.Empty(t, NewAttackStatistician("foobar").Report(true).Timeline)
+}
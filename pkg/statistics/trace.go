@@ -0,0 +1,104 @@
+package statistics
+
+import (
+	"time"
+)
+
+type (
+	// Trace 一次请求各个阶段的耗时，由开启追踪的Attacker提供
+	Trace struct {
+		Phases     map[string]time.Duration // key: 阶段名称，未经历的阶段不出现，如复用连接时没有建连
+		ConnReused bool                     // 是否复用了连接
+	}
+
+	// TraceReport 开启追踪的请求的分阶段统计
+	TraceReport struct {
+		Requests   uint64                 `json:"requests"`         // 开启追踪的请求数，包含失败请求
+		ConnReused uint64                 `json:"conn_reused"`      // 复用连接的请求数
+		Phases     map[string]PhaseReport `json:"phases,omitempty"` // 各阶段的统计
+	}
+
+	// PhaseReport 单个阶段的统计
+	PhaseReport struct {
+		Count   uint64        `json:"count"`   // 经历该阶段的请求数
+		Min     time.Duration `json:"min"`     // 最小耗时
+		Max     time.Duration `json:"max"`     // 最大耗时
+		Average time.Duration `json:"average"` // 平均耗时
+		Total   time.Duration `json:"total"`   // 总耗时
+	}
+
+	phaseStats struct {
+		count uint64
+		total time.Duration
+		min   time.Duration
+		max   time.Duration
+	}
+
+	// traceStats 按阶段汇总的追踪信息
+	traceStats struct {
+		requests   uint64
+		connReused uint64
+		phases     map[string]*phaseStats
+	}
+)
+
+func newTraceStats() *traceStats {
+	return &traceStats{phases: make(map[string]*phaseStats)}
+}
+
+func (ts *traceStats) record(t *Trace) {
+	ts.requests++
+	if t.ConnReused {
+		ts.connReused++
+	}
+	for name, d := range t.Phases {
+		ts.phaseOf(name).merge(&phaseStats{count: 1, total: d, min: d, max: d})
+	}
+}
+
+func (ts *traceStats) phaseOf(name string) *phaseStats {
+	ps, ok := ts.phases[name]
+	if !ok {
+		ps = new(phaseStats)
+		ts.phases[name] = ps
+	}
+	return ps
+}
+
+func (ts *traceStats) merge(other *traceStats) {
+	ts.requests += other.requests
+	ts.connReused += other.connReused
+	for name, ps := range other.phases {
+		ts.phaseOf(name).merge(ps)
+	}
+}
+
+func (ts *traceStats) report() *TraceReport {
+	report := &TraceReport{
+		Requests:   ts.requests,
+		ConnReused: ts.connReused,
+		Phases:     make(map[string]PhaseReport, len(ts.phases)),
+	}
+	for name, ps := range ts.phases {
+		pr := PhaseReport{Count: ps.count, Min: ps.min, Max: ps.max, Total: ps.total}
+		if ps.count > 0 {
+			pr.Average = ps.total / time.Duration(ps.count)
+		}
+		report.Phases[name] = pr
+	}
+	return report
+}
+
+func (ps *phaseStats) merge(other *phaseStats) {
+	if other.count == 0 {
+		return
+	}
+	if ps.count == 0 || other.min < ps.min {
+		ps.min = other.min
+	}
+	if other.max > ps.max {
+		ps.max = other.max
+	}
+	ps.count += other.count
+	ps.total += other.total
+}
@@ -0,0 +1,41 @@
+package statistics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttackStatistician_Trace(t *testing.T) {
+	as := NewAttackStatistician("pay")
+	as.Record(AttackResult{Name: "pay", Duration: 10 * time.Millisecond})
+	assert.Nil(t, as.Report(true).Trace) // 未开启追踪
+
+	as.Record(AttackResult{Name: "pay", Duration: 30 * time.Millisecond, Trace: &Trace{
+		Phases: map[string]time.Duration{"connect": 5 * time.Millisecond, "ttfb": 20 * time.Millisecond},
+	}})
+	as.Record(AttackResult{Name: "pay", Error: errors.New("timeout"), Trace: &Trace{
+		Phases: map[string]time.Duration{"ttfb": 40 * time.Millisecond}, ConnReused: true,
+	}})
+
+	other := NewAttackStatistician("pay")
+	other.Record(AttackResult{Name: "pay", Duration: 10 * time.Millisecond, Trace: &Trace{
+		Phases: map[string]time.Duration{"ttfb": 6 * time.Millisecond}, ConnReused: true,
+	}})
+	assert.Nil(t, as.merge(other))
+
+	dto, err := ConvertAttackStatistician(as)
+	assert.Nil(t, err)
+	converted, err := NewAttackStatisticianFromDTO(dto)
+	assert.Nil(t, err)
+	for _, s := range []*AttackStatistician{as, converted} {
+		trace := s.Report(true).Trace
+		assert.NotNil(t, trace)
+		assert.EqualValues(t, 3, trace.Requests)
+		assert.EqualValues(t, 2, trace.ConnReused)
+		assert.EqualValues(t, PhaseReport{Count: 1, Min: 5 * time.Millisecond, Max: 5 * time.Millisecond, Average: 5 * time.Millisecond, Total: 5 * time.Millisecond}, trace.Phases["connect"])
+		assert.EqualValues(t, PhaseReport{Count: 3, Min: 6 * time.Millisecond, Max: 40 * time.Millisecond, Average: 22 * time.Millisecond, Total: 66 * time.Millisecond}, trace.Phases["ttfb"])
+	}
+}
@@ -0,0 +1,202 @@
+package statistics
+
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultRecentWindow = CurrentTPSTimeRange
+)
+
+type (
+	// WindowReport 最近一段时间窗口内的统计
+	WindowReport struct {
+		Window        time.Duration            `json:"window"`                  // 窗口长度
+		Requests      uint64                   `json:"requests"`                // 窗口内的成功请求数
+		Failures      uint64                   `json:"failures"`                // 窗口内的失败请求数
+		Min           time.Duration            `json:"min"`                     // 窗口内的最小延迟
+		Max           time.Duration            `json:"max"`                     // 窗口内的最大延迟
+		Average       time.Duration            `json:"average"`                 // 窗口内的平均延迟
+		Distributions map[string]time.Duration `json:"distributions,omitempty"` // 窗口内的百分位分布
+		FailureRatio  float64                  `json:"failure_ratio"`           // 窗口内的错误率
+	}
+
+	// windowSlot 一秒内的请求统计
+	windowSlot struct {
+		requests          uint64
+		failures          uint64
+		totalResponseTime time.Duration
+		minResponseTime   time.Duration
+		maxResponseTime   time.Duration
+		responseBucket    map[time.Duration]uint64
+	}
+
+	// slidingWindow 按秒分片的滑动窗口，只保留窗口内的分片
+	slidingWindow struct {
+		size  time.Duration
+		slots map[int64]*windowSlot // key: unix秒
+	}
+)
+
+// WithRecentWindow 设置实时报告中百分位、平均值以及错误率的统计窗口
+func WithRecentWindow(d time.Duration) StatisticianOption {
+	return func(as *AttackStatistician) {
+		if d < time.Second {
+			return
+		}
+		as.window.size = d
+	}
+}
+
+func newSlidingWindow(size time.Duration) *slidingWindow {
+	return &slidingWindow{
+		size:  size,
+		slots: make(map[int64]*windowSlot),
+	}
+}
+
+func newWindowSlot() *windowSlot {
+	return &windowSlot{responseBucket: make(map[time.Duration]uint64)}
+}
+
+func (sw *slidingWindow) seconds() int64 {
+	return int64((sw.size + time.Second - 1) / time.Second)
+}
+
+func (sw *slidingWindow) slotOf(k int64) *windowSlot {
+	slot, ok := sw.slots[k]
+	if !ok {
+		slot = newWindowSlot()
+		sw.slots[k] = slot
+		sw.evict(k)
+	}
+	return slot
+}
+
+// evict 清理窗口之外的分片
+func (sw *slidingWindow) evict(latest int64) {
+	for key := range sw.slots {
+		if latest-key >= sw.seconds() {
+			delete(sw.slots, key)
+		}
+	}
+}
+
+func (sw *slidingWindow) recordSuccess(t time.Time, d time.Duration) {
+	slot := sw.slotOf(t.Unix())
+	slot.requests++
+	slot.totalResponseTime += d
+	if slot.minResponseTime == 0 || d < slot.minResponseTime {
+		slot.minResponseTime = d
+	}
+	if d > slot.maxResponseTime {
+		slot.maxResponseTime = d
+	}
+	slot.responseBucket[findResponseBucket(d)]++
+}
+
+func (sw *slidingWindow) recordFailure(t time.Time) {
+	sw.slotOf(t.Unix()).failures++
+}
+
+func (slot *windowSlot) add(other *windowSlot) {
+	slot.requests += other.requests
+	slot.failures += other.failures
+	slot.totalResponseTime += other.totalResponseTime
+	if other.minResponseTime > 0 && (slot.minResponseTime == 0 || other.minResponseTime < slot.minResponseTime) {
+		slot.minResponseTime = other.minResponseTime
+	}
+	if other.maxResponseTime > slot.maxResponseTime {
+		slot.maxResponseTime = other.maxResponseTime
+	}
+	for k, v := range other.responseBucket {
+		slot.responseBucket[k] += v
+	}
+}
+
+func (sw *slidingWindow) merge(other *slidingWindow) {
+	if other.size > sw.size {
+		sw.size = other.size
+	}
+	var latest int64
+	for k, slot := range other.slots {
+		s, ok := sw.slots[k]
+		if !ok {
+			s = newWindowSlot()
+			sw.slots[k] = s
+		}
+		s.add(slot)
+		if k > latest {
+			latest = k
+		}
+	}
+	sw.evict(latest)
+}
+
+// report 统计截止到now的窗口
+func (sw *slidingWindow) report(now time.Time) *WindowReport {
+	sum := newWindowSlot()
+	end := now.Unix()
+	for k, slot := range sw.slots {
+		if k <= end && end-k < sw.seconds() {
+			sum.add(slot)
+		}
+	}
+
+	report := &WindowReport{
+		Window:        sw.size,
+		Requests:      sum.requests,
+		Failures:      sum.failures,
+		Min:           sum.minResponseTime,
+		Max:           sum.maxResponseTime,
+		Distributions: make(map[string]time.Duration),
+	}
+	if sum.requests > 0 {
+		report.Average = sum.totalResponseTime / time.Duration(sum.requests)
+	}
+	if total := sum.requests + sum.failures; total > 0 {
+		report.FailureRatio = float64(sum.failures) / float64(total)
+	}
+	pers := sum.percentile(timeDistributions...)
+	for index, d := range timeDistributions {
+		report.Distributions[strconv.FormatFloat(d, 'f', 2, 64)] = pers[index]
+	}
+	return report
+}
+
+func (slot *windowSlot) percentile(ps ...float64) []time.Duration {
+	keys := make([]time.Duration, 0, len(slot.responseBucket))
+	for k := range slot.responseBucket {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	results := make([]time.Duration, len(ps))
+	if slot.requests == 0 {
+		return results
+	}
+
+percent:
+	for n, per := range ps {
+		index := int64(float64(slot.requests)*per + .5)
+		if index >= int64(slot.requests) {
+			results[n] = slot.maxResponseTime
+			continue percent
+		}
+		if index <= 1 {
+			results[n] = slot.minResponseTime
+			continue percent
+		}
+		for _, key := range keys {
+			index -= int64(slot.responseBucket[key])
+			if index <= 0 {
+				results[n] = key
+				continue percent
+			}
+		}
+		results[n] = slot.maxResponseTime
+	}
+	return results
+}
@@ -0,0 +1,62 @@
+package statistics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlidingWindow_Report(t *testing.T) {
+	sw := newSlidingWindow(10 * time.Second)
+	begin := time.Unix(1600000000, 0)
+	for i := 0; i < 60; i++ { // 窗口之外的慢请求
+		sw.recordSuccess(begin.Add(time.Duration(i)*time.Second), time.Second)
+	}
+	for i := 60; i < 70; i++ {
+		sw.recordSuccess(begin.Add(time.Duration(i)*time.Second), 10*time.Millisecond)
+		sw.recordFailure(begin.Add(time.Duration(i) * time.Second))
+	}
+	assert.LessOrEqual(t, len(sw.slots), 10)
+
+	report := sw.report(begin.Add(69 * time.Second))
+	assert.EqualValues(t, report.Window, 10*time.Second)
+	assert.EqualValues(t, report.Requests, 10)
+	assert.EqualValues(t, report.Failures, 10)
+	assert.EqualValues(t, report.FailureRatio, .5)
+	assert.EqualValues(t, report.Average, 10*time.Millisecond)
+	assert.EqualValues(t, report.Distributions["0.99"], 10*time.Millisecond)
+
+	report = sw.report(begin.Add(90 * time.Second)) // 窗口内没有请求
+	assert.EqualValues(t, report.Requests, 0)
+	assert.EqualValues(t, report.Distributions["0.99"], 0)
+}
+
+func TestSlidingWindow_Merge(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	sw := newSlidingWindow(5 * time.Second)
+	other := newSlidingWindow(10 * time.Second)
+	sw.recordSuccess(now, 20*time.Millisecond)
+	other.recordSuccess(now, 10*time.Millisecond)
+	other.recordSuccess(now.Add(-8*time.Second), 30*time.Millisecond)
+
+	sw.merge(other)
+	report := sw.report(now)
+	assert.EqualValues(t, report.Window, 10*time.Second)
+	assert.EqualValues(t, report.Requests, 3)
+	assert.EqualValues(t, report.Min, 10*time.Millisecond)
+	assert.EqualValues(t, report.Max, 30*time.Millisecond)
+	assert.EqualValues(t, report.Average, 20*time.Millisecond)
+}
+
+func TestAttackStatistician_Recent(t *testing.T) {
+	as := NewAttackStatistician("foobar", WithRecentWindow(30*time.Second))
+	as.Record(AttackResult{Name: "foobar", Duration: 100 * time.Millisecond})
+	assert.Nil(t, as.Report(true).Recent)
+
+	recent := as.Report(false).Recent
+	assert.NotNil(t, recent)
+	assert.EqualValues(t, recent.Window, 30*time.Second)
+	assert.EqualValues(t, recent.Requests, 1)
+	assert.EqualValues(t, recent.Distributions["0.50"], 100*time.Millisecond)
+}
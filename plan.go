@@ -17,6 +17,7 @@ type (
 		Stages() []Stage
 		Current() (int, Stage)
 		Status() PlanStatus
+		History() []PlanRecord
 		// Start() error
 		// StopCurrentAndStartNext(int, statistics.SummaryReport) (bool, int, Stage, error)
 	}
@@ -24,6 +25,19 @@ type (
 	// PlanStatus 定义测试计划状态
 	PlanStatus int
 
+	// PlanRecord 测试计划执行过程中的人工干预记录
+	PlanRecord struct {
+		Action         PlanAction     `json:"action"`
+		Stage          int            `json:"stage"`
+		Strategy       AttackStrategy `json:"strategy,omitempty"`
+		Timer          Timer          `json:"timer,omitempty"`
+		ExitConditions ExitConditions `json:"exit_conditions,omitempty"`
+		CreatedAt      time.Time      `json:"created_at"`
+	}
+
+	// PlanAction 人工干预的类型
+	PlanAction string
+
 	plan struct {
 		locked       bool
 		current      int
@@ -31,6 +45,10 @@ type (
 		stages       []Stage
 		status       PlanStatus
 		actualStages []*UniversalExitConditions
+		history      []PlanRecord
+		skipping     int // 被要求立即结束的阶段
+		rateLimit    *RateLimit
+		assertions   map[string][]*HTTPAssertion
 		mu           sync.Mutex
 	}
 )
@@ -46,9 +64,19 @@ const (
 	StatusInterrupted
 )
 
+const (
+	// ActionAdjustStage 在线调整当前阶段的压测策略、延时器
+	ActionAdjustStage PlanAction = "adjust-stage"
+	// ActionSkipStage 立即结束当前阶段，进入下一阶段
+	ActionSkipStage PlanAction = "skip-stage"
+	// ActionExtendStage 延长当前阶段的持续时长或请求总数
+	ActionExtendStage PlanAction = "extend-stage"
+)
+
 var (
-	ErrPlanClosed      = errors.New("plan was finished or interrupted")
-	_             Plan = (*plan)(nil)
+	ErrPlanClosed          = errors.New("plan was finished or interrupted")
+	ErrPlanNotRunning      = errors.New("plan is not running")
+	_                 Plan = (*plan)(nil)
 )
 
 func NewPlan(name string) *plan {
@@ -56,10 +84,11 @@ func NewPlan(name string) *plan {
 		name = "unknown"
 	}
 	return &plan{
-		name:    name,
-		current: -1,
-		stages:  make([]Stage, 0),
-		status:  StatusReady,
+		name:     name,
+		current:  -1,
+		stages:   make([]Stage, 0),
+		status:   StatusReady,
+		skipping: -1,
 	}
 }
 
@@ -87,6 +116,38 @@ func (p *plan) AddStages(stages ...Stage) {
 	}
 }
 
+// SetRateLimit 设置请求速率上限，测试计划开始后不能修改
+func (p *plan) SetRateLimit(limit *RateLimit) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.locked {
+		panic(errors.New("plan was locked"))
+	}
+	p.rateLimit = limit
+}
+
+func (p *plan) GetRateLimit() *RateLimit {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.rateLimit
+}
+
+// SetAssertions 按Attacker名称声明断言，测试计划开始后不能修改
+func (p *plan) SetAssertions(assertions map[string][]*HTTPAssertion) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.locked {
+		panic(errors.New("plan was locked"))
+	}
+	p.assertions = assertions
+}
+
+func (p *plan) GetAssertions() map[string][]*HTTPAssertion {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.assertions
+}
+
 func (p *plan) interrupt() {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -103,16 +164,52 @@ func (p *plan) check() error {
 		return fmt.Errorf("cannot start plan in %d status", p.status)
 	}
 
+	if err := p.validateStages(); err != nil {
+		return err
+	}
+	p.locked = true
+	p.actualStages = make([]*UniversalExitConditions, len(p.stages))
+	return nil
+}
+
+// validate 仅检查阶段配置，不改变测试计划的状态
+func (p *plan) validate() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.validateStages()
+}
+
+func (p *plan) validateStages() error {
 	if len(p.stages) == 0 {
 		return errors.New("empty stage")
 	}
+	if err := p.rateLimit.check(); err != nil {
+		return err
+	}
+	if err := checkAssertions(p.assertions); err != nil {
+		return err
+	}
 
+	strategies := make(map[string]string) // 场景名称 -> 压测策略名称，空字符串为默认场景
 	for index, stage := range p.stages {
-		strategy := stage.GetStrategy()
-		switch v := strategy.(type) {
-		case *FixedConcurrentUsers:
-			if v.ConcurrentUsers <= 0 {
-				return errors.New("concurrent users must greater than 0")
+		if err := checkAttackStrategy(stage.GetStrategy()); err != nil {
+			return err
+		}
+		if err := checkStrategyName(strategies, "", stage.GetStrategy()); err != nil {
+			return fmt.Errorf("stage %d: %w", index, err)
+		}
+		if err := defaultTimerConverter.check(stage.GetTimer()); err != nil {
+			return fmt.Errorf("stage %d: %w", index, err)
+		}
+		if err := checkWeights(stageWeights(stage)); err != nil {
+			return fmt.Errorf("stage %d: %w", index, err)
+		}
+		if err := checkScenarios(stageScenarios(stage)); err != nil {
+			return fmt.Errorf("stage %d: %w", index, err)
+		}
+		for _, sc := range stageScenarios(stage) {
+			if err := checkStrategyName(strategies, sc.Name, sc.Strategy); err != nil {
+				return fmt.Errorf("stage %d: scenario %s: %w", index, sc.Name, err)
 			}
 		}
 		// 非最后阶段
@@ -122,8 +219,129 @@ func (p *plan) check() error {
 			}
 		}
 	}
-	p.locked = true
-	p.actualStages = make([]*UniversalExitConditions, len(p.stages))
+	return nil
+}
+
+// checkStrategyName slave为每个场景只创建一次commander，各个阶段中同一场景的压测策略类型必须一致
+func checkStrategyName(strategies map[string]string, scenario string, strategy AttackStrategy) error {
+	name, ok := strategies[scenario]
+	if !ok {
+		strategies[scenario] = strategy.Name()
+		return nil
+	}
+	if name != strategy.Name() {
+		return fmt.Errorf("cannot switch attack strategy from %s to %s", name, strategy.Name())
+	}
+	return nil
+}
+
+func checkAttackStrategy(strategy AttackStrategy) error {
+	if strategy == nil {
+		return errors.New("attack strategy cannot be nil")
+	}
+	if !defaultAttackStrategyConverter.registered(strategy.Name()) {
+		return fmt.Errorf("attack strategy %s: %w", strategy.Name(), ErrNotRegistered)
+	}
+	switch v := strategy.(type) {
+	case *FixedConcurrentUsers:
+		if v.ConcurrentUsers <= 0 {
+			return errors.New("concurrent users must greater than 0")
+		}
+	}
+	return nil
+}
+
+// adjustCurrentStage 替换当前阶段默认场景的压测策略、延时器，为nil时沿用原配置，退出条件以及命名场景保持不变；返回被调整阶段的序号
+func (p *plan) adjustCurrentStage(strategy AttackStrategy, timer Timer) (int, Stage, error) {
+	if strategy == nil && timer == nil {
+		return 0, nil, errors.New("nothing to adjust")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.status != StatusRunning || p.current < 0 {
+		return 0, nil, ErrPlanNotRunning
+	}
+
+	current := p.stages[p.current]
+	if strategy == nil {
+		strategy = current.GetStrategy()
+	} else if strategy.Name() != current.GetStrategy().Name() {
+		return 0, nil, fmt.Errorf("cannot switch attack strategy from %s to %s", current.GetStrategy().Name(), strategy.Name())
+	}
+	if err := checkAttackStrategy(strategy); err != nil {
+		return 0, nil, err
+	}
+	if timer == nil {
+		timer = current.GetTimer()
+	}
+	if err := defaultTimerConverter.check(timer); err != nil {
+		return 0, nil, err
+	}
+
+	adjusted := copyStage(current, strategy, timer, current.GetExitConditions())
+	p.stages[p.current] = adjusted
+	p.history = append(p.history, PlanRecord{
+		Action:    ActionAdjustStage,
+		Stage:     p.current,
+		Strategy:  adjusted.GetStrategy(),
+		Timer:     adjusted.GetTimer(),
+		CreatedAt: time.Now(),
+	})
+	return p.current, adjusted, nil
+}
+
+// skipCurrentStage 标记当前阶段需要立即结束，由下一次巡检推进到下一阶段
+func (p *plan) skipCurrentStage() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.status != StatusRunning || p.current < 0 {
+		return ErrPlanNotRunning
+	}
+	p.skipping = p.current
+	p.history = append(p.history, PlanRecord{
+		Action:    ActionSkipStage,
+		Stage:     p.current,
+		CreatedAt: time.Now(),
+	})
+	return nil
+}
+
+// extendCurrentStage 延长当前阶段的持续时长、请求总数，只能延长已经设置的退出条件
+func (p *plan) extendCurrentStage(duration time.Duration, requests uint64) error {
+	if duration < 0 || (duration == 0 && requests == 0) {
+		return errors.New("nothing to extend")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.status != StatusRunning || p.current < 0 {
+		return ErrPlanNotRunning
+	}
+
+	current := p.stages[p.current]
+	ec, ok := current.GetExitConditions().(*UniversalExitConditions)
+	if !ok {
+		return errors.New("cannot extend the exit conditions of current stage")
+	}
+	if duration > 0 && ec.Duration <= 0 {
+		return errors.New("current stage has no duration limit")
+	}
+	if requests > 0 && ec.Requests <= 0 {
+		return errors.New("current stage has no requests limit")
+	}
+
+	extended := &UniversalExitConditions{Requests: ec.Requests + requests, Duration: ec.Duration + duration}
+	p.stages[p.current] = copyStage(current, current.GetStrategy(), current.GetTimer(), extended)
+	p.history = append(p.history, PlanRecord{
+		Action:         ActionExtendStage,
+		Stage:          p.current,
+		ExitConditions: extended,
+		CreatedAt:      time.Now(),
+	})
 	return nil
 }
 
@@ -183,8 +401,9 @@ func (p *plan) isFinishedCurrentStage(n int, report statistics.SummaryReport) bo
 
 	// todo 暂时不支持其他ExitConditions
 	condition := &UniversalExitConditions{Requests: currentStageRequests, Duration: currentStageDuration}
-	if p.stages[n].GetExitConditions().Check(condition) {
+	if p.skipping == n || p.stages[n].GetExitConditions().Check(condition) {
 		p.actualStages[n] = condition
+		p.skipping = -1
 		return true
 	}
 
@@ -207,6 +426,15 @@ func (p *plan) Stages() []Stage {
 	return ret
 }
 
+func (p *plan) History() []PlanRecord {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ret := make([]PlanRecord, len(p.history))
+	copy(ret, p.history)
+	return ret
+}
+
 func (p *plan) Current() (int, Stage) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -117,3 +117,122 @@ func TestPlan_current(t *testing.T) {
 	assert.EqualValues(t, no, 0)
 	assert.NotNil(t, stage)
 }
+
+func TestPlan_adjustCurrentStage(t *testing.T) {
+	plan := NewPlan("")
+	plan.AddStages(
+		&V1StageConfig{ConcurrentUsers: 100, RampUpPeriod: 3, Duration: 1 * time.Hour},
+	)
+	_, _, err := plan.adjustCurrentStage(&FixedConcurrentUsers{ConcurrentUsers: 200}, nil)
+	assert.ErrorIs(t, err, ErrPlanNotRunning)
+
+	assert.Nil(t, plan.check())
+	plan.stopCurrentAndStartNext(-1, statistics.SummaryReport{})
+
+	_, _, err = plan.adjustCurrentStage(nil, nil)
+	assert.NotNil(t, err)
+	_, _, err = plan.adjustCurrentStage(&FixedConcurrentUsers{ConcurrentUsers: 0}, nil)
+	assert.NotNil(t, err)
+
+	index, stage, err := plan.adjustCurrentStage(&FixedConcurrentUsers{ConcurrentUsers: 200, RampUpPeriod: 5}, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, index)
+	assert.EqualValues(t, stage.GetStrategy(), &FixedConcurrentUsers{ConcurrentUsers: 200, RampUpPeriod: 5})
+	assert.EqualValues(t, stage.GetTimer(), &UniformRandomTimer{})
+	assert.EqualValues(t, stage.GetExitConditions(), &UniversalExitConditions{Duration: 1 * time.Hour})
+
+	_, stage, err = plan.adjustCurrentStage(nil, &UniformRandomTimer{MinWait: 1 * time.Second, MaxWait: 2 * time.Second})
+	assert.Nil(t, err)
+	assert.EqualValues(t, stage.GetStrategy().(*FixedConcurrentUsers).ConcurrentUsers, 200)
+	_, current := plan.Current()
+	assert.EqualValues(t, current, stage)
+
+	history := plan.History()
+	assert.EqualValues(t, len(history), 2)
+	assert.EqualValues(t, history[0].Action, ActionAdjustStage)
+	assert.EqualValues(t, history[1].Timer, &UniformRandomTimer{MinWait: 1 * time.Second, MaxWait: 2 * time.Second})
+}
+
+func TestPlan_skipCurrentStage(t *testing.T) {
+	plan := NewPlan("")
+	plan.AddStages(
+		BuildStage().WithAttackStrategy(&FixedConcurrentUsers{ConcurrentUsers: 100}).
+			WithExitConditions(&UniversalExitConditions{Duration: 1 * time.Hour}),
+		BuildStage().WithAttackStrategy(&FixedConcurrentUsers{ConcurrentUsers: 200}).WithExitConditions(nil),
+	)
+	assert.ErrorIs(t, plan.skipCurrentStage(), ErrPlanNotRunning)
+	assert.Nil(t, plan.check())
+	plan.stopCurrentAndStartNext(-1, statistics.SummaryReport{})
+
+	assert.Nil(t, plan.skipCurrentStage())
+	stopped, i, stage, err := plan.stopCurrentAndStartNext(0, statistics.SummaryReport{
+		LastAttack:    time.Now(),
+		FirstAttack:   time.Now().Add(-1 * time.Minute),
+		TotalRequests: 10000,
+	})
+	assert.Nil(t, err)
+	assert.True(t, stopped)
+	assert.EqualValues(t, i, 1)
+	assert.EqualValues(t, stage, plan.stages[1])
+	assert.EqualValues(t, plan.actualStages[0].Requests, 10000)
+
+	// 最后一个阶段永不结束，直到被跳过
+	stopped, _, _, err = plan.stopCurrentAndStartNext(1, statistics.SummaryReport{
+		LastAttack:    time.Now(),
+		FirstAttack:   time.Now().Add(-2 * time.Hour),
+		TotalRequests: 20000,
+	})
+	assert.False(t, stopped)
+	assert.Nil(t, err)
+
+	assert.Nil(t, plan.skipCurrentStage())
+	stopped, _, _, err = plan.stopCurrentAndStartNext(1, statistics.SummaryReport{
+		LastAttack:    time.Now(),
+		FirstAttack:   time.Now().Add(-2 * time.Hour),
+		TotalRequests: 20000,
+	})
+	assert.True(t, stopped)
+	assert.ErrorIs(t, err, ErrPlanClosed)
+	assert.EqualValues(t, plan.Status(), StatusFinished)
+	assert.EqualValues(t, len(plan.History()), 2)
+}
+
+func TestPlan_extendCurrentStage(t *testing.T) {
+	plan := NewPlan("")
+	plan.AddStages(
+		&V1StageConfig{ConcurrentUsers: 100, Duration: 1 * time.Hour},
+	)
+	assert.ErrorIs(t, plan.extendCurrentStage(1*time.Minute, 0), ErrPlanNotRunning)
+	assert.Nil(t, plan.check())
+	plan.stopCurrentAndStartNext(-1, statistics.SummaryReport{})
+
+	assert.NotNil(t, plan.extendCurrentStage(0, 0))
+	assert.NotNil(t, plan.extendCurrentStage(0, 1000)) // 未设置请求总数
+	assert.Nil(t, plan.extendCurrentStage(30*time.Minute, 0))
+
+	_, stage := plan.Current()
+	assert.EqualValues(t, stage.GetExitConditions(), &UniversalExitConditions{Duration: 90 * time.Minute})
+	assert.EqualValues(t, stage.GetStrategy(), &FixedConcurrentUsers{ConcurrentUsers: 100})
+
+	stopped, _, _, err := plan.stopCurrentAndStartNext(0, statistics.SummaryReport{
+		LastAttack:  time.Now(),
+		FirstAttack: time.Now().Add(-61 * time.Minute),
+	})
+	assert.False(t, stopped)
+	assert.Nil(t, err)
+	assert.EqualValues(t, plan.History()[0].Action, ActionExtendStage)
+
+	// 延长后再调整，权重以及命名场景保持不变
+	weighted := NewPlan("weighted")
+	scenario := &Scenario{Name: "checkout", Strategy: &FixedConcurrentUsers{ConcurrentUsers: 5}, Weights: map[string]uint32{"pay": 1}}
+	weighted.AddStages(BuildStage().WithAttackStrategy(&FixedConcurrentUsers{ConcurrentUsers: 10}).WithExitConditions(&UniversalExitConditions{Requests: 100}).
+		WithWeights(map[string]uint32{"read": 1}).WithScenarios(scenario))
+	assert.Nil(t, weighted.check())
+	weighted.stopCurrentAndStartNext(-1, statistics.SummaryReport{})
+	assert.Nil(t, weighted.extendCurrentStage(0, 100))
+	_, adjusted, err := weighted.adjustCurrentStage(&FixedConcurrentUsers{ConcurrentUsers: 20}, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, &UniversalExitConditions{Requests: 200}, adjusted.GetExitConditions())
+	assert.EqualValues(t, map[string]uint32{"read": 1}, stageWeights(adjusted))
+	assert.EqualValues(t, []*Scenario{scenario}, stageScenarios(adjusted))
+}
@@ -0,0 +1,470 @@
+package ultron
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	"github.com/wosai/ultron/v2/pkg/genproto"
+	"go.uber.org/zap"
+)
+
+type (
+	// QueuedPlan 排队等待执行的测试计划
+	QueuedPlan struct {
+		ID         string                      `json:"id"`
+		Name       string                      `json:"name"`
+		Stages     []*stageDefinition          `json:"stages"`
+		RateLimit  *RateLimit                  `json:"rate_limit,omitempty"`
+		Assertions map[string][]*HTTPAssertion `json:"assertions,omitempty"`
+		CoolDown   time.Duration               `json:"cool_down,omitempty"` // 与上一个测试计划之间的冷却时长
+		Cron       string                      `json:"cron,omitempty"`      // 非空时按cron表达式周期执行
+		NextRun    time.Time                   `json:"next_run,omitempty"`  // cron计划的下一次执行时间
+		EnqueuedAt time.Time                   `json:"enqueued_at"`
+		LastError  string                      `json:"last_error,omitempty"` // 最近一次启动失败的原因，启动成功后清空
+		FailedAt   time.Time                   `json:"failed_at,omitempty"`  // 最近一次启动失败的时间，queueRetryInterval之后重试
+	}
+
+	// EnqueueOption 排队配置项
+	EnqueueOption func(*QueuedPlan)
+
+	// stageDefinition 可序列化的阶段描述，用于持久化以及重复构建测试计划
+	stageDefinition struct {
+		Strategy       *typedConfig             `json:"strategy"`
+		Timer          *typedConfig             `json:"timer"`
+		ExitConditions *UniversalExitConditions `json:"exit_conditions"`
+		Weights        map[string]uint32        `json:"weights,omitempty"`
+		Scenarios      []*scenarioDefinition    `json:"scenarios,omitempty"`
+	}
+
+	// planQueue master侧的测试计划队列
+	planQueue struct {
+		runner    *masterRunner
+		items     []*QueuedPlan
+		storeFile string    // 为空时不持久化
+		idleSince time.Time // master空闲的起始时间，用于计算冷却时长
+		wakeup    chan struct{}
+		mu        sync.Mutex
+	}
+)
+
+var (
+	ErrQueuedPlanNotFound = errors.New("cannot find queued plan with provided id")
+)
+
+// queueRetryInterval 启动失败的测试计划保留在队列中，间隔该时长后重试
+const queueRetryInterval = time.Minute
+
+// WithCoolDown 上一个测试计划结束后，至少等待d才开始执行
+func WithCoolDown(d time.Duration) EnqueueOption {
+	return func(qp *QueuedPlan) {
+		qp.CoolDown = d
+	}
+}
+
+// WithCronSchedule 按标准cron表达式（5个字段）周期性执行
+func WithCronSchedule(expr string) EnqueueOption {
+	return func(qp *QueuedPlan) {
+		qp.Cron = expr
+	}
+}
+
+func newStageDefinition(s Stage) (*stageDefinition, error) {
+	strategy, err := defaultAttackStrategyConverter.convertAttackStrategy(s.GetStrategy())
+	if err != nil {
+		return nil, err
+	}
+	timer := s.GetTimer()
+	if timer == nil {
+		timer = NonstopTimer{}
+	}
+	t, err := defaultTimerConverter.convertTimer(timer)
+	if err != nil {
+		return nil, err
+	}
+	def := &stageDefinition{
+		Strategy:       &typedConfig{Type: strategy.Type, Config: strategy.AttackStrategy},
+		Timer:          &typedConfig{Type: t.Type, Config: t.Timer},
+		ExitConditions: &UniversalExitConditions{},
+		Weights:        stageWeights(s),
+	}
+	for _, sc := range stageScenarios(s) {
+		sd, err := newScenarioDefinition(sc)
+		if err != nil {
+			return nil, err
+		}
+		def.Scenarios = append(def.Scenarios, sd)
+	}
+	switch ec := s.GetExitConditions().(type) {
+	case nil:
+	case *UniversalExitConditions:
+		*def.ExitConditions = *ec
+	default:
+		return nil, errors.New("cannot persist exit conditions except UniversalExitConditions")
+	}
+	return def, nil
+}
+
+func (sd *stageDefinition) build() (Stage, error) {
+	if sd.Strategy == nil || sd.Timer == nil {
+		return nil, errors.New("bad stage definition")
+	}
+	strategy, err := defaultAttackStrategyConverter.convertDTO(&genproto.AttackStrategyDTO{Type: sd.Strategy.Type, AttackStrategy: sd.Strategy.Config})
+	if err != nil {
+		return nil, err
+	}
+	timer, err := defaultTimerConverter.convertDTO(&genproto.TimerDTO{Type: sd.Timer.Type, Timer: sd.Timer.Config})
+	if err != nil {
+		return nil, err
+	}
+	s := BuildStage().WithAttackStrategy(strategy).WithTimer(timer).WithExitConditions(sd.ExitConditions).WithWeights(sd.Weights)
+	for _, def := range sd.Scenarios {
+		sc, err := def.build()
+		if err != nil {
+			return nil, err
+		}
+		s.WithScenarios(sc)
+	}
+	return s, nil
+}
+
+// newQueuedPlan 将测试计划转换为可重复构建的描述
+func newQueuedPlan(p Plan, now time.Time, opts ...EnqueueOption) (*QueuedPlan, error) {
+	if p == nil {
+		return nil, errors.New("empty plan")
+	}
+	qp := &QueuedPlan{
+		ID:         uuid.NewString(),
+		Name:       p.Name(),
+		RateLimit:  planRateLimit(p),
+		Assertions: planAssertions(p),
+		EnqueuedAt: now,
+	}
+	for _, opt := range opts {
+		opt(qp)
+	}
+	if qp.CoolDown < 0 {
+		return nil, errors.New("cool down must not be negative")
+	}
+	if qp.Cron != "" {
+		schedule, err := cron.ParseStandard(qp.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("bad cron expression: %w", err)
+		}
+		qp.NextRun = schedule.Next(now)
+	}
+
+	stages := p.Stages()
+	if len(stages) == 0 {
+		return nil, errors.New("empty stage")
+	}
+	for _, stage := range stages {
+		def, err := newStageDefinition(stage)
+		if err != nil {
+			return nil, err
+		}
+		qp.Stages = append(qp.Stages, def)
+	}
+
+	// 提前构建一次，避免到执行时才发现配置错误
+	if _, err := qp.build(); err != nil {
+		return nil, err
+	}
+	return qp, nil
+}
+
+// build 构建一个全新的、可执行的测试计划
+func (qp *QueuedPlan) build() (*plan, error) {
+	p := NewPlan(qp.Name)
+	p.SetRateLimit(qp.RateLimit)
+	p.SetAssertions(qp.Assertions)
+	for _, def := range qp.Stages {
+		stage, err := def.build()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.addStage(stage); err != nil {
+			return nil, err
+		}
+	}
+	return p, p.validate()
+}
+
+func (qp *QueuedPlan) due(now, idleSince time.Time) bool {
+	if now.Sub(idleSince) < qp.CoolDown {
+		return false
+	}
+	if !qp.FailedAt.IsZero() && now.Sub(qp.FailedAt) < queueRetryInterval {
+		return false
+	}
+	return qp.Cron == "" || !qp.NextRun.After(now)
+}
+
+func newPlanQueue(runner *masterRunner, storeFile string) *planQueue {
+	return &planQueue{
+		runner:    runner,
+		items:     make([]*QueuedPlan, 0),
+		storeFile: storeFile,
+		wakeup:    make(chan struct{}, 1),
+	}
+}
+
+// load 从持久化文件中恢复队列
+func (q *planQueue) load() error {
+	if q.storeFile == "" {
+		return nil
+	}
+	data, err := os.ReadFile(q.storeFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	items := make([]*QueuedPlan, 0)
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, item := range items {
+		if _, err := item.build(); err != nil {
+			return fmt.Errorf("bad queued plan %s: %w", item.ID, err)
+		}
+		if item.Cron != "" && item.NextRun.Before(now) { // 停机期间错过的周期不再补跑
+			schedule, err := cron.ParseStandard(item.Cron)
+			if err != nil {
+				return fmt.Errorf("bad queued plan %s: %w", item.ID, err)
+			}
+			item.NextRun = schedule.Next(now)
+		}
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.items = items
+	return nil
+}
+
+// save 持久化队列，调用方需持有锁
+func (q *planQueue) save() error {
+	if q.storeFile == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(q.items, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(q.storeFile), filepath.Base(q.storeFile)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), q.storeFile)
+}
+
+func (q *planQueue) enqueue(p Plan, opts ...EnqueueOption) (*QueuedPlan, error) {
+	qp, err := newQueuedPlan(p, time.Now(), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mu.Lock()
+	q.items = append(q.items, qp)
+	err = q.save()
+	q.mu.Unlock()
+	if err != nil {
+		Logger.Error("failed to persist plan queue", zap.Error(err))
+	}
+
+	q.notify()
+	ret := *qp
+	return &ret, nil
+}
+
+func (q *planQueue) list() []QueuedPlan {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	ret := make([]QueuedPlan, len(q.items))
+	for i, item := range q.items {
+		ret[i] = *item
+	}
+	return ret
+}
+
+// move 调整排队顺序，position从0开始，超出范围时放到队尾
+func (q *planQueue) move(id string, position int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	index := q.indexOf(id)
+	if index < 0 {
+		return ErrQueuedPlanNotFound
+	}
+	if position < 0 {
+		position = 0
+	}
+	if position >= len(q.items) {
+		position = len(q.items) - 1
+	}
+
+	item := q.items[index]
+	q.items = append(q.items[:index], q.items[index+1:]...)
+	q.items = append(q.items[:position], append([]*QueuedPlan{item}, q.items[position:]...)...)
+	return q.save()
+}
+
+func (q *planQueue) cancel(id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	index := q.indexOf(id)
+	if index < 0 {
+		return ErrQueuedPlanNotFound
+	}
+	q.items = append(q.items[:index], q.items[index+1:]...)
+	return q.save()
+}
+
+func (q *planQueue) indexOf(id string) int {
+	for i, item := range q.items {
+		if item.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (q *planQueue) notify() {
+	select {
+	case q.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// pick 返回下一个可以执行的测试计划，但不会将其移出队列；一次性的计划严格按照顺序执行，
+// 未到期的一次性计划不影响之后的周期性计划
+func (q *planQueue) pick(now time.Time) *QueuedPlan {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.idleSince.IsZero() {
+		q.idleSince = now
+	}
+
+	var blocked bool // 之前的一次性计划尚未到期
+	for _, item := range q.items {
+		if item.Cron == "" && blocked {
+			continue
+		}
+		if !item.due(now, q.idleSince) {
+			if item.Cron == "" {
+				blocked = true
+			}
+			continue
+		}
+		picked := *item
+		return &picked
+	}
+	return nil
+}
+
+// started 测试计划启动成功后，移出一次性的计划，或者计算周期性计划的下一次执行时间；
+// 启动期间已被取消的计划不再处理
+func (q *planQueue) started(id string, now time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.idleSince = time.Time{}
+	index := q.indexOf(id)
+	if index < 0 {
+		return
+	}
+	item := q.items[index]
+	if item.Cron == "" {
+		q.items = append(q.items[:index], q.items[index+1:]...)
+	} else {
+		schedule, _ := cron.ParseStandard(item.Cron)
+		item.NextRun = schedule.Next(now)
+		item.LastError, item.FailedAt = "", time.Time{}
+	}
+	if err := q.save(); err != nil {
+		Logger.Error("failed to persist plan queue", zap.Error(err))
+	}
+}
+
+// failed 启动失败的测试计划保留在原位置，失败原因通过队列接口返回；返回是否与上一次的失败原因不同
+func (q *planQueue) failed(id string, now time.Time, cause error) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	index := q.indexOf(id)
+	if index < 0 {
+		return false
+	}
+	item := q.items[index]
+	changed := item.LastError != cause.Error()
+	item.LastError, item.FailedAt = cause.Error(), now
+	if err := q.save(); err != nil {
+		Logger.Error("failed to persist plan queue", zap.Error(err))
+	}
+	return changed
+}
+
+// dispatch master空闲时，执行下一个到期的测试计划
+func (q *planQueue) dispatch(now time.Time) {
+	if q.runner.isRunning() {
+		q.mu.Lock()
+		q.idleSince = time.Time{}
+		q.mu.Unlock()
+		return
+	}
+
+	item := q.pick(now)
+	if item == nil {
+		return
+	}
+	p, err := item.build()
+	if err == nil {
+		err = q.runner.StartPlan(p)
+	}
+	if err != nil {
+		if q.failed(item.ID, now, err) {
+			Logger.Error("failed to start queued plan, retry later", zap.String("queued_id", item.ID), zap.String("plan_name", item.Name), zap.Error(err))
+		}
+		return
+	}
+
+	q.started(item.ID, now)
+	Logger.Info("started queued plan", zap.String("queued_id", item.ID), zap.String("plan_name", item.Name))
+}
+
+func (q *planQueue) run(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.wakeup:
+		case <-ticker.C:
+		}
+		q.dispatch(time.Now())
+	}
+}
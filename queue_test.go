@@ -0,0 +1,169 @@
+package ultron
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wosai/ultron/v2/pkg/genproto"
+)
+
+func newQueueTestPlan(name string) *plan {
+	p := NewPlan(name)
+	p.SetRateLimit(&RateLimit{GlobalRPS: 200, Attackers: map[string]float64{"write": 10}})
+	p.SetAssertions(map[string][]*HTTPAssertion{"write": {{Type: AssertStatus, Value: []int{200}}}})
+	p.AddStages(
+		&V1StageConfig{Duration: 10 * time.Minute, ConcurrentUsers: 100, RampUpPeriod: 10, MinWait: time.Second, MaxWait: 2 * time.Second},
+		&V1StageConfig{Requests: 1000, ConcurrentUsers: 200, Weights: map[string]uint32{"write": 3}, Scenarios: []*V1ScenarioConfig{{Name: "checkout", ConcurrentUsers: 20}}},
+	)
+	return p
+}
+
+func TestNewQueuedPlan(t *testing.T) {
+	now := time.Date(2021, 11, 2, 12, 0, 0, 0, time.Local)
+	qp, err := newQueuedPlan(newQueueTestPlan("nightly"), now, WithCoolDown(time.Minute), WithCronSchedule("0 2 * * *"))
+	assert.Nil(t, err)
+	assert.EqualValues(t, qp.Name, "nightly")
+	assert.EqualValues(t, len(qp.Stages), 2)
+	assert.EqualValues(t, qp.NextRun, time.Date(2021, 11, 3, 2, 0, 0, 0, time.Local))
+
+	p, err := qp.build()
+	assert.Nil(t, err)
+	stages := p.Stages()
+	assert.EqualValues(t, stages[0].GetStrategy(), &FixedConcurrentUsers{ConcurrentUsers: 100, RampUpPeriod: 10})
+	assert.EqualValues(t, stages[0].GetTimer(), &UniformRandomTimer{MinWait: time.Second, MaxWait: 2 * time.Second})
+	assert.EqualValues(t, stages[1].GetExitConditions(), &UniversalExitConditions{Requests: 1000})
+	assert.Nil(t, stageWeights(stages[0]))
+	assert.EqualValues(t, map[string]uint32{"write": 3}, stageWeights(stages[1]))
+	assert.Nil(t, stageScenarios(stages[0]))
+	assert.EqualValues(t, &RateLimit{GlobalRPS: 200, Attackers: map[string]float64{"write": 10}}, p.GetRateLimit())
+	assert.EqualValues(t, map[string][]*HTTPAssertion{"write": {{Type: AssertStatus, Value: []int{200}}}}, p.GetAssertions())
+	assert.EqualValues(t, []*Scenario{{Name: "checkout", Strategy: &FixedConcurrentUsers{ConcurrentUsers: 20}, Timer: &UniformRandomTimer{}}}, stageScenarios(stages[1]))
+
+	_, err = newQueuedPlan(newQueueTestPlan(""), now, WithCronSchedule("every night"))
+	assert.NotNil(t, err)
+	_, err = newQueuedPlan(NewPlan("empty"), now)
+	assert.NotNil(t, err)
+}
+
+func TestPlanQueue_MoveAndCancel(t *testing.T) {
+	q := newPlanQueue(newMasterRunner(), "")
+	ids := make([]string, 3)
+	for i := range ids {
+		qp, err := q.enqueue(newQueueTestPlan(""))
+		assert.Nil(t, err)
+		ids[i] = qp.ID
+	}
+
+	assert.Nil(t, q.move(ids[2], 0))
+	list := q.list()
+	assert.EqualValues(t, []string{list[0].ID, list[1].ID, list[2].ID}, []string{ids[2], ids[0], ids[1]})
+
+	assert.Nil(t, q.move(ids[2], 100))
+	list = q.list()
+	assert.EqualValues(t, list[2].ID, ids[2])
+
+	assert.Nil(t, q.cancel(ids[0]))
+	assert.ErrorIs(t, q.cancel(ids[0]), ErrQueuedPlanNotFound)
+	assert.ErrorIs(t, q.move(ids[0], 0), ErrQueuedPlanNotFound)
+	assert.EqualValues(t, len(q.list()), 2)
+}
+
+func TestPlanQueue_Persistence(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "queue.json")
+	q := newPlanQueue(newMasterRunner(), file)
+	_, err := q.enqueue(newQueueTestPlan("first"), WithCoolDown(30*time.Second))
+	assert.Nil(t, err)
+	_, err = q.enqueue(newQueueTestPlan("nightly"), WithCronSchedule("@daily"))
+	assert.Nil(t, err)
+
+	restored := newPlanQueue(newMasterRunner(), file)
+	assert.Nil(t, restored.load())
+	expected, _ := json.Marshal(q.list())
+	actual, _ := json.Marshal(restored.list())
+	assert.JSONEq(t, string(expected), string(actual))
+}
+
+func TestPlanQueue_Pick(t *testing.T) {
+	q := newPlanQueue(newMasterRunner(), "")
+	now := time.Now()
+	first, _ := q.enqueue(newQueueTestPlan("first"), WithCoolDown(time.Minute))
+	nightly, _ := q.enqueue(newQueueTestPlan("nightly"), WithCronSchedule("@daily"))
+	second, _ := q.enqueue(newQueueTestPlan("second"))
+
+	assert.Nil(t, q.pick(now)) // 冷却中，之后的一次性计划也需要等待
+
+	picked := q.pick(now.Add(time.Minute))
+	assert.EqualValues(t, picked.ID, first.ID)
+	assert.EqualValues(t, picked.ID, q.pick(now.Add(time.Minute)).ID) // 启动成功之前不会移出队列
+	q.started(first.ID, now.Add(time.Minute))
+	picked = q.pick(now.Add(time.Minute))
+	assert.EqualValues(t, picked.ID, second.ID)
+	q.started(second.ID, now.Add(time.Minute))
+	assert.Nil(t, q.pick(now.Add(time.Minute)))
+
+	picked = q.pick(nightly.NextRun)
+	assert.EqualValues(t, picked.ID, nightly.ID)
+	q.started(nightly.ID, nightly.NextRun)
+	assert.EqualValues(t, len(q.list()), 1) // 周期性计划保留在队列中
+	assert.True(t, q.list()[0].NextRun.After(nightly.NextRun))
+}
+
+func TestPlanQueue_PickCronBehindOneShot(t *testing.T) {
+	q := newPlanQueue(newMasterRunner(), "")
+	now := time.Now()
+	first, _ := q.enqueue(newQueueTestPlan("first"), WithCoolDown(48*time.Hour))
+	nightly, _ := q.enqueue(newQueueTestPlan("nightly"), WithCronSchedule("@daily"))
+	assert.Nil(t, q.pick(now))
+
+	// 冷却中的一次性计划不阻塞之后到期的周期性计划
+	picked := q.pick(nightly.NextRun)
+	assert.EqualValues(t, nightly.ID, picked.ID)
+	picked = q.pick(now.Add(48 * time.Hour))
+	assert.EqualValues(t, first.ID, picked.ID)
+}
+
+func TestPlanQueue_Dispatch(t *testing.T) {
+	runner := newMasterRunner()
+	runner.supervisor = newSlaveSupervisor()
+	sa := newSlaveAgent(&genproto.SubscribeRequest{SlaveId: "abc"})
+	go func() {
+		for range sa.input {
+		}
+	}()
+	runner.supervisor.Add(sa)
+
+	_, err := runner.EnqueuePlan(newQueueTestPlan("first"))
+	assert.Nil(t, err)
+	_, err = runner.EnqueuePlan(newQueueTestPlan("second"))
+	assert.Nil(t, err)
+
+	runner.queue.dispatch(time.Now())
+	assert.True(t, runner.isRunning())
+	assert.EqualValues(t, runner.plan.Name(), "first")
+
+	runner.queue.dispatch(time.Now()) // 上一个计划尚未结束
+	assert.EqualValues(t, len(runner.QueuedPlans()), 1)
+	runner.scheduler.cancel()
+}
+
+func TestPlanQueue_DispatchFailed(t *testing.T) {
+	runner := newMasterRunner()
+	runner.supervisor = newSlaveSupervisor() // 没有slave，无法启动
+	queued, err := runner.EnqueuePlan(newQueueTestPlan("first"))
+	assert.Nil(t, err)
+
+	now := time.Now()
+	runner.queue.dispatch(now)
+	assert.False(t, runner.isRunning())
+	list := runner.QueuedPlans()
+	assert.Len(t, list, 1)
+	assert.EqualValues(t, queued, list[0].ID)
+	assert.NotEmpty(t, list[0].LastError)
+	assert.EqualValues(t, now, list[0].FailedAt)
+
+	assert.Nil(t, runner.queue.pick(now.Add(time.Second))) // 等待重试
+	assert.NotNil(t, runner.queue.pick(now.Add(queueRetryInterval)))
+}
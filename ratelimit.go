@@ -0,0 +1,293 @@
+package ultron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/wosai/ultron/v2/pkg/genproto"
+)
+
+type (
+	// RateLimit 测试计划的请求速率上限，由master按各个slave的负载比例切分，虚拟用户在Fire之前等待令牌
+	RateLimit struct {
+		GlobalRPS float64            `json:"global_rps,omitempty"` // 整个测试计划的上限，为0时不限制
+		Attackers map[string]float64 `json:"attackers,omitempty"`  // 按Attacker名称的上限
+	}
+
+	// RateLimitedPlan Plan的可选实现，为测试计划设置请求速率上限
+	RateLimitedPlan interface {
+		Plan
+		GetRateLimit() *RateLimit
+	}
+
+	// LoadMeasurer AttackStrategy的可选实现，返回切分后的负载（如并发用户数），用于按比例切分请求速率上限；
+	// 存在未实现的压测策略时，各个slave均分
+	LoadMeasurer interface {
+		Load() int
+	}
+
+	// tokenBucket 容量为1的令牌桶，按令牌的理论到达时间预约，最多提前预约一个间隔，并发安全
+	tokenBucket struct {
+		interval time.Duration // 为0时不允许发送请求
+		tat      time.Time     // 下一个令牌的理论到达时间
+		changed  chan struct{} // 速率变化时关闭，唤醒尚未预约到令牌的等待者
+		mu       sync.Mutex
+	}
+
+	// rateLimiters slave当前阶段分到的限流器，创建后不再修改
+	rateLimiters struct {
+		global    *tokenBucket
+		attackers map[string]*tokenBucket
+	}
+)
+
+var (
+	_ LoadMeasurer    = (*FixedConcurrentUsers)(nil)
+	_ RateLimitedPlan = (*plan)(nil)
+)
+
+func (rl *RateLimit) check() error {
+	if rl == nil {
+		return nil
+	}
+	if rl.GlobalRPS < 0 || math.IsInf(rl.GlobalRPS, 0) || math.IsNaN(rl.GlobalRPS) {
+		return errors.New("global rps must be a non-negative number")
+	}
+	for name, rps := range rl.Attackers {
+		if name == "" {
+			return errors.New("attacker name in rate limit cannot be empty")
+		}
+		if rps <= 0 || math.IsInf(rps, 0) || math.IsNaN(rps) {
+			return fmt.Errorf("rps of attacker %s must be a positive number", name)
+		}
+	}
+	return nil
+}
+
+// split 按负载比例切分为n份，负载总和为0时均分
+func (rl *RateLimit) split(loads []int) [][]*genproto.RateLimitDTO {
+	var total int
+	for _, load := range loads {
+		total += load
+	}
+	ret := make([][]*genproto.RateLimitDTO, len(loads))
+	if rl == nil || (rl.GlobalRPS == 0 && len(rl.Attackers) == 0) {
+		return ret
+	}
+	for i, load := range loads {
+		share := 1 / float64(len(loads))
+		if total > 0 {
+			share = float64(load) / float64(total)
+		}
+		if rl.GlobalRPS > 0 {
+			ret[i] = append(ret[i], &genproto.RateLimitDTO{Rps: rl.GlobalRPS * share})
+		}
+		for name, rps := range rl.Attackers {
+			ret[i] = append(ret[i], &genproto.RateLimitDTO{Attacker: name, Rps: rps * share})
+		}
+	}
+	return ret
+}
+
+// strategyLoads 各个slave在默认场景以及命名场景中的负载之和，存在无法衡量负载的压测策略时返回nil
+func strategyLoads(strategies []AttackStrategy, scenarios [][]AttackStrategy) []int {
+	loads := make([]int, len(strategies))
+	measure := func(i int, s AttackStrategy) bool {
+		lm, ok := s.(LoadMeasurer)
+		if ok {
+			loads[i] += lm.Load()
+		}
+		return ok
+	}
+	for i, s := range strategies {
+		if !measure(i, s) {
+			return nil
+		}
+	}
+	for _, parts := range scenarios {
+		for i, s := range parts {
+			if i < len(loads) && !measure(i, s) {
+				return nil
+			}
+		}
+	}
+	return loads
+}
+
+func newTokenBucket(rps float64) *tokenBucket {
+	tb := &tokenBucket{changed: make(chan struct{})}
+	tb.setRate(rps)
+	return tb
+}
+
+// setRate 速率变化时丢弃按旧速率排队的预约，并唤醒等待者按新速率重新预约
+func (tb *tokenBucket) setRate(rps float64) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	var interval time.Duration
+	if rps > 0 {
+		interval = time.Duration(float64(time.Second) / rps)
+		if interval <= 0 {
+			interval = 1
+		}
+	}
+	if interval == tb.interval {
+		return
+	}
+	tb.interval = interval
+	if now := time.Now(); tb.tat.After(now) {
+		tb.tat = now
+	}
+	close(tb.changed)
+	tb.changed = make(chan struct{})
+}
+
+func (tb *tokenBucket) changes() <-chan struct{} {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	return tb.changed
+}
+
+// reserve 预约一个令牌，返回需要等待的时长；预约已排满一个间隔时reserved为false，返回重试之前需要等待的时长；
+// 不允许发送请求时ok为false
+func (tb *tokenBucket) reserve(now time.Time) (wait time.Duration, reserved bool, ok bool) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	if tb.interval == 0 {
+		return 0, false, false
+	}
+	if tb.tat.Before(now) {
+		tb.tat = now
+	}
+	wait = tb.tat.Sub(now)
+	if wait > tb.interval {
+		return wait - tb.interval, false, true
+	}
+	tb.tat = tb.tat.Add(tb.interval)
+	return wait, true, true
+}
+
+// cancel 归还at时刻的预约，之后已有其他预约时不归还
+func (tb *tokenBucket) cancel(at time.Time) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	if tb.interval > 0 && tb.tat.Equal(at.Add(tb.interval)) {
+		tb.tat = at
+	}
+}
+
+// wait 等待令牌，返回等待的时长；context取消时归还尚未使用的预约
+func (tb *tokenBucket) wait(ctx context.Context) (time.Duration, error) {
+	start := time.Now()
+	for waited := false; ; waited = true {
+		changed := tb.changes()
+		now := time.Now()
+		d, reserved, ok := tb.reserve(now)
+		if reserved && d <= 0 {
+			if !waited {
+				return 0, nil
+			}
+			return time.Since(start), nil
+		}
+
+		if reserved {
+			changed = nil // 已预约的令牌不受速率变化影响
+		}
+		if err := sleepOrWake(ctx, d, ok, changed); err != nil {
+			if reserved {
+				tb.cancel(now.Add(d))
+			}
+			return time.Since(start), err
+		}
+		if reserved {
+			return time.Since(start), nil
+		}
+	}
+}
+
+// sleepOrWake 等待d，timed为false时一直等待；速率变化时提前返回
+func sleepOrWake(ctx context.Context, d time.Duration, timed bool, changed <-chan struct{}) error {
+	var timeout <-chan time.Time
+	if timed {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-changed:
+	case <-timeout:
+	}
+	return nil
+}
+
+// newRateLimiters 复用上一阶段同名的令牌桶，避免阶段切换时重新积累令牌
+func newRateLimiters(limits []*genproto.RateLimitDTO, prev *rateLimiters) *rateLimiters {
+	if len(limits) == 0 {
+		return nil
+	}
+	rl := &rateLimiters{attackers: make(map[string]*tokenBucket)}
+	bucket := func(name string, rps float64) *tokenBucket {
+		if prev != nil {
+			tb := prev.global
+			if name != "" {
+				tb = prev.attackers[name]
+			}
+			if tb != nil {
+				tb.setRate(rps)
+				return tb
+			}
+		}
+		return newTokenBucket(rps)
+	}
+	for _, limit := range limits {
+		if limit.GetAttacker() == "" {
+			rl.global = bucket("", limit.GetRps())
+		} else {
+			rl.attackers[limit.GetAttacker()] = bucket(limit.GetAttacker(), limit.GetRps())
+		}
+	}
+	return rl
+}
+
+// wait 先等待Attacker的令牌再等待全局的令牌，避免等待严格的Attacker上限时占用全局令牌，使其他Attacker达不到全局上限
+func (rl *rateLimiters) wait(ctx context.Context, name string) (time.Duration, error) {
+	var throttled time.Duration
+	for _, tb := range []*tokenBucket{rl.attackers[name], rl.global} {
+		if tb == nil {
+			continue
+		}
+		d, err := tb.wait(ctx)
+		throttled += d
+		if err != nil {
+			return throttled, err
+		}
+	}
+	return throttled, nil
+}
+
+// throttle 在Fire之前等待令牌，返回等待的时长
+func throttle(ctx context.Context, name string) (time.Duration, error) {
+	e, ok := executionFrom(ctx)
+	if !ok {
+		return 0, nil
+	}
+	rl, _ := e.limiters.Load().(*rateLimiters)
+	if rl == nil {
+		return 0, nil
+	}
+	return rl.wait(ctx, name)
+}
+
+// planRateLimit 未实现RateLimitedPlan时返回nil
+func planRateLimit(p Plan) *RateLimit {
+	if rp, ok := p.(RateLimitedPlan); ok {
+		return rp.GetRateLimit()
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package ultron
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wosai/ultron/v2/pkg/genproto"
+)
+
+func TestRateLimit_check(t *testing.T) {
+	var rl *RateLimit
+	assert.Nil(t, rl.check())
+	assert.Nil(t, (&RateLimit{GlobalRPS: 100, Attackers: map[string]float64{"pay": 10}}).check())
+	assert.Error(t, (&RateLimit{GlobalRPS: -1}).check())
+	assert.Error(t, (&RateLimit{Attackers: map[string]float64{"pay": 0}}).check())
+	assert.Error(t, (&RateLimit{Attackers: map[string]float64{"": 1}}).check())
+
+	plan := NewPlan("rate-limit")
+	plan.AddStages(&V1StageConfig{ConcurrentUsers: 10})
+	plan.SetRateLimit(&RateLimit{GlobalRPS: -1})
+	assert.Error(t, plan.validate())
+	plan.SetRateLimit(&RateLimit{GlobalRPS: 10})
+	assert.Nil(t, plan.check())
+	assert.EqualValues(t, &RateLimit{GlobalRPS: 10}, planRateLimit(plan))
+	assert.Panics(t, func() { plan.SetRateLimit(nil) })
+}
+
+func TestRateLimit_split(t *testing.T) {
+	var rl *RateLimit
+	assert.EqualValues(t, [][]*genproto.RateLimitDTO{nil, nil}, rl.split([]int{1, 1}))
+
+	rl = &RateLimit{GlobalRPS: 100, Attackers: map[string]float64{"pay": 10}}
+	limits := rl.split([]int{3, 1, 0})
+	assert.Len(t, limits, 3)
+	assert.EqualValues(t, []*genproto.RateLimitDTO{{Rps: 75}, {Attacker: "pay", Rps: 7.5}}, limits[0])
+	assert.EqualValues(t, []*genproto.RateLimitDTO{{Rps: 25}, {Attacker: "pay", Rps: 2.5}}, limits[1])
+	assert.EqualValues(t, []*genproto.RateLimitDTO{{Rps: 0}, {Attacker: "pay", Rps: 0}}, limits[2])
+
+	limits = (&RateLimit{GlobalRPS: 90}).split([]int{0, 0, 0}) // 均分
+	for _, limit := range limits {
+		assert.EqualValues(t, []*genproto.RateLimitDTO{{Rps: 30}}, limit)
+	}
+}
+
+func TestStrategyLoads(t *testing.T) {
+	strategies := (&FixedConcurrentUsers{ConcurrentUsers: 10}).Split(3)
+	scenarios := [][]AttackStrategy{(&FixedConcurrentUsers{ConcurrentUsers: 2}).Split(3)}
+	assert.EqualValues(t, []int{5, 4, 3}, strategyLoads(strategies, scenarios))
+
+	assert.Nil(t, strategyLoads([]AttackStrategy{&unmeasurableStrategy{}}, nil))
+}
+
+// unmeasurableStrategy 覆盖Load的签名，不再实现LoadMeasurer
+type unmeasurableStrategy struct {
+	FixedConcurrentUsers
+}
+
+func (*unmeasurableStrategy) Load() {}
+
+func TestTokenBucket_reserve(t *testing.T) {
+	tb := newTokenBucket(10)
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		wait, reserved, ok := tb.reserve(now)
+		assert.True(t, ok)
+		assert.True(t, reserved)
+		assert.EqualValues(t, time.Duration(i)*100*time.Millisecond, wait)
+	}
+
+	// 最多提前预约一个间隔
+	wait, reserved, ok := tb.reserve(now)
+	assert.True(t, ok)
+	assert.False(t, reserved)
+	assert.EqualValues(t, 100*time.Millisecond, wait)
+
+	// 归还最后一个预约
+	tb.cancel(now.Add(100 * time.Millisecond))
+	wait, reserved, _ = tb.reserve(now)
+	assert.True(t, reserved)
+	assert.EqualValues(t, 100*time.Millisecond, wait)
+
+	// 空闲后不积累令牌
+	wait, reserved, _ = tb.reserve(now.Add(time.Second))
+	assert.True(t, reserved)
+	assert.Zero(t, wait)
+	wait, _, _ = tb.reserve(now.Add(time.Second))
+	assert.EqualValues(t, 100*time.Millisecond, wait)
+
+	tb.setRate(0)
+	_, _, ok = tb.reserve(now)
+	assert.False(t, ok)
+}
+
+func TestTokenBucket_wait(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	d, err := newTokenBucket(0).wait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.GreaterOrEqual(t, d, 50*time.Millisecond)
+
+	tb := newTokenBucket(1)
+	d, err = tb.wait(context.Background())
+	assert.Nil(t, err)
+	assert.Zero(t, d)
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = tb.wait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestTokenBucket_raiseRate(t *testing.T) {
+	prev := newRateLimiters([]*genproto.RateLimitDTO{{Rps: 1}}, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var done int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := prev.wait(ctx, "pay"); err == nil {
+				atomic.AddInt32(&done, 1)
+			}
+		}()
+	}
+	<-time.After(50 * time.Millisecond)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&done)) // 每秒1个令牌
+
+	// 下一阶段提高速率，排队中的用户按新速率获取令牌，而不是等待旧速率的预约耗尽
+	next := newRateLimiters([]*genproto.RateLimitDTO{{Rps: 1000}}, prev)
+	assert.Same(t, prev.global, next.global)
+	<-time.After(150 * time.Millisecond)
+	assert.GreaterOrEqual(t, atomic.LoadInt32(&done), int32(19)) // 仅按旧速率预约的一个用户仍在等待
+
+	cancel()
+	wg.Wait()
+}
+
+func TestRateLimiters_strictAttacker(t *testing.T) {
+	rl := newRateLimiters([]*genproto.RateLimitDTO{{Rps: 100}, {Attacker: "slow", Rps: 1}}, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var fast int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for {
+				if _, err := rl.wait(ctx, "slow"); err != nil {
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for {
+				if _, err := rl.wait(ctx, "fast"); err != nil {
+					return
+				}
+				atomic.AddInt32(&fast, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.GreaterOrEqual(t, atomic.LoadInt32(&fast), int32(90)) // 等待slow令牌的用户不占用全局令牌
+}
+
+func TestThrottle(t *testing.T) {
+	d, err := throttle(context.Background(), "pay")
+	assert.Nil(t, err)
+	assert.Zero(t, d)
+
+	exec := newExecution("plan", "slave")
+	ctx := withExecution(context.Background(), exec)
+	d, err = throttle(ctx, "pay")
+	assert.Nil(t, err)
+	assert.Zero(t, d)
+
+	exec.setRateLimits([]*genproto.RateLimitDTO{{Rps: 1000}, {Attacker: "pay", Rps: 20}})
+	limiters := exec.limiters.Load().(*rateLimiters)
+	start := time.Now()
+	var throttled time.Duration
+	for i := 0; i < 5; i++ {
+		d, err := throttle(ctx, "pay")
+		assert.Nil(t, err)
+		throttled += d
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 190*time.Millisecond)
+	assert.GreaterOrEqual(t, throttled, 190*time.Millisecond)
+
+	d, err = throttle(ctx, "read") // 仅受全局上限约束
+	assert.Nil(t, err)
+	assert.Less(t, d, 10*time.Millisecond)
+
+	// 下一阶段复用同名的令牌桶
+	exec.setRateLimits([]*genproto.RateLimitDTO{{Attacker: "pay", Rps: 10}})
+	next := exec.limiters.Load().(*rateLimiters)
+	assert.Same(t, limiters.attackers["pay"], next.attackers["pay"])
+	assert.Nil(t, next.global)
+	assert.EqualValues(t, 100*time.Millisecond, next.attackers["pay"].interval)
+
+	exec.setRateLimits(nil)
+	d, err = throttle(ctx, "pay")
+	assert.Nil(t, err)
+	assert.Zero(t, d)
+}
@@ -0,0 +1,330 @@
+package ultron
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// ReplayRecord 一条录制的请求
+	ReplayRecord struct {
+		Method    string            `json:"method"`
+		URL       string            `json:"url"`
+		Headers   map[string]string `json:"headers,omitempty"`
+		Body      string            `json:"body,omitempty"`
+		Timestamp time.Time         `json:"timestamp,omitempty"` // 录制时间，按原始节奏回放时使用
+	}
+
+	// ReplayMode 回放方式
+	ReplayMode int
+
+	// ReplayAttacker 按顺序回放录制的请求，请求由内置的HTTPAttacker发送
+	ReplayAttacker struct {
+		name    string
+		http    *HTTPAttacker
+		all     []ReplayRecord
+		offsets []time.Duration // 相对于第一条请求的时间偏移
+		span    time.Duration   // 一轮回放的时长，为最后一条请求的偏移加上平均间隔
+		mode    ReplayMode
+		speed   float64
+		groups  []replayGroup
+
+		mu      sync.Mutex
+		exec    *execution // 当前切分对应的执行信息，测试计划变化时重新切分
+		records []int      // 当前slave需要回放的请求下标
+		cursor  uint64
+		start   time.Time
+	}
+
+	// ReplayOption ReplayAttacker配置项
+	ReplayOption func(*ReplayAttacker)
+
+	replayGroup struct {
+		name    string
+		pattern *regexp.Regexp
+	}
+
+	harFile struct {
+		Log struct {
+			Entries []struct {
+				StartedDateTime time.Time `json:"startedDateTime"`
+				Request         struct {
+					Method  string `json:"method"`
+					URL     string `json:"url"`
+					Headers []struct {
+						Name  string `json:"name"`
+						Value string `json:"value"`
+					} `json:"headers"`
+					PostData *struct {
+						Text string `json:"text"`
+					} `json:"postData"`
+				} `json:"request"`
+			} `json:"entries"`
+		} `json:"log"`
+	}
+)
+
+const (
+	ReplayLoop           ReplayMode = iota // 不等待，按压测策略允许的最快速度循环回放
+	ReplayOriginalTiming                   // 保持请求之间原始的相对时间，循环回放
+)
+
+const (
+	keyReplayRecord = "replay_record"
+)
+
+var (
+	_ Attacker        = (*ReplayAttacker)(nil)
+	_ VirtualUserHook = (*ReplayAttacker)(nil)
+)
+
+// NewReplayAttacker 创建回放Attacker，默认按slave切分请求
+func NewReplayAttacker(name string, records []ReplayRecord, opts ...ReplayOption) *ReplayAttacker {
+	if len(records) == 0 {
+		panic("no records to replay")
+	}
+	ra := &ReplayAttacker{
+		name:    name,
+		all:     records,
+		offsets: make([]time.Duration, len(records)),
+		mode:    ReplayLoop,
+		speed:   1,
+	}
+	ra.http = NewHTTPAttacker(name, WithPrepareFunc(ra.prepare))
+
+	first := records[0].Timestamp
+	for i, r := range records {
+		if !first.IsZero() && !r.Timestamp.IsZero() && r.Timestamp.After(first) {
+			ra.offsets[i] = r.Timestamp.Sub(first)
+		}
+		if ra.offsets[i] > ra.span {
+			ra.span = ra.offsets[i]
+		}
+	}
+	if len(records) > 1 {
+		ra.span += ra.span / time.Duration(len(records)-1)
+	}
+
+	for _, opt := range opts {
+		opt(ra)
+	}
+	ra.reset(nil)
+	return ra
+}
+
+// WithReplayMode 回放方式
+func WithReplayMode(mode ReplayMode) ReplayOption {
+	return func(ra *ReplayAttacker) {
+		ra.mode = mode
+	}
+}
+
+// WithReplaySpeed 按原始节奏的倍速回放，如2表示两倍速
+func WithReplaySpeed(speed float64) ReplayOption {
+	return func(ra *ReplayAttacker) {
+		if speed <= 0 {
+			panic("replay speed must be positive")
+		}
+		ra.mode = ReplayOriginalTiming
+		ra.speed = speed
+	}
+}
+
+// WithReplayGroup URL匹配pattern的请求以name统计，按添加顺序匹配，都不匹配时以ReplayAttacker的名称统计
+func WithReplayGroup(name, pattern string) ReplayOption {
+	return func(ra *ReplayAttacker) {
+		if name == "" {
+			panic("replay group name cannot be empty")
+		}
+		ra.groups = append(ra.groups, replayGroup{name: name, pattern: regexp.MustCompile(pattern)})
+	}
+}
+
+// WithReplayHTTPOptions 配置内置的HTTPAttacker，如client、校验函数等
+func WithReplayHTTPOptions(opts ...HTTPAttackerOption) ReplayOption {
+	return func(ra *ReplayAttacker) {
+		ra.http.Apply(opts...)
+	}
+}
+
+// LoadReplayJSONLines 读取JSON Lines格式的录制请求，每行为一个ReplayRecord
+func LoadReplayJSONLines(path string) ([]ReplayRecord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []ReplayRecord
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var record ReplayRecord
+		if err := json.Unmarshal([]byte(text), &record); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if record.Method == "" || record.URL == "" {
+			return nil, fmt.Errorf("line %d: method and url are required", line)
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// LoadReplayHAR 读取HAR文件中的请求
+func LoadReplayHAR(path string) ([]ReplayRecord, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var har harFile
+	if err := json.Unmarshal(data, &har); err != nil {
+		return nil, err
+	}
+	records := make([]ReplayRecord, 0, len(har.Log.Entries))
+	for _, entry := range har.Log.Entries {
+		record := ReplayRecord{
+			Method:    entry.Request.Method,
+			URL:       entry.Request.URL,
+			Timestamp: entry.StartedDateTime,
+		}
+		for _, h := range entry.Request.Headers {
+			if strings.HasPrefix(h.Name, ":") { // HTTP/2伪头部
+				continue
+			}
+			if record.Headers == nil {
+				record.Headers = make(map[string]string)
+			}
+			record.Headers[h.Name] = h.Value
+		}
+		if entry.Request.PostData != nil {
+			record.Body = entry.Request.PostData.Text
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func (ra *ReplayAttacker) Name() string {
+	return ra.name
+}
+
+func (ra *ReplayAttacker) Fire(ctx context.Context) error {
+	index, due := ra.next(ctx)
+	if wait := time.Until(due); ra.mode == ReplayOriginalTiming && wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	record := &ra.all[index]
+	ctx = AllocateStorageInContext(ctx)
+	pinStorageInContext(ctx)
+	defer func() {
+		unpinStorageInContext(ctx)
+		ClearStorageInContext(ctx)
+	}()
+	StoreInContext(ctx, keyReplayRecord, record)
+
+	err := ra.http.Fire(ctx)
+	renameResult(ctx, ra.group(record.URL))
+	return err
+}
+
+// OnStart 转发给内置的HTTPAttacker
+func (ra *ReplayAttacker) OnStart(ctx context.Context) error {
+	return ra.http.OnStart(ctx)
+}
+
+// OnStop 转发给内置的HTTPAttacker
+func (ra *ReplayAttacker) OnStop(ctx context.Context) {
+	ra.http.OnStop(ctx)
+}
+
+// next 取出下一条请求，返回其下标以及按原始节奏的发送时间
+func (ra *ReplayAttacker) next(ctx context.Context) (int, time.Time) {
+	ra.mu.Lock()
+	defer ra.mu.Unlock()
+
+	if exec, ok := executionFrom(ctx); ok && exec != ra.exec {
+		ra.reset(exec)
+	}
+	if ra.start.IsZero() {
+		ra.start = time.Now()
+	}
+
+	n := ra.cursor
+	ra.cursor++
+	index := ra.records[n%uint64(len(ra.records))]
+	round := time.Duration(n / uint64(len(ra.records)))
+	due := ra.start.Add(time.Duration(float64(round*ra.span+ra.offsets[index]) / ra.speed))
+	return index, due
+}
+
+// reset 按slave切分请求，调用方需持有锁
+func (ra *ReplayAttacker) reset(exec *execution) {
+	ra.exec = exec
+	ra.cursor = 0
+	ra.start = time.Time{}
+	ra.records = ra.records[:0]
+
+	var index, count uint32 = 0, 1
+	if exec != nil {
+		if s := exec.slice(); s[1] > 0 {
+			index, count = s[0], s[1]
+		}
+	}
+	for i := int(index); i < len(ra.all); i += int(count) {
+		ra.records = append(ra.records, i)
+	}
+	if len(ra.records) == 0 { // slave数量多于请求数量时，重复回放第一条
+		ra.records = append(ra.records, 0)
+	}
+}
+
+func (ra *ReplayAttacker) group(url string) string {
+	for _, g := range ra.groups {
+		if g.pattern.MatchString(url) {
+			return g.name
+		}
+	}
+	return ra.name
+}
+
+func (ra *ReplayAttacker) prepare(ctx context.Context) (*http.Request, error) {
+	v, _ := FromContext(ctx, keyReplayRecord)
+	record, ok := v.(*ReplayRecord)
+	if !ok {
+		return nil, errors.New("no replay record in context")
+	}
+	var body io.Reader
+	if record.Body != "" {
+		body = strings.NewReader(record.Body)
+	}
+	req, err := http.NewRequest(record.Method, record.URL, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range record.Headers {
+		req.Header.Set(k, v)
+	}
+	return req, nil
+}
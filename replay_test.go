@@ -0,0 +1,118 @@
+package ultron
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wosai/ultron/v2/pkg/genproto"
+)
+
+func TestLoadReplayRecords(t *testing.T) {
+	dir := t.TempDir()
+	jsonl := filepath.Join(dir, "requests.jsonl")
+	assert.Nil(t, os.WriteFile(jsonl, []byte(`{"method":"POST","url":"http://example.com/a","headers":{"X-Id":"1"},"body":"hello","timestamp":"2022-01-01T00:00:00Z"}
+
+{"method":"GET","url":"http://example.com/b"}
+`), 0644))
+	records, err := LoadReplayJSONLines(jsonl)
+	assert.Nil(t, err)
+	assert.Len(t, records, 2)
+	assert.EqualValues(t, "hello", records[0].Body)
+	assert.EqualValues(t, "1", records[0].Headers["X-Id"])
+
+	assert.Nil(t, os.WriteFile(jsonl, []byte(`{"url":"http://example.com/a"}`), 0644))
+	_, err = LoadReplayJSONLines(jsonl)
+	assert.ErrorContains(t, err, "line 1")
+
+	har := filepath.Join(dir, "requests.har")
+	assert.Nil(t, os.WriteFile(har, []byte(`{"log":{"entries":[
+{"startedDateTime":"2022-01-01T00:00:00.000Z","request":{"method":"GET","url":"http://example.com/a","headers":[{"name":":authority","value":"example.com"},{"name":"Accept","value":"*/*"}]}},
+{"startedDateTime":"2022-01-01T00:00:01.500Z","request":{"method":"POST","url":"http://example.com/b","headers":[],"postData":{"text":"{}"}}}
+]}}`), 0644))
+	records, err = LoadReplayHAR(har)
+	assert.Nil(t, err)
+	assert.Len(t, records, 2)
+	assert.EqualValues(t, map[string]string{"Accept": "*/*"}, records[0].Headers)
+	assert.EqualValues(t, "{}", records[1].Body)
+
+	ra := NewReplayAttacker("replay", records)
+	assert.EqualValues(t, []time.Duration{0, 1500 * time.Millisecond}, ra.offsets)
+	assert.EqualValues(t, 3*time.Second, ra.span)
+}
+
+func TestReplayAttacker_Fire(t *testing.T) {
+	var mu sync.Mutex
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, r.Method+" "+r.URL.Path+" "+r.Header.Get("X-Id")+" "+string(body))
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	records := []ReplayRecord{
+		{Method: "POST", URL: server.URL + "/users/1", Headers: map[string]string{"X-Id": "a"}, Body: "x"},
+		{Method: "GET", URL: server.URL + "/orders/2"},
+		{Method: "GET", URL: server.URL + "/health"},
+	}
+	ra := NewReplayAttacker("replay", records,
+		WithReplayGroup("users", `/users/\d+`),
+		WithReplayGroup("orders", `/orders/\d+`),
+	)
+
+	ctx := newExecutorSharedContext(context.Background())
+	var names []string
+	for i := 0; i < 4; i++ {
+		assert.Nil(t, ra.Fire(ctx))
+		names = append(names, takeResultName(ctx, ra.Name()))
+	}
+	assert.EqualValues(t, []string{"users", "orders", "replay", "users"}, names)
+	assert.EqualValues(t, []string{"POST /users/1 a x", "GET /orders/2  ", "GET /health  ", "POST /users/1 a x"}, received)
+}
+
+func TestReplayAttacker_Partition(t *testing.T) {
+	records := make([]ReplayRecord, 5)
+	ra := NewReplayAttacker("replay", records)
+	assert.EqualValues(t, []int{0, 1, 2, 3, 4}, ra.records)
+
+	exec := newExecution("plan", "slave")
+	exec.update(&genproto.ExecutionDTO{SlaveIndex: 1, SlaveCount: 2})
+	index, _ := ra.next(withExecution(context.Background(), exec))
+	assert.EqualValues(t, 1, index)
+	index, _ = ra.next(withExecution(context.Background(), exec))
+	assert.EqualValues(t, 3, index)
+	index, _ = ra.next(withExecution(context.Background(), exec))
+	assert.EqualValues(t, 1, index)
+}
+
+func TestReplayAttacker_Timing(t *testing.T) {
+	start := time.Now()
+	records := []ReplayRecord{
+		{Method: "GET", URL: "http://example.com/a", Timestamp: start},
+		{Method: "GET", URL: "http://example.com/b", Timestamp: start.Add(2 * time.Second)},
+	}
+
+	ra := NewReplayAttacker("replay", records, WithReplaySpeed(4))
+	_, due0 := ra.next(context.Background())
+	_, due1 := ra.next(context.Background())
+	_, due2 := ra.next(context.Background())
+	assert.EqualValues(t, 500*time.Millisecond, due1.Sub(due0))
+	assert.EqualValues(t, time.Second, due2.Sub(due0)) // 下一轮
+	assert.EqualValues(t, ReplayOriginalTiming, ra.mode)
+
+	// 等待期间被取消
+	ra = NewReplayAttacker("replay", records, WithReplayMode(ReplayOriginalTiming))
+	ra.next(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, ra.Fire(ctx), context.DeadlineExceeded)
+}
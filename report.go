@@ -0,0 +1,339 @@
+package ultron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/wosai/ultron/v2/pkg/statistics"
+	"go.uber.org/zap"
+)
+
+type (
+	// HTMLReporter 收集测试计划执行过程中的周期报告，生成可离线查看的HTML报告
+	HTMLReporter struct {
+		plan    string
+		samples []reportSample
+		last    *statistics.SummaryReport
+		final   *statistics.SummaryReport
+		mu      sync.Mutex
+	}
+
+	// reportSample 周期报告的采样点
+	reportSample struct {
+		at              time.Time
+		stage           string
+		concurrentUsers float64
+		failures        uint64
+		tps             float64
+		attackerTPS     map[string]float64
+	}
+
+	chartSeries struct {
+		name   string
+		values []float64
+	}
+
+	failureDetail struct {
+		Attacker string
+		Error    string
+		Count    uint64
+		Samples  []string
+	}
+
+	htmlReportView struct {
+		Plan        string
+		GeneratedAt string
+		Summary     *statistics.SummaryReport
+		Attackers   []statistics.AttackReport
+		Failures    []failureDetail
+		Charts      []template.HTML
+	}
+)
+
+var (
+	ErrEmptyReport = errors.New("no report has been collected yet")
+
+	chartColors = []string{"#2f7ed8", "#0d233a", "#8bbc21", "#910000", "#1aadce", "#492970", "#f28f43", "#77a1e5", "#c42525", "#a6c96a"}
+
+	htmlReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+		"percentile": func(r statistics.AttackReport, key string) string { return r.Distributions[key].String() },
+		"tps":        func(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) },
+		"ratio":      func(v float64) string { return strconv.FormatFloat(v*100, 'f', 2, 64) + "%" },
+		"datetime":   func(t time.Time) string { return t.Format("2006-01-02 15:04:05") },
+	}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Ultron Report - {{.Plan}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 24px; color: #333; }
+h1 { font-size: 24px; } h2 { font-size: 18px; margin-top: 32px; border-bottom: 1px solid #ddd; padding-bottom: 4px; }
+table { border-collapse: collapse; width: 100%; font-size: 13px; }
+th, td { border: 1px solid #ddd; padding: 6px 8px; text-align: center; }
+th { background: #f5f5f5; }
+td.text { text-align: left; word-break: break-all; }
+.meta span { margin-right: 24px; }
+svg { margin-bottom: 16px; }
+</style>
+</head>
+<body>
+<h1>{{.Plan}}</h1>
+<p class="meta"><span>Generated At: {{.GeneratedAt}}</span>{{with .Summary}}<span>First Attack: {{datetime .FirstAttack}}</span><span>Last Attack: {{datetime .LastAttack}}</span><span>Full History: {{.FullHistory}}</span>{{end}}</p>
+{{with .Summary}}
+<h2>Summary</h2>
+<table>
+<tr><th>Requests</th><th>Failures</th><th>TPS</th></tr>
+<tr><td>{{.TotalRequests}}</td><td>{{.TotalFailures}}</td><td>{{tps .TotalTPS}}</td></tr>
+</table>
+{{end}}
+<h2>Attackers</h2>
+<table>
+<tr><th>Attacker</th><th>Min</th><th>P50</th><th>P60</th><th>P70</th><th>P80</th><th>P90</th><th>P95</th><th>P97</th><th>P98</th><th>P99</th><th>Max</th><th>Avg</th><th>Requests</th><th>Failures</th><th>Failure Ratio</th><th>TPS</th></tr>
+{{range .Attackers}}<tr><td class="text">{{.Name}}</td><td>{{.Min}}</td><td>{{percentile . "0.50"}}</td><td>{{percentile . "0.60"}}</td><td>{{percentile . "0.70"}}</td><td>{{percentile . "0.80"}}</td><td>{{percentile . "0.90"}}</td><td>{{percentile . "0.95"}}</td><td>{{percentile . "0.97"}}</td><td>{{percentile . "0.98"}}</td><td>{{percentile . "0.99"}}</td><td>{{.Max}}</td><td>{{.Average}}</td><td>{{.Requests}}</td><td>{{.Failures}}</td><td>{{ratio .FailureRatio}}</td><td>{{tps .TPS}}</td></tr>
+{{end}}</table>
+<h2>Timeline</h2>
+{{range .Charts}}{{.}}
+{{else}}<p>no periodic report was collected</p>
+{{end}}
+<h2>Failures</h2>
+{{if .Failures}}<table>
+<tr><th>Attacker</th><th>Error</th><th>Count</th><th>Samples</th></tr>
+{{range .Failures}}<tr><td class="text">{{.Attacker}}</td><td class="text">{{.Error}}</td><td>{{.Count}}</td><td class="text">{{range .Samples}}<div>{{.}}</div>{{end}}</td></tr>
+{{end}}</table>
+{{else}}<p>no failure</p>
+{{end}}
+</body>
+</html>
+`))
+)
+
+// NewHTMLReporter 创建HTMLReporter，需要通过HandleReport订阅聚合报告
+func NewHTMLReporter() *HTMLReporter {
+	return &HTMLReporter{samples: make([]reportSample, 0)}
+}
+
+// HandleReport 订阅聚合报告的处理函数
+func (hr *HTMLReporter) HandleReport() ReportHandleFunc {
+	return func(_ context.Context, report statistics.SummaryReport) {
+		hr.collect(report, time.Now())
+	}
+}
+
+func (hr *HTMLReporter) collect(report statistics.SummaryReport, at time.Time) {
+	hr.mu.Lock()
+	defer hr.mu.Unlock()
+
+	plan := report.Extras[KeyPlan]
+	if plan != hr.plan || (hr.final != nil && !report.FullHistory) { // 新的测试计划
+		hr.plan = plan
+		hr.samples = make([]reportSample, 0)
+		hr.last = nil
+		hr.final = nil
+	}
+
+	if report.FullHistory {
+		hr.final = &report
+		return
+	}
+	hr.last = &report
+
+	sample := reportSample{
+		at:          at,
+		stage:       report.Extras[KeyStage],
+		tps:         report.TotalTPS,
+		failures:    report.TotalFailures,
+		attackerTPS: make(map[string]float64),
+	}
+	sample.concurrentUsers = float64(report.ConcurrentUsers)
+	for name, r := range report.Reports {
+		sample.attackerTPS[name] = r.TPS
+	}
+	hr.samples = append(hr.samples, sample)
+}
+
+// Render 输出HTML报告，测试计划尚未结束时使用最近一次的周期报告
+func (hr *HTMLReporter) Render(w io.Writer) error {
+	hr.mu.Lock()
+	view := htmlReportView{
+		Plan:        hr.plan,
+		GeneratedAt: time.Now().Format("2006-01-02 15:04:05"),
+		Summary:     hr.final,
+	}
+	if view.Summary == nil {
+		view.Summary = hr.last
+	}
+	samples := make([]reportSample, len(hr.samples))
+	copy(samples, hr.samples)
+	hr.mu.Unlock()
+
+	if view.Summary == nil {
+		return ErrEmptyReport
+	}
+	if view.Plan == "" {
+		view.Plan = "unknown"
+	}
+
+	for _, r := range view.Summary.Reports {
+		view.Attackers = append(view.Attackers, r)
+		for reason, count := range r.FailureDetails {
+			view.Failures = append(view.Failures, failureDetail{Attacker: r.Name, Error: reason, Count: count, Samples: r.FailureSamples[reason]})
+		}
+	}
+	sort.Slice(view.Attackers, func(i, j int) bool { return view.Attackers[i].Name < view.Attackers[j].Name })
+	sort.Slice(view.Failures, func(i, j int) bool {
+		if view.Failures[i].Count == view.Failures[j].Count {
+			return view.Failures[i].Attacker+view.Failures[i].Error < view.Failures[j].Attacker+view.Failures[j].Error
+		}
+		return view.Failures[i].Count > view.Failures[j].Count
+	})
+	view.Charts = renderTimelineCharts(samples, view.Attackers)
+
+	return htmlReportTemplate.Execute(w, view)
+}
+
+func renderTimelineCharts(samples []reportSample, attackers []statistics.AttackReport) []template.HTML {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	times := make([]time.Time, len(samples))
+	total := chartSeries{name: "Total", values: make([]float64, len(samples))}
+	errs := chartSeries{name: "Failures/s", values: make([]float64, len(samples))}
+	users := chartSeries{name: "Concurrent Users", values: make([]float64, len(samples))}
+	perAttacker := make([]chartSeries, len(attackers))
+	for i, a := range attackers {
+		perAttacker[i] = chartSeries{name: a.Name, values: make([]float64, len(samples))}
+	}
+
+	var boundaries []int
+	for i, sample := range samples {
+		times[i] = sample.at
+		total.values[i] = sample.tps
+		users.values[i] = sample.concurrentUsers
+		for j, a := range attackers {
+			perAttacker[j].values[i] = sample.attackerTPS[a.Name]
+		}
+		if i > 0 {
+			if elapsed := sample.at.Sub(samples[i-1].at).Seconds(); elapsed > 0 && sample.failures >= samples[i-1].failures {
+				errs.values[i] = float64(sample.failures-samples[i-1].failures) / elapsed
+			}
+		}
+		if i == 0 || sample.stage != samples[i-1].stage {
+			boundaries = append(boundaries, i)
+		}
+	}
+
+	stageOf := func(i int) string { return samples[i].stage }
+	return []template.HTML{
+		renderLineChart("Throughput (TPS)", times, append([]chartSeries{total}, perAttacker...), boundaries, stageOf),
+		renderLineChart("Errors", times, []chartSeries{errs}, boundaries, stageOf),
+		renderLineChart("Concurrent Users", times, []chartSeries{users}, boundaries, stageOf),
+	}
+}
+
+// renderLineChart 生成内联的SVG折线图，不依赖任何外部资源
+func renderLineChart(title string, times []time.Time, series []chartSeries, boundaries []int, stageOf func(int) string) template.HTML {
+	const width, height, padding = 960.0, 260.0, 48.0
+
+	var max float64
+	for _, s := range series {
+		for _, v := range s.values {
+			if v > max {
+				max = v
+			}
+		}
+	}
+	if max == 0 {
+		max = 1
+	}
+	begin, end := times[0], times[len(times)-1]
+	span := end.Sub(begin).Seconds()
+	x := func(i int) float64 {
+		if span <= 0 {
+			return padding
+		}
+		return padding + times[i].Sub(begin).Seconds()/span*(width-2*padding)
+	}
+	y := func(v float64) float64 {
+		return height - padding - v/max*(height-2*padding)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" font-size="11">`, width, height)
+	fmt.Fprintf(&b, `<text x="%.0f" y="18" font-size="14" font-weight="bold">%s</text>`, padding, html.EscapeString(title))
+	fmt.Fprintf(&b, `<line x1="%.0f" y1="%.0f" x2="%.0f" y2="%.0f" stroke="#999"/>`, padding, height-padding, width-padding, height-padding)
+	fmt.Fprintf(&b, `<line x1="%.0f" y1="%.0f" x2="%.0f" y2="%.0f" stroke="#999"/>`, padding, padding, padding, height-padding)
+	fmt.Fprintf(&b, `<text x="%.0f" y="%.0f" text-anchor="end">%s</text>`, padding-4, padding+4, strconv.FormatFloat(max, 'f', 2, 64))
+	fmt.Fprintf(&b, `<text x="%.0f" y="%.0f" text-anchor="end">0</text>`, padding-4, height-padding+4)
+	fmt.Fprintf(&b, `<text x="%.0f" y="%.0f">%s</text>`, padding, height-padding+16, begin.Format("15:04:05"))
+	fmt.Fprintf(&b, `<text x="%.0f" y="%.0f" text-anchor="end">%s</text>`, width-padding, height-padding+16, end.Format("15:04:05"))
+
+	for _, i := range boundaries {
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%.0f" x2="%.1f" y2="%.0f" stroke="#bbb" stroke-dasharray="4,4"/>`, x(i), padding, x(i), height-padding)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.0f" fill="#888">stage %s</text>`, x(i)+2, padding-4, html.EscapeString(stageOf(i)))
+	}
+
+	for n, s := range series {
+		color := chartColors[n%len(chartColors)]
+		points := make([]string, len(s.values))
+		for i, v := range s.values {
+			points[i] = fmt.Sprintf("%.1f,%.1f", x(i), y(v))
+		}
+		fmt.Fprintf(&b, `<polyline fill="none" stroke="%s" stroke-width="1.5" points="%s"/>`, color, strings.Join(points, " "))
+		fmt.Fprintf(&b, `<rect x="%.0f" y="%d" width="10" height="10" fill="%s"/>`, width-padding-160, 8+n*14, color)
+		fmt.Fprintf(&b, `<text x="%.0f" y="%d">%s</text>`, width-padding-146, 17+n*14, html.EscapeString(s.name))
+	}
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
+
+// SaveHTMLReport 测试计划结束时，在dir目录下生成HTML报告
+func SaveHTMLReport(dir string) ReportHandleFunc {
+	reporter := NewHTMLReporter()
+	handle := reporter.HandleReport()
+
+	return func(ctx context.Context, report statistics.SummaryReport) {
+		handle(ctx, report)
+		if !report.FullHistory {
+			return
+		}
+
+		name := filepath.Join(dir, fmt.Sprintf("%s-%s.html", reportFileName(report.Extras[KeyPlan]), time.Now().Format("20060102150405")))
+		f, err := os.Create(name)
+		if err != nil {
+			Logger.Error("failed to create html report", zap.String("file", name), zap.Error(err))
+			return
+		}
+		defer f.Close()
+
+		if err := reporter.Render(f); err != nil {
+			Logger.Error("failed to render html report", zap.String("file", name), zap.Error(err))
+			return
+		}
+		Logger.Info("generated html report", zap.String("file", name))
+	}
+}
+
+func reportFileName(plan string) string {
+	if plan == "" {
+		return "ultron"
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' {
+			return r
+		}
+		return '-'
+	}, plan)
+}
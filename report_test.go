@@ -0,0 +1,68 @@
+package ultron
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wosai/ultron/v2/pkg/statistics"
+)
+
+func newHTMLTestReport(plan, stage string, users int, full bool) statistics.SummaryReport {
+	sg := statistics.NewStatisticianGroup()
+	sg.SetTag(KeyPlan, plan)
+	sg.SetTag(KeyStage, stage)
+	for i := 1; i <= 10; i++ {
+		sg.Record(statistics.AttackResult{Name: "unittest", Duration: time.Duration(i) * time.Millisecond})
+	}
+	sg.Record(statistics.AttackResult{Name: "unittest", Duration: time.Millisecond, Error: errors.New("<bad status code>")})
+	report := sg.Report(full)
+	report.ConcurrentUsers = users
+	return report
+}
+
+func TestHTMLReporter_Render(t *testing.T) {
+	reporter := NewHTMLReporter()
+	assert.ErrorIs(t, reporter.Render(new(bytes.Buffer)), ErrEmptyReport)
+
+	now := time.Now()
+	reporter.collect(newHTMLTestReport("html", "0", 10, false), now)
+	reporter.collect(newHTMLTestReport("html", "0", 20, false), now.Add(5*time.Second))
+	reporter.collect(newHTMLTestReport("html", "1", 30, false), now.Add(10*time.Second))
+	reporter.collect(newHTMLTestReport("html", "1", 0, true), now.Add(12*time.Second))
+	assert.EqualValues(t, len(reporter.samples), 3)
+	assert.EqualValues(t, reporter.samples[2].concurrentUsers, 30)
+
+	buf := new(bytes.Buffer)
+	assert.Nil(t, reporter.Render(buf))
+	content := buf.String()
+	assert.EqualValues(t, strings.Count(content, "<svg"), 3)
+	assert.Contains(t, content, "stage 1")
+	assert.Contains(t, content, "&lt;bad status code&gt;")
+	assert.Contains(t, content, "Full History: true")
+
+	// 新的测试计划
+	reporter.collect(newHTMLTestReport("html", "0", 10, false), now.Add(time.Minute))
+	assert.EqualValues(t, len(reporter.samples), 1)
+	assert.Nil(t, reporter.final)
+}
+
+func TestSaveHTMLReport(t *testing.T) {
+	dir := t.TempDir()
+	handle := SaveHTMLReport(dir)
+	handle(context.TODO(), newHTMLTestReport("save/html", "0", 10, false))
+	handle(context.TODO(), newHTMLTestReport("save/html", "0", 10, true))
+
+	files, err := filepath.Glob(filepath.Join(dir, "save-html-*.html"))
+	assert.Nil(t, err)
+	assert.EqualValues(t, len(files), 1)
+	data, err := os.ReadFile(files[0])
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), "save/html")
+}
@@ -5,10 +5,13 @@ import (
 	"compress/gzip"
 	"embed"
 	"encoding/json"
+	"errors"
 	"io"
 	"io/fs"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -17,6 +20,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	dto "github.com/prometheus/client_model/go"
 	"github.com/prometheus/prom2json"
+	"github.com/wosai/ultron/v2/pkg/genproto"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 )
@@ -27,13 +31,45 @@ type (
 	}
 
 	restResponse struct {
-		Result       bool   `json:"result,omitempty"`
-		ErrorMessage string `json:"error_message,omitempty"`
+		Result       bool        `json:"result,omitempty"`
+		ErrorMessage string      `json:"error_message,omitempty"`
+		Data         interface{} `json:"data,omitempty"`
 	}
 
 	requestStartPlan struct {
-		Name   string           `json:"name"`
-		Stages []*V1StageConfig `json:"stages"`
+		Name       string                      `json:"name"`
+		Stages     []*V1StageConfig            `json:"stages"`
+		RateLimit  *RateLimit                  `json:"rate_limit,omitempty"`
+		Assertions map[string][]*HTTPAssertion `json:"assertions,omitempty"`
+	}
+
+	requestAdjustStage struct {
+		Strategy *typedConfig `json:"strategy,omitempty"`
+		Timer    *typedConfig `json:"timer,omitempty"`
+	}
+
+	requestEnqueuePlan struct {
+		Name       string                      `json:"name"`
+		Stages     []*V1StageConfig            `json:"stages"`
+		RateLimit  *RateLimit                  `json:"rate_limit,omitempty"`
+		Assertions map[string][]*HTTPAssertion `json:"assertions,omitempty"`
+		CoolDown   time.Duration               `json:"cool_down,omitempty"`
+		Cron       string                      `json:"cron,omitempty"`
+	}
+
+	requestMoveQueuedPlan struct {
+		Position int `json:"position"`
+	}
+
+	requestExtendStage struct {
+		Duration time.Duration `json:"duration,omitempty"`
+		Requests uint64        `json:"requests,omitempty"`
+	}
+
+	// typedConfig 携带类型名称的压测策略、延时器配置
+	typedConfig struct {
+		Type   string          `json:"type"`
+		Config json.RawMessage `json:"config"`
 	}
 )
 
@@ -54,6 +90,8 @@ func (rest *restServer) handleStartNewPlan() http.HandlerFunc {
 		}
 
 		plan := NewPlan(req.Name)
+		plan.SetRateLimit(req.RateLimit)
+		plan.SetAssertions(req.Assertions)
 		for _, stage := range req.Stages {
 			plan.AddStages(stage)
 		}
@@ -69,6 +107,163 @@ func (rest *restServer) handleStopPlan() http.HandlerFunc {
 	}
 }
 
+func (rest *restServer) handleAdjustStage() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		req := new(requestAdjustStage)
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			Logger.Error("failed to parse request body", zap.Error(err))
+			renderResponse(err, rw, r)
+			return
+		}
+
+		var strategy AttackStrategy
+		var timer Timer
+		var err error
+		if req.Strategy != nil {
+			strategy, err = defaultAttackStrategyConverter.convertDTO(&genproto.AttackStrategyDTO{Type: req.Strategy.Type, AttackStrategy: req.Strategy.Config})
+			if err != nil {
+				Logger.Error("failed to parse attack strategy", zap.Error(err))
+				renderResponse(err, rw, r)
+				return
+			}
+		}
+		if req.Timer != nil {
+			timer, err = defaultTimerConverter.convertDTO(&genproto.TimerDTO{Type: req.Timer.Type, Timer: req.Timer.Config})
+			if err != nil {
+				Logger.Error("failed to parse timer", zap.Error(err))
+				renderResponse(err, rw, r)
+				return
+			}
+		}
+		err = rest.runner.AdjustCurrentStage(strategy, timer)
+		renderResponse(err, rw, r)
+	}
+}
+
+func (rest *restServer) handleSkipStage() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		err := rest.runner.SkipCurrentStage()
+		renderResponse(err, rw, r)
+	}
+}
+
+func (rest *restServer) handleExtendStage() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		req := new(requestExtendStage)
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			Logger.Error("failed to parse request body", zap.Error(err))
+			renderResponse(err, rw, r)
+			return
+		}
+		err := rest.runner.ExtendCurrentStage(req.Duration, req.Requests)
+		renderResponse(err, rw, r)
+	}
+}
+
+func (rest *restServer) handleListQueuedPlans() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		renderData(rest.runner.QueuedPlans(), nil, rw, r)
+	}
+}
+
+func (rest *restServer) handleEnqueuePlan() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		req := new(requestEnqueuePlan)
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			Logger.Error("failed to parse request body", zap.Error(err))
+			renderResponse(err, rw, r)
+			return
+		}
+
+		plan := NewPlan(req.Name)
+		plan.SetRateLimit(req.RateLimit)
+		plan.SetAssertions(req.Assertions)
+		for _, stage := range req.Stages {
+			plan.AddStages(stage)
+		}
+		id, err := rest.runner.EnqueuePlan(plan, WithCoolDown(req.CoolDown), WithCronSchedule(req.Cron))
+		if err != nil {
+			renderResponse(err, rw, r)
+			return
+		}
+		renderData(map[string]string{"id": id}, nil, rw, r)
+	}
+}
+
+func (rest *restServer) handleMoveQueuedPlan() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		req := new(requestMoveQueuedPlan)
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			Logger.Error("failed to parse request body", zap.Error(err))
+			renderResponse(err, rw, r)
+			return
+		}
+		err := rest.runner.MoveQueuedPlan(chi.URLParam(r, "id"), req.Position)
+		renderResponse(err, rw, r)
+	}
+}
+
+func (rest *restServer) handleCancelQueuedPlan() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		err := rest.runner.CancelQueuedPlan(chi.URLParam(r, "id"))
+		renderResponse(err, rw, r)
+	}
+}
+
+func handleDownloadHTMLReport(reporter *HTMLReporter) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		buf := new(bytes.Buffer)
+		if err := reporter.Render(buf); err != nil {
+			renderResponse(err, rw, r)
+			return
+		}
+		rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+		rw.Header().Set("Content-Disposition", `attachment; filename="ultron-report.html"`)
+		rw.Write(buf.Bytes())
+	}
+}
+
+func handleQueryDiagnostics(collector *diagnosticCollector) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		renderData(collector.query(query.Get("attacker"), query.Get("error")), nil, rw, r)
+	}
+}
+
+func handleListHistory(history *reportHistory) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		renderData(history.list(), nil, rw, r)
+	}
+}
+
+// handleQueryHistory 查询报告时间序列，since支持RFC3339格式的时间以及相对当前的时长（如5m）
+func handleQueryHistory(history *reportHistory) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		q := HistoryQuery{Plan: query.Get("plan")}
+		if since := query.Get("since"); since != "" {
+			if t, err := time.Parse(time.RFC3339, since); err == nil {
+				q.Since = t
+			} else if d, err := time.ParseDuration(since); err == nil && d > 0 {
+				q.Since = time.Now().Add(-d)
+			} else {
+				renderResponse(errors.New("bad since parameter, RFC3339 time or duration is expected"), rw, r)
+				return
+			}
+		}
+		for _, attackers := range query["attacker"] {
+			for _, name := range strings.Split(attackers, ",") {
+				if name = strings.TrimSpace(name); name != "" {
+					q.Attackers = append(q.Attackers, name)
+				}
+			}
+		}
+
+		ret, err := history.query(q)
+		renderData(ret, err, rw, r)
+	}
+}
+
 func metricToJson(next http.Handler) http.Handler {
 	fn := func(w http.ResponseWriter, r *http.Request) {
 		// before
@@ -115,21 +310,26 @@ func metricToJson(next http.Handler) http.Handler {
 }
 
 func renderResponse(err error, w http.ResponseWriter, r *http.Request) {
+	renderData(nil, err, w, r)
+}
+
+func renderData(data interface{}, err error, w http.ResponseWriter, r *http.Request) {
 	ret := &restResponse{}
 	if err == nil {
 		ret.Result = true
+		ret.Data = data
 	} else {
 		ret.ErrorMessage = err.Error()
 	}
 
-	data, err := json.Marshal(ret)
+	body, err := json.Marshal(ret)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		w.Write([]byte(http.StatusText(http.StatusInternalServerError)))
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
-	w.Write(data)
+	w.Write(body)
 }
 
 func buildHTTPRouter(runner *masterRunner) http.Handler {
@@ -147,6 +347,14 @@ func buildHTTPRouter(runner *masterRunner) http.Handler {
 	{
 		route.Post("/api/v1/plan", rest.handleStartNewPlan())
 		route.Delete("/api/v1/plan", rest.handleStopPlan())
+		route.Patch("/api/v1/plan/stage", rest.handleAdjustStage())
+		route.Post("/api/v1/plan/stage/skip", rest.handleSkipStage())
+		route.Post("/api/v1/plan/stage/extend", rest.handleExtendStage())
+
+		route.Get("/api/v1/queue", rest.handleListQueuedPlans())
+		route.Post("/api/v1/queue", rest.handleEnqueuePlan())
+		route.Patch("/api/v1/queue/{id}", rest.handleMoveQueuedPlan())
+		route.Delete("/api/v1/queue/{id}", rest.handleCancelQueuedPlan())
 	}
 
 	// static files
@@ -160,6 +368,28 @@ func buildHTTPRouter(runner *masterRunner) http.Handler {
 	})
 	route.Handle("/static/*", http.StripPrefix("/static/", http.FileServer(http.FS(content))))
 
+	// 推送聚合报告、测试计划事件
+	hub := newStreamHub(64)
+	runner.SubscribeReport(hub.handleReport())
+	runner.eventbus.subscribePlanEvent(hub.handlePlanEvent())
+	route.Get("/api/v1/stream", hub.serveSSE())
+
+	// 离线HTML报告下载
+	reporter := NewHTMLReporter()
+	runner.SubscribeReport(reporter.HandleReport())
+	route.Get("/api/v1/report.html", handleDownloadHTMLReport(reporter))
+
+	// 失败请求的诊断信息
+	diagnostics := newDiagnosticCollector()
+	runner.SubscribeReport(diagnostics.handleReport())
+	route.Get("/api/v1/diagnostics", handleQueryDiagnostics(diagnostics))
+
+	// 报告时间序列
+	history := newReportHistory(loadedOption.History.MaxPlans, loadedOption.History.MaxPoints)
+	runner.SubscribeReport(history.handleReport())
+	route.Get("/api/v1/history", handleListHistory(history))
+	route.Get("/api/v1/history/series", handleQueryHistory(history))
+
 	// prometheus exporter
 	exporter := newMetric(runner)
 	runner.SubscribeReport(exporter.handleReport()) // 订阅report
@@ -1,6 +1,7 @@
 package ultron
 
 import (
+	"context"
 	"errors"
 	"net"
 	"net/http"
@@ -11,22 +12,31 @@ import (
 	"time"
 
 	"github.com/wosai/ultron/v2/pkg/genproto"
+	"github.com/wosai/ultron/v2/pkg/statistics"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 )
 
 type (
 	MasterRunner interface {
-		Launch(...grpc.ServerOption) error   // 服务启动
-		StartPlan(Plan) error                // 开始执行某个测试计划
-		StopPlan()                           // 停止当前计划
-		SubscribeReport(...ReportHandleFunc) // 订阅聚合报告
+		Launch(...grpc.ServerOption) error                  // 服务启动
+		StartPlan(Plan) error                               // 开始执行某个测试计划
+		StopPlan()                                          // 停止当前计划
+		SubscribeReport(...ReportHandleFunc)                // 订阅聚合报告
+		AdjustCurrentStage(AttackStrategy, Timer) error     // 在线调整当前阶段的压测策略、延时器
+		SkipCurrentStage() error                            // 立即结束当前阶段
+		ExtendCurrentStage(time.Duration, uint64) error     // 延长当前阶段的持续时长、请求总数
+		EnqueuePlan(Plan, ...EnqueueOption) (string, error) // 测试计划排队执行
+		QueuedPlans() []QueuedPlan                          // 排队中的测试计划
+		MoveQueuedPlan(string, int) error                   // 调整排队顺序
+		CancelQueuedPlan(string) error                      // 取消排队中的测试计划
 	}
 
 	SlaveRunner interface {
-		Connect(string, ...grpc.DialOption) error // 连接master
-		SubscribeResult(...ResultHandleFunc)      // 订阅Attacker的执行结果
-		Assign(Task)                              // 指派压测任务
+		Connect(string, ...grpc.DialOption) error  // 连接master
+		SubscribeResult(...ResultHandleFunc)       // 订阅Attacker的执行结果
+		Assign(Task)                               // 指派压测任务
+		ClassifyErrors(statistics.ErrorClassifier) // 自定义错误归类，无法归类时使用内置的归类；只对之后新出现的Attacker统计生效，需在开始压测前调用
 	}
 
 	LocalRunner interface {
@@ -34,8 +44,16 @@ type (
 		Assign(Task)
 		SubscribeReport(...ReportHandleFunc)
 		SubscribeResult(...ResultHandleFunc)
+		ClassifyErrors(statistics.ErrorClassifier)
 		StartPlan(Plan) error
 		StopPlan()
+		AdjustCurrentStage(AttackStrategy, Timer) error
+		SkipCurrentStage() error
+		ExtendCurrentStage(time.Duration, uint64) error
+		EnqueuePlan(Plan, ...EnqueueOption) (string, error)
+		QueuedPlans() []QueuedPlan
+		MoveQueuedPlan(string, int) error
+		CancelQueuedPlan(string) error
 	}
 
 	masterRunner struct {
@@ -43,6 +61,7 @@ type (
 		plan       Plan
 		eventbus   *eventbus
 		supervisor *slaveSupervisor
+		queue      *planQueue
 		rpc        *grpc.Server
 		rest       *http.Server
 		mu         sync.RWMutex
@@ -90,9 +109,11 @@ func NewSlaveRunner() SlaveRunner {
 }
 
 func newMasterRunner() *masterRunner {
-	return &masterRunner{
+	runner := &masterRunner{
 		eventbus: defaultEventBus,
 	}
+	runner.queue = newPlanQueue(runner, loadedOption.Queue.StoreFile)
+	return runner
 }
 
 // Launch 主线程，如果发生错误则关闭
@@ -135,6 +156,12 @@ func (r *masterRunner) Launch(opts ...grpc.ServerOption) error {
 	}()
 
 	<-start
+
+	// 恢复排队中的测试计划
+	if err := r.queue.load(); err != nil {
+		Logger.Error("failed to load plan queue", zap.String("store_file", r.queue.storeFile), zap.Error(err))
+	}
+	go r.queue.run(context.Background(), 1*time.Second)
 	return nil
 }
 
@@ -169,6 +196,39 @@ func (r *masterRunner) StartPlan(p Plan) error {
 	return nil
 }
 
+func (r *masterRunner) isRunning() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.plan != nil && r.plan.Status() == StatusRunning
+}
+
+func (r *masterRunner) EnqueuePlan(p Plan, opts ...EnqueueOption) (string, error) {
+	qp, err := r.queue.enqueue(p, opts...)
+	if err != nil {
+		Logger.Error("failed to enqueue plan", zap.Error(err))
+		return "", err
+	}
+	Logger.Info("enqueued plan", zap.String("queued_id", qp.ID), zap.String("plan_name", qp.Name), zap.String("cron", qp.Cron))
+	return qp.ID, nil
+}
+
+func (r *masterRunner) QueuedPlans() []QueuedPlan {
+	return r.queue.list()
+}
+
+func (r *masterRunner) MoveQueuedPlan(id string, position int) error {
+	return r.queue.move(id, position)
+}
+
+func (r *masterRunner) CancelQueuedPlan(id string) error {
+	if err := r.queue.cancel(id); err != nil {
+		Logger.Error("failed to cancel queued plan", zap.String("queued_id", id), zap.Error(err))
+		return err
+	}
+	Logger.Info("canceled queued plan", zap.String("queued_id", id))
+	return nil
+}
+
 func (r *masterRunner) StopPlan() {
 	r.mu.RLock()
 	if r.scheduler == nil {
@@ -181,6 +241,57 @@ func (r *masterRunner) StopPlan() {
 	}
 }
 
+func (r *masterRunner) AdjustCurrentStage(strategy AttackStrategy, t Timer) error {
+	r.mu.RLock()
+	scheduler := r.scheduler
+	r.mu.RUnlock()
+
+	if scheduler == nil {
+		Logger.Error("cannot adjust current stage without running plan", zap.Error(ErrPlanNotRunning))
+		return ErrPlanNotRunning
+	}
+	if err := scheduler.adjustCurrentStage(strategy, t); err != nil {
+		Logger.Error("failed to adjust current stage", zap.Error(err))
+		return err
+	}
+	Logger.Info("adjusted current stage", zap.Any("strategy", strategy), zap.Any("timer", t))
+	return nil
+}
+
+func (r *masterRunner) SkipCurrentStage() error {
+	r.mu.RLock()
+	scheduler := r.scheduler
+	r.mu.RUnlock()
+
+	if scheduler == nil {
+		Logger.Error("cannot skip current stage without running plan", zap.Error(ErrPlanNotRunning))
+		return ErrPlanNotRunning
+	}
+	if err := scheduler.skipCurrentStage(); err != nil {
+		Logger.Error("failed to skip current stage", zap.Error(err))
+		return err
+	}
+	Logger.Info("skipped current stage")
+	return nil
+}
+
+func (r *masterRunner) ExtendCurrentStage(duration time.Duration, requests uint64) error {
+	r.mu.RLock()
+	scheduler := r.scheduler
+	r.mu.RUnlock()
+
+	if scheduler == nil {
+		Logger.Error("cannot extend current stage without running plan", zap.Error(ErrPlanNotRunning))
+		return ErrPlanNotRunning
+	}
+	if err := scheduler.extendCurrentStage(duration, requests); err != nil {
+		Logger.Error("failed to extend current stage", zap.Error(err))
+		return err
+	}
+	Logger.Info("extended current stage", zap.Duration("duration", duration), zap.Uint64("requests", requests))
+	return nil
+}
+
 func (r *masterRunner) SubscribeReport(fns ...ReportHandleFunc) {
 	for _, fn := range fns {
 		r.eventbus.subscribeReport(fn)
@@ -228,6 +339,10 @@ func (lr *localRunner) SubscribeResult(fns ...ResultHandleFunc) {
 	lr.slave.SubscribeResult(fns...)
 }
 
+func (lr *localRunner) ClassifyErrors(classifier statistics.ErrorClassifier) {
+	lr.slave.ClassifyErrors(classifier)
+}
+
 func (lr *localRunner) SubscribeReport(fns ...ReportHandleFunc) {
 	lr.master.SubscribeReport(fns...)
 }
@@ -239,3 +354,31 @@ func (lr *localRunner) StartPlan(p Plan) error {
 func (lr *localRunner) StopPlan() {
 	lr.master.StopPlan()
 }
+
+func (lr *localRunner) AdjustCurrentStage(strategy AttackStrategy, t Timer) error {
+	return lr.master.AdjustCurrentStage(strategy, t)
+}
+
+func (lr *localRunner) EnqueuePlan(p Plan, opts ...EnqueueOption) (string, error) {
+	return lr.master.EnqueuePlan(p, opts...)
+}
+
+func (lr *localRunner) QueuedPlans() []QueuedPlan {
+	return lr.master.QueuedPlans()
+}
+
+func (lr *localRunner) MoveQueuedPlan(id string, position int) error {
+	return lr.master.MoveQueuedPlan(id, position)
+}
+
+func (lr *localRunner) CancelQueuedPlan(id string) error {
+	return lr.master.CancelQueuedPlan(id)
+}
+
+func (lr *localRunner) SkipCurrentStage() error {
+	return lr.master.SkipCurrentStage()
+}
+
+func (lr *localRunner) ExtendCurrentStage(duration time.Duration, requests uint64) error {
+	return lr.master.ExtendCurrentStage(duration, requests)
+}
@@ -0,0 +1,177 @@
+package ultron
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wosai/ultron/v2/pkg/genproto"
+)
+
+type (
+	// Scenario 与阶段的默认场景并行执行的命名场景，拥有独立的压测策略、Timer以及Attacker子集；
+	// JSON格式同scenarioDefinition，strategy、timer以{"type": ..., "config": ...}表示
+	Scenario struct {
+		Name     string            `json:"name"`
+		Strategy AttackStrategy    `json:"strategy"`
+		Timer    Timer             `json:"timer,omitempty"`
+		Weights  map[string]uint32 `json:"weights,omitempty"` // 仅执行列出的Attacker，为空时执行Task中的全部Attacker
+	}
+
+	// ScenarioStage Stage的可选实现，阶段自身的压测策略、Timer、权重构成默认场景
+	ScenarioStage interface {
+		Stage
+		GetScenarios() []*Scenario
+	}
+
+	V1ScenarioConfig struct {
+		Name            string            `json:"name"`
+		ConcurrentUsers int               `json:"concurrent_users"`
+		RampUpPeriod    int               `json:"ramp_up_period"` // 单位秒
+		MinWait         time.Duration     `json:"min_wait,omitempty"`
+		MaxWait         time.Duration     `json:"max_wait,omitempty"`
+		Weights         map[string]uint32 `json:"weights,omitempty"`
+	}
+
+	// scenarioDefinition 可序列化的场景描述
+	scenarioDefinition struct {
+		Name     string            `json:"name"`
+		Strategy *typedConfig      `json:"strategy"`
+		Timer    *typedConfig      `json:"timer"`
+		Weights  map[string]uint32 `json:"weights,omitempty"`
+	}
+)
+
+var (
+	_ ScenarioStage = (*stage)(nil)
+	_ ScenarioStage = (*V1StageConfig)(nil)
+)
+
+func (v1 *V1ScenarioConfig) scenario() *Scenario {
+	return &Scenario{
+		Name:     v1.Name,
+		Strategy: &FixedConcurrentUsers{ConcurrentUsers: v1.ConcurrentUsers, RampUpPeriod: v1.RampUpPeriod},
+		Timer:    &UniformRandomTimer{MinWait: v1.MinWait, MaxWait: v1.MaxWait},
+		Weights:  v1.Weights,
+	}
+}
+
+// stageScenarios 未实现ScenarioStage时返回nil
+func stageScenarios(s Stage) []*Scenario {
+	if ss, ok := s.(ScenarioStage); ok {
+		return ss.GetScenarios()
+	}
+	return nil
+}
+
+// checkScenarios 场景名称不能为空且不能重复
+func checkScenarios(scenarios []*Scenario) error {
+	names := make(map[string]struct{}, len(scenarios))
+	for _, s := range scenarios {
+		if s == nil {
+			return errors.New("scenario cannot be nil")
+		}
+		if s.Name == "" {
+			return errors.New("scenario name cannot be empty")
+		}
+		if _, ok := names[s.Name]; ok {
+			return fmt.Errorf("duplicated scenario %s", s.Name)
+		}
+		names[s.Name] = struct{}{}
+
+		if err := checkAttackStrategy(s.Strategy); err != nil {
+			return fmt.Errorf("scenario %s: %w", s.Name, err)
+		}
+		if err := defaultTimerConverter.check(scenarioTimer(s)); err != nil {
+			return fmt.Errorf("scenario %s: %w", s.Name, err)
+		}
+		if err := checkWeights(s.Weights); err != nil {
+			return fmt.Errorf("scenario %s: %w", s.Name, err)
+		}
+	}
+	return nil
+}
+
+func scenarioTimer(s *Scenario) Timer {
+	if s.Timer == nil {
+		return NonstopTimer{}
+	}
+	return s.Timer
+}
+
+// convertScenario strategy为切分后该slave的压测策略
+func convertScenario(s *Scenario, strategy AttackStrategy) (*genproto.ScenarioDTO, error) {
+	as, err := defaultAttackStrategyConverter.convertAttackStrategy(strategy)
+	if err != nil {
+		return nil, err
+	}
+	t, err := defaultTimerConverter.convertTimer(scenarioTimer(s))
+	if err != nil {
+		return nil, err
+	}
+	return &genproto.ScenarioDTO{Name: s.Name, AttackStrategy: as, Timer: t, Weights: s.Weights}, nil
+}
+
+func newScenarioFromDTO(dto *genproto.ScenarioDTO) (*Scenario, error) {
+	strategy, err := defaultAttackStrategyConverter.convertDTO(dto.GetAttackStrategy())
+	if err != nil {
+		return nil, err
+	}
+	timer, err := defaultTimerConverter.convertDTO(dto.GetTimer())
+	if err != nil {
+		return nil, err
+	}
+	return &Scenario{Name: dto.GetName(), Strategy: strategy, Timer: timer, Weights: dto.GetWeights()}, nil
+}
+
+func newScenarioDefinition(s *Scenario) (*scenarioDefinition, error) {
+	dto, err := convertScenario(s, s.Strategy)
+	if err != nil {
+		return nil, err
+	}
+	return &scenarioDefinition{
+		Name:     s.Name,
+		Strategy: &typedConfig{Type: dto.AttackStrategy.Type, Config: dto.AttackStrategy.AttackStrategy},
+		Timer:    &typedConfig{Type: dto.Timer.Type, Config: dto.Timer.Timer},
+		Weights:  s.Weights,
+	}, nil
+}
+
+// MarshalJSON 通过scenarioDefinition序列化，压测策略与Timer需已注册
+func (s *Scenario) MarshalJSON() ([]byte, error) {
+	sd, err := newScenarioDefinition(s)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(sd)
+}
+
+// UnmarshalJSON 通过scenarioDefinition反序列化，未设置timer时使用NonstopTimer
+func (s *Scenario) UnmarshalJSON(b []byte) error {
+	sd := &scenarioDefinition{}
+	if err := json.Unmarshal(b, sd); err != nil {
+		return err
+	}
+	if sd.Timer == nil {
+		sd.Timer = &typedConfig{Type: NonstopTimer{}.Name(), Config: json.RawMessage("{}")}
+	}
+	built, err := sd.build()
+	if err != nil {
+		return err
+	}
+	*s = *built
+	return nil
+}
+
+func (sd *scenarioDefinition) build() (*Scenario, error) {
+	if sd.Strategy == nil || sd.Timer == nil {
+		return nil, errors.New("bad scenario definition")
+	}
+	return newScenarioFromDTO(&genproto.ScenarioDTO{
+		Name:           sd.Name,
+		AttackStrategy: &genproto.AttackStrategyDTO{Type: sd.Strategy.Type, AttackStrategy: sd.Strategy.Config},
+		Timer:          &genproto.TimerDTO{Type: sd.Timer.Type, Timer: sd.Timer.Config},
+		Weights:        sd.Weights,
+	})
+}
@@ -0,0 +1,84 @@
+package ultron
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestV1StageConfig_Scenarios(t *testing.T) {
+	conf := &V1StageConfig{
+		ConcurrentUsers: 10,
+		Scenarios: []*V1ScenarioConfig{
+			{Name: "checkout", ConcurrentUsers: 20, RampUpPeriod: 5, MinWait: time.Second, MaxWait: 2 * time.Second, Weights: map[string]uint32{"pay": 1}},
+		},
+	}
+	scenarios := stageScenarios(conf)
+	assert.Len(t, scenarios, 1)
+	assert.EqualValues(t, "checkout", scenarios[0].Name)
+	assert.EqualValues(t, &FixedConcurrentUsers{ConcurrentUsers: 20, RampUpPeriod: 5}, scenarios[0].Strategy)
+	assert.EqualValues(t, &UniformRandomTimer{MinWait: time.Second, MaxWait: 2 * time.Second}, scenarios[0].Timer)
+	assert.EqualValues(t, map[string]uint32{"pay": 1}, scenarios[0].Weights)
+
+	assert.Nil(t, stageScenarios(&V1StageConfig{ConcurrentUsers: 10}))
+}
+
+func TestCheckScenarios(t *testing.T) {
+	valid := &Scenario{Name: "background", Strategy: &FixedConcurrentUsers{ConcurrentUsers: 10}}
+	assert.Nil(t, checkScenarios(nil))
+	assert.Nil(t, checkScenarios([]*Scenario{valid}))
+
+	assert.Error(t, checkScenarios([]*Scenario{nil}))
+	assert.Error(t, checkScenarios([]*Scenario{{Strategy: &FixedConcurrentUsers{ConcurrentUsers: 10}}}))
+	assert.Error(t, checkScenarios([]*Scenario{valid, valid}))
+	assert.Error(t, checkScenarios([]*Scenario{{Name: "empty"}}))
+	assert.Error(t, checkScenarios([]*Scenario{{Name: "zero", Strategy: &FixedConcurrentUsers{}}}))
+	assert.Error(t, checkScenarios([]*Scenario{{Name: "weights", Strategy: &FixedConcurrentUsers{ConcurrentUsers: 1}, Weights: map[string]uint32{"read": 0}}}))
+
+	plan := NewPlan("scenarios")
+	plan.AddStages(BuildStage().WithAttackStrategy(&FixedConcurrentUsers{ConcurrentUsers: 10}).WithScenarios(valid, valid))
+	assert.Error(t, plan.validate())
+}
+
+func TestScenarioDefinition(t *testing.T) {
+	sc := &Scenario{
+		Name:     "checkout",
+		Strategy: &FixedConcurrentUsers{ConcurrentUsers: 20, RampUpPeriod: 5},
+		Timer:    &ConstantTimer{Wait: time.Second},
+		Weights:  map[string]uint32{"pay": 1},
+	}
+	def, err := newScenarioDefinition(sc)
+	assert.Nil(t, err)
+	built, err := def.build()
+	assert.Nil(t, err)
+	assert.EqualValues(t, sc, built)
+
+	def, err = newScenarioDefinition(&Scenario{Name: "nonstop", Strategy: &FixedConcurrentUsers{ConcurrentUsers: 1}})
+	assert.Nil(t, err)
+	built, err = def.build()
+	assert.Nil(t, err)
+	assert.EqualValues(t, NonstopTimer{}, built.Timer)
+}
+
+func TestScenario_JSON(t *testing.T) {
+	sc := &Scenario{
+		Name:     "checkout",
+		Strategy: &FixedConcurrentUsers{ConcurrentUsers: 20, RampUpPeriod: 5},
+		Timer:    &ConstantTimer{Wait: time.Second},
+		Weights:  map[string]uint32{"pay": 1},
+	}
+	data, err := json.Marshal([]*Scenario{sc})
+	assert.Nil(t, err)
+	var scenarios []*Scenario
+	assert.Nil(t, json.Unmarshal(data, &scenarios))
+	assert.EqualValues(t, []*Scenario{sc}, scenarios)
+
+	got := &Scenario{}
+	assert.Nil(t, json.Unmarshal([]byte(`{"name":"nonstop","strategy":{"type":"fixed-concurrent-users","config":{"concurrent_users":1}}}`), got))
+	assert.EqualValues(t, &Scenario{Name: "nonstop", Strategy: &FixedConcurrentUsers{ConcurrentUsers: 1}, Timer: NonstopTimer{}}, got)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"name":"bad","strategy":{"type":"unknown","config":{}}}`), got))
+	assert.Error(t, json.Unmarshal([]byte(`{"name":"bad"}`), got))
+}
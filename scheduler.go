@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"sync"
 	"time"
 
@@ -19,7 +20,10 @@ type (
 		plan       *plan
 		supervisor *slaveSupervisor
 		eventbus   reportBus
+		events     planEventBus
+		wakeup     chan struct{} // 要求patrol立即巡检
 		mu         sync.RWMutex
+		dispatch   sync.Mutex // 阶段的变更与下发作为整体执行，避免将过期的阶段下发给slave
 	}
 )
 
@@ -27,6 +31,8 @@ func newScheduler(sup *slaveSupervisor) *scheduler {
 	return &scheduler{
 		supervisor: sup,
 		eventbus:   defaultEventBus,
+		events:     defaultEventBus,
+		wakeup:     make(chan struct{}, 1),
 	}
 }
 
@@ -47,14 +53,17 @@ func (s *scheduler) start(plan *plan) error {
 	s.plan = plan
 	s.mu.Unlock()
 
+	s.dispatch.Lock()
 	_, _, stage, err := plan.stopCurrentAndStartNext(-1, statistics.SummaryReport{})
-	if err != nil {
-		return err
+	if err == nil {
+		err = s.supervisor.NextStage(s.ctx, 0, stage, plan.GetRateLimit(), plan.GetAssertions())
 	}
-
-	if err := s.supervisor.NextStage(s.ctx, stage.GetStrategy(), stage.GetTimer()); err != nil {
+	s.dispatch.Unlock()
+	if err != nil {
 		return err
 	}
+	s.events.publishPlanEvent(PlanEvent{Type: EventPlanStarted, Plan: plan.Name()})
+	s.events.publishPlanEvent(PlanEvent{Type: EventStageStarted, Plan: plan.Name(), Stage: 0})
 	return nil
 }
 
@@ -72,9 +81,11 @@ func (s *scheduler) stop(done bool) error {
 
 	switch {
 	case ps == StatusFinished && done: // 正常结束
+		s.events.publishPlanEvent(PlanEvent{Type: EventPlanFinished, Plan: plan.Name()})
 
 	case ps == StatusRunning && !done: // 被中断
 		plan.interrupt()
+		s.events.publishPlanEvent(PlanEvent{Type: EventPlanInterrupted, Plan: plan.Name()})
 
 	case !done && (ps == StatusReady || ps == StatusFinished || ps == StatusInterrupted):
 		return nil
@@ -91,7 +102,11 @@ func (s *scheduler) stop(done bool) error {
 	cancel()
 	Logger.Info("canceled all running jobs")
 
-	report, aggErr := s.supervisor.Aggregate(true, statistics.Tag{Key: KeyPlan, Value: plan.Name()})
+	stageIndex, _ := plan.Current()
+	report, aggErr := s.supervisor.Aggregate(true,
+		statistics.Tag{Key: KeyPlan, Value: plan.Name()},
+		statistics.Tag{Key: KeyStage, Value: strconv.Itoa(stageIndex)},
+	)
 	switch {
 	case err == nil && aggErr != nil:
 		return aggErr
@@ -108,8 +123,81 @@ func (s *scheduler) stop(done bool) error {
 	}
 }
 
-func (s *scheduler) nextStage(stage Stage) error {
-	return s.supervisor.NextStage(s.ctx, stage.GetStrategy(), stage.GetTimer())
+func (s *scheduler) nextStage(index int, stage Stage) error {
+	return s.supervisor.NextStage(s.ctx, index, stage, s.plan.GetRateLimit(), s.plan.GetAssertions())
+}
+
+// adjustCurrentStage 在线调整当前阶段，重新切分后下发给各个slave
+func (s *scheduler) adjustCurrentStage(strategy AttackStrategy, t Timer) error {
+	s.mu.RLock()
+	plan := s.plan
+	ctx := s.ctx
+	s.mu.RUnlock()
+
+	if plan == nil {
+		return ErrPlanNotRunning
+	}
+	s.dispatch.Lock()
+	defer s.dispatch.Unlock()
+	index, stage, err := plan.adjustCurrentStage(strategy, t)
+	if err != nil {
+		return err
+	}
+	return s.supervisor.NextStage(ctx, index, stage, plan.GetRateLimit(), plan.GetAssertions())
+}
+
+// skipCurrentStage 立即结束当前阶段
+func (s *scheduler) skipCurrentStage() error {
+	s.mu.RLock()
+	plan := s.plan
+	s.mu.RUnlock()
+
+	if plan == nil {
+		return ErrPlanNotRunning
+	}
+	if err := plan.skipCurrentStage(); err != nil {
+		return err
+	}
+	s.patrolNow()
+	return nil
+}
+
+// extendCurrentStage 延长当前阶段
+func (s *scheduler) extendCurrentStage(duration time.Duration, requests uint64) error {
+	s.mu.RLock()
+	plan := s.plan
+	s.mu.RUnlock()
+
+	if plan == nil {
+		return ErrPlanNotRunning
+	}
+	if err := plan.extendCurrentStage(duration, requests); err != nil {
+		return err
+	}
+	s.patrolNow()
+	return nil
+}
+
+// advance 当前阶段结束时推进到下一阶段并下发，与adjustCurrentStage互斥
+func (s *scheduler) advance(plan *plan, index int, report statistics.SummaryReport) (stopped bool, next int, err error) {
+	s.dispatch.Lock()
+	defer s.dispatch.Unlock()
+
+	stopped, next, stage, err := plan.stopCurrentAndStartNext(index, report)
+	if err == nil && stopped {
+		if err := s.nextStage(next, stage); err != nil {
+			Logger.Error("failed to send the configurations of next stage to slaves", zap.Error(err))
+		}
+	}
+	return stopped, next, err
+}
+
+// patrolNow 不等待下一个巡检周期，立即巡检
+func (s *scheduler) patrolNow() {
+	select {
+	case s.wakeup <- struct{}{}:
+	default: // 已有待处理的巡检请求
+	}
 }
 
 // patrol scheduler核心逻辑
@@ -133,38 +221,44 @@ patrol:
 		case <-ctx.Done():
 			return ctx.Err()
 
+		case <-s.wakeup: // 人工干预后立即巡检
+			ticker.Reset(every)
+
 		case <-ticker.C:
-			report, err := s.supervisor.Aggregate(false, statistics.Tag{Key: KeyPlan, Value: plan.Name()})
-			if err != nil {
-				Logger.Warn("failed to aggregate stats report", zap.Error(err))
-				continue patrol
-			}
-			s.eventbus.publishReport(report)
-
-			stopped, next, stage, err := plan.stopCurrentAndStartNext(stageIndex, report)
-			switch {
-			case err != nil && errors.Is(err, ErrPlanClosed) && stopped: // 当前在最后一个阶段并且执行完成了，此时plan已经完成
-				Logger.Info("current plan is closed")
-				s.stop(true) // TODO： 是否还要做点什么？不做的话会拿到下一次聚合报告？
-				return nil
-
-			case err != nil && errors.Is(err, ErrPlanClosed) && !stopped: // 计划早已经结束，不干了
-				Logger.Info("this plan is complete, stop patrol")
-				return nil
-
-			case err != nil && !errors.Is(err, ErrPlanClosed):
-				Logger.Error("occur error on checking the test plan", zap.Error(err))
-				continue patrol
-
-			case err == nil && stopped: // 下一阶段
-				Logger.Info("start the next stage")
-				if err := s.nextStage(stage); err != nil {
-					Logger.Error("failed to send the configurations of next stage to slaves", zap.Error(err))
-				}
-				stageIndex = next
-
-			default: // 继续巡查
-			}
+		}
+
+		report, err := s.supervisor.Aggregate(false,
+			statistics.Tag{Key: KeyPlan, Value: plan.Name()},
+			statistics.Tag{Key: KeyStage, Value: strconv.Itoa(stageIndex)},
+		)
+		if err != nil {
+			Logger.Warn("failed to aggregate stats report", zap.Error(err))
+			continue patrol
+		}
+		report.ConcurrentUsers = s.supervisor.ConcurrentUsers()
+		s.eventbus.publishReport(report)
+
+		stopped, next, err := s.advance(plan, stageIndex, report)
+		switch {
+		case err != nil && errors.Is(err, ErrPlanClosed) && stopped: // 当前在最后一个阶段并且执行完成了，此时plan已经完成
+			Logger.Info("current plan is closed")
+			s.stop(true) // TODO： 是否还要做点什么？不做的话会拿到下一次聚合报告？
+			return nil
+
+		case err != nil && errors.Is(err, ErrPlanClosed) && !stopped: // 计划早已经结束，不干了
+			Logger.Info("this plan is complete, stop patrol")
+			return nil
+
+		case err != nil && !errors.Is(err, ErrPlanClosed):
+			Logger.Error("occur error on checking the test plan", zap.Error(err))
+			continue patrol
+
+		case err == nil && stopped: // 下一阶段
+			Logger.Info("started the next stage")
+			stageIndex = next
+			s.events.publishPlanEvent(PlanEvent{Type: EventStageStarted, Plan: plan.Name(), Stage: next})
+
+		default: // 继续巡查
 		}
 	}
 }
@@ -2,11 +2,13 @@ package ultron
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/wosai/ultron/v2/pkg/genproto"
+	"github.com/wosai/ultron/v2/pkg/statistics"
 )
 
 func TestScheduler_Start(t *testing.T) {
@@ -75,7 +77,7 @@ func TestScheduler_NextStage(t *testing.T) {
 	err = scheduler.start(plan)
 	assert.Nil(t, err)
 
-	err = scheduler.nextStage(&V1StageConfig{ConcurrentUsers: 100})
+	err = scheduler.nextStage(1, &V1StageConfig{ConcurrentUsers: 100})
 	assert.Nil(t, err)
 }
 
@@ -120,3 +122,97 @@ func TestScheduler_Patrol(t *testing.T) {
 	go scheduler.patrol(1 * time.Second)
 	<-time.After(3500 * time.Millisecond)
 }
+
+func TestScheduler_AdjustCurrentStage(t *testing.T) {
+	supervisor := newSlaveSupervisor()
+	sa := newSlaveAgent(&genproto.SubscribeRequest{SlaveId: "abc"})
+	events := make(chan *genproto.SubscribeResponse, 10)
+	go func() {
+		for event := range sa.input {
+			events <- event
+		}
+	}()
+	supervisor.Add(sa)
+	scheduler := newScheduler(supervisor)
+	err := scheduler.adjustCurrentStage(&FixedConcurrentUsers{ConcurrentUsers: 100}, nil)
+	assert.ErrorIs(t, err, ErrPlanNotRunning)
+
+	plan := NewPlan("")
+	plan.AddStages(&V1StageConfig{Duration: 1000, ConcurrentUsers: 200})
+	err = scheduler.start(plan)
+	assert.Nil(t, err)
+	<-events // PLAN_STARTED
+	<-events // NEXT_STAGE_STARTED
+
+	err = scheduler.adjustCurrentStage(&FixedConcurrentUsers{ConcurrentUsers: 100}, nil)
+	assert.Nil(t, err)
+	event := <-events
+	assert.EqualValues(t, event.Type, genproto.EventType_NEXT_STAGE_STARTED)
+	assert.EqualValues(t, event.GetAttackStrategy().GetType(), "fixed-concurrent-users")
+	assert.JSONEq(t, string(event.GetAttackStrategy().GetAttackStrategy()), `{"concurrent_users":100}`)
+}
+
+func TestScheduler_AdjustWhileAdvancing(t *testing.T) {
+	supervisor := newSlaveSupervisor()
+	sa := newSlaveAgent(&genproto.SubscribeRequest{SlaveId: "abc"})
+	events := make(chan *genproto.SubscribeResponse, 10)
+	go func() {
+		for event := range sa.input {
+			events <- event
+		}
+	}()
+	supervisor.Add(sa)
+	scheduler := newScheduler(supervisor)
+
+	plan := NewPlan("")
+	plan.AddStages(&V1StageConfig{Requests: 1, ConcurrentUsers: 200}, &V1StageConfig{Duration: time.Hour, ConcurrentUsers: 50})
+	assert.Nil(t, scheduler.start(plan))
+	<-events // PLAN_STARTED
+	<-events // NEXT_STAGE_STARTED
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 5; i++ {
+			scheduler.adjustCurrentStage(&FixedConcurrentUsers{ConcurrentUsers: 100}, nil)
+		}
+	}()
+	stopped, next, err := scheduler.advance(plan, 0, statistics.SummaryReport{TotalRequests: 10})
+	wg.Wait()
+	assert.Nil(t, err)
+	assert.True(t, stopped)
+	assert.EqualValues(t, 1, next)
+
+	// 下一阶段开始之前，调整后的阶段只能以原序号下发
+	<-time.After(100 * time.Millisecond)
+	for advanced := false; len(events) > 0; {
+		event := <-events
+		users := string(event.GetAttackStrategy().GetAttackStrategy())
+		if event.GetExecution().GetStageIndex() == 1 && !advanced {
+			assert.JSONEq(t, `{"concurrent_users":50}`, users)
+			advanced = true
+		}
+	}
+}
+
+func TestScheduler_SkipCurrentStage(t *testing.T) {
+	supervisor := newSlaveSupervisor()
+	sa := newSlaveAgent(&genproto.SubscribeRequest{SlaveId: "abc"})
+	go func() {
+		for range sa.input {
+		}
+	}()
+	supervisor.Add(sa)
+	scheduler := newScheduler(supervisor)
+	assert.ErrorIs(t, scheduler.skipCurrentStage(), ErrPlanNotRunning)
+
+	plan := NewPlan("")
+	plan.AddStages(&V1StageConfig{Duration: 1000, ConcurrentUsers: 200})
+	err := scheduler.start(plan)
+	assert.Nil(t, err)
+
+	assert.Nil(t, scheduler.skipCurrentStage())
+	assert.Nil(t, scheduler.extendCurrentStage(1*time.Minute, 0))
+	assert.EqualValues(t, len(scheduler.wakeup), 1)
+}
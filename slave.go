@@ -20,26 +20,44 @@ type (
 		ctx             context.Context
 		cancel          context.CancelFunc
 		client          genproto.UltronAPIClient
-		commander       AttackStrategyCommander
+		commander       AttackStrategyCommander // 默认场景
+		scenarios       map[string]*scenarioRunner
 		stats           *statistics.StatisticianGroup
 		task            Task
+		execution       *execution
 		eventbus        *eventbus
 		subscribeStream genproto.UltronAPI_SubscribeClient
 	}
+
+	// scenarioRunner 命名场景使用从Task复制出的独立Task以及commander
+	scenarioRunner struct {
+		task      ReweightableTask
+		commander AttackStrategyCommander
+	}
 )
 
 var _ SlaveRunner = (*slaveRunner)(nil)
 
 const (
-	KeyPlan     = "plan"
-	KeyAttacker = "attacker"
+	KeyPlan            = "plan"
+	KeyAttacker        = "attacker"
+	KeyPhase           = "phase"
+	KeyStage           = "stage"
+	KeyConcurrentUsers = "concurrent_users"
 )
 
 func newSlaveRunner() *slaveRunner {
 	return &slaveRunner{
-		id:       uuid.NewString(),
-		stats:    statistics.NewStatisticianGroup(),
-		eventbus: defaultEventBus,
+		id: uuid.NewString(),
+		stats: statistics.NewStatisticianGroup(
+			statistics.WithRecentWindow(loadedOption.Statistics.RecentWindow),
+			statistics.WithTimeline(loadedOption.Statistics.TimelineResolution, loadedOption.Statistics.TimelineMaxSlots),
+			statistics.WithMaxFailureKeys(loadedOption.Statistics.MaxFailureKeys),
+			statistics.WithFailureSamples(loadedOption.Statistics.FailureSamples),
+			statistics.WithDiagnosticSamples(loadedOption.Statistics.DiagnosticSamples),
+		),
+		scenarios: make(map[string]*scenarioRunner),
+		eventbus:  defaultEventBus,
 	}
 }
 
@@ -85,6 +103,11 @@ func (sr *slaveRunner) Assign(t Task) {
 	sr.task = t
 }
 
+// ClassifyErrors 只对之后新建的统计生效，已经开始统计的Attacker仍使用之前的归类，直至下一个测试计划
+func (sr *slaveRunner) ClassifyErrors(classifier statistics.ErrorClassifier) {
+	sr.stats.Apply(statistics.WithErrorClassifier(classifier))
+}
+
 func (sr *slaveRunner) SubscribeResult(fns ...ResultHandleFunc) {
 	for _, fn := range fns {
 		sr.eventbus.subscribeResult(fn)
@@ -121,7 +144,7 @@ func (sr *slaveRunner) working(streams genproto.UltronAPI_SubscribeClient) {
 			sr.startPlan(event.GetPlanName())
 
 		case genproto.EventType_NEXT_STAGE_STARTED:
-			sr.startNextStage(event.GetAttackStrategy(), event.GetTimer())
+			sr.startNextStage(event)
 
 		case genproto.EventType_STATUS_REPORT:
 			sr.sendStatus()
@@ -133,13 +156,14 @@ func (sr *slaveRunner) working(streams genproto.UltronAPI_SubscribeClient) {
 }
 
 func (sr *slaveRunner) startPlan(name string) {
-	if sr.commander != nil {
+	if sr.commander != nil || len(sr.scenarios) > 0 {
 		sr.stopPlan()
 		Logger.Warn("stop a exists plan before start new plan")
 	}
 
 	sr.stats.Reset()
 	sr.stats.Attach(statistics.Tag{Key: KeyPlan, Value: name})
+	sr.execution = newExecution(name, sr.id)
 	Logger.Info("start a new plan", zap.String("plan_name", name))
 }
 
@@ -156,49 +180,133 @@ func (sr *slaveRunner) submit(batch uint32) {
 	}()
 }
 
-func (sr *slaveRunner) startNextStage(s *genproto.AttackStrategyDTO, t *genproto.TimerDTO) {
-	strategy, err := defaultAttackStrategyConverter.convertDTO(s)
+func (sr *slaveRunner) startNextStage(event *genproto.SubscribeResponse) {
+	strategy, err := defaultAttackStrategyConverter.convertDTO(event.GetAttackStrategy())
 	if err != nil {
 		Logger.Error("failed to start next stage", zap.Error(err))
 		return
 	}
-	timer, err := defaultTimerConverter.convertDTO(t)
+	timer, err := defaultTimerConverter.convertDTO(event.GetTimer())
 	if err != nil {
 		Logger.Error("failed to start next stage", zap.Error(err))
 		return
 	}
 
-	if sr.commander == nil {
-		sr.commander = defaultCommanderFactory.build(strategy.Name())
-		output := sr.commander.Open(sr.ctx, sr.task)
-		go func(c <-chan statistics.AttackResult) {
-			for ret := range c {
-				if ret.IsFailure() {
-					if errors.Is(ret.Error, context.Canceled) { // 一般出现于降压阶段或者终止进程时
-						Logger.Warn("dropped the canceled attack result")
-						continue
-					}
-					Logger.Warn("received a failed attack result", zap.Error(ret.Error))
-				}
-				sr.stats.Record(ret)
-				sr.eventbus.publishResult(ret)
-			}
-		}(output)
+	if sr.execution == nil {
+		sr.execution = newExecution("", sr.id)
+	}
+	sr.execution.update(event.GetExecution())
+	sr.execution.setRateLimits(event.GetRateLimits())
+	if err := sr.execution.setAssertions(event.GetAssertions()); err != nil {
+		Logger.Error("failed to parse assertions, ignored them", zap.Error(err))
+	}
+
+	if rt, ok := sr.task.(ReweightableTask); ok {
+		if err := rt.Reweight(event.GetWeights()); err != nil {
+			Logger.Error("failed to reweight attackers, keep the previous weights", zap.Error(err))
+		}
+	} else if len(event.GetWeights()) > 0 {
+		Logger.Warn("the task does not support weights override, ignored it")
+	}
+
+	if sr.commander, err = sr.command(sr.commander, "", sr.task, strategy, timer); err != nil {
+		Logger.Error("failed to start next stage", zap.Error(err))
+		return
+	}
+	sr.startScenarios(event.GetScenarios())
+}
+
+// command 按需创建commander并下发压测策略，scenario为空时为默认场景
+func (sr *slaveRunner) command(commander AttackStrategyCommander, scenario string, t Task, strategy AttackStrategy, timer Timer) (AttackStrategyCommander, error) {
+	if commander == nil {
+		var err error
+		if commander, err = defaultCommanderFactory.build(strategy.Name()); err != nil {
+			return nil, err
+		}
+		output := commander.Open(withExecution(sr.ctx, sr.execution), t)
+		go sr.collect(scenario, output)
 	}
 
 	go func(cmd AttackStrategyCommander) {
 		cmd.Command(strategy, timer)
-	}(sr.commander)
+	}(commander)
+	return commander, nil
+}
+
+func (sr *slaveRunner) collect(scenario string, c <-chan statistics.AttackResult) {
+	for ret := range c {
+		if ret.IsFailure() {
+			if errors.Is(ret.Error, context.Canceled) { // 一般出现于降压阶段或者终止进程时
+				Logger.Warn("dropped the canceled attack result")
+				continue
+			}
+			Logger.Warn("received a failed attack result", zap.Error(ret.Error))
+		}
+		ret.Scenario = scenario
+		sr.stats.Record(ret)
+		sr.eventbus.publishResult(ret)
+	}
+}
+
+// startScenarios 启动或调整本阶段的命名场景，并关闭本阶段未包含的场景
+func (sr *slaveRunner) startScenarios(scenarios []*genproto.ScenarioDTO) {
+	active := make(map[string]struct{}, len(scenarios))
+	for _, dto := range scenarios {
+		active[dto.GetName()] = struct{}{}
+		if err := sr.startScenario(dto); err != nil {
+			Logger.Error("failed to start scenario", zap.String("scenario", dto.GetName()), zap.Error(err))
+		}
+	}
+	for name, runner := range sr.scenarios {
+		if _, ok := active[name]; !ok {
+			delete(sr.scenarios, name)
+			go runner.commander.Close()
+		}
+	}
+}
+
+func (sr *slaveRunner) startScenario(dto *genproto.ScenarioDTO) error {
+	sc, err := newScenarioFromDTO(dto)
+	if err != nil {
+		return err
+	}
+	runner, ok := sr.scenarios[sc.Name]
+	if !ok {
+		st, ok := sr.task.(ScenarioTask)
+		if !ok {
+			return errors.New("the task does not support scenarios")
+		}
+		runner = &scenarioRunner{task: st.Fork()}
+	}
+	if err := runner.task.Reweight(sc.Weights); err != nil {
+		return err
+	}
+	if runner.commander, err = sr.command(runner.commander, sc.Name, runner.task, sc.Strategy, scenarioTimer(sc)); err != nil {
+		return err
+	}
+	sr.scenarios[sc.Name] = runner
+	return nil
 }
 
 func (sr *slaveRunner) stopPlan() {
-	if commander := sr.commander; commander != nil {
+	commanders := make([]AttackStrategyCommander, 0, len(sr.scenarios)+1)
+	if sr.commander != nil {
+		commanders = append(commanders, sr.commander)
 		sr.commander = nil
-		go func(cmd AttackStrategyCommander) {
-			cmd.Close()
-			Logger.Info("current plan is stopped")
-		}(commander)
 	}
+	for _, runner := range sr.scenarios {
+		commanders = append(commanders, runner.commander)
+	}
+	sr.scenarios = make(map[string]*scenarioRunner)
+	if len(commanders) == 0 {
+		return
+	}
+	go func() {
+		for _, cmd := range commanders {
+			cmd.Close()
+		}
+		Logger.Info("current plan is stopped")
+	}()
 }
 
 func (sr *slaveRunner) sendStatus() {
@@ -206,6 +314,9 @@ func (sr *slaveRunner) sendStatus() {
 	if sr.commander != nil {
 		req.ConcurrentUsers = int32(sr.commander.ConcurrentUsers())
 	}
+	for _, runner := range sr.scenarios {
+		req.ConcurrentUsers += int32(runner.commander.ConcurrentUsers())
+	}
 	go func() {
 		if _, err := sr.client.SendStatus(sr.ctx, req); err != nil {
 			Logger.Error("failed to send status to ultron master server", zap.Error(err))
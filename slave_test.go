@@ -4,6 +4,7 @@ import (
 	"context"
 	"net"
 	"net/http"
+	"sync"
 	"testing"
 	"time"
 
@@ -94,3 +95,101 @@ func TestSlaveRunner_Working(t *testing.T) {
 
 	<-time.After(1 * time.Second)
 }
+
+func TestSlaveRunner_Scenarios(t *testing.T) {
+	slave := newSlaveRunner()
+	slave.ctx, slave.cancel = context.WithCancel(context.Background())
+	defer slave.cancel()
+	task := NewTask()
+	task.Add(newBenchmarkAttacker("read", 10*time.Millisecond), 1)
+	task.Add(newBenchmarkAttacker("pay", 10*time.Millisecond), 1)
+	slave.Assign(task)
+	slave.startPlan("scenarios")
+
+	strategy, _ := defaultAttackStrategyConverter.convertAttackStrategy(&FixedConcurrentUsers{ConcurrentUsers: 2})
+	timer, _ := defaultTimerConverter.convertTimer(NonstopTimer{})
+	scenario, _ := convertScenario(&Scenario{Name: "checkout", Strategy: &FixedConcurrentUsers{ConcurrentUsers: 3}, Weights: map[string]uint32{"pay": 1}}, &FixedConcurrentUsers{ConcurrentUsers: 3})
+	event := &genproto.SubscribeResponse{
+		Type:      genproto.EventType_NEXT_STAGE_STARTED,
+		Data:      &genproto.SubscribeResponse_AttackStrategy{AttackStrategy: strategy},
+		Timer:     timer,
+		Weights:   map[string]uint32{"pay": 0},
+		Scenarios: []*genproto.ScenarioDTO{scenario},
+	}
+	slave.startNextStage(event)
+	assert.Len(t, slave.scenarios, 1)
+	<-time.After(200 * time.Millisecond)
+
+	report := slave.stats.Report(true)
+	assert.Contains(t, report.Reports, "read")
+	assert.NotContains(t, report.Reports, "pay")
+	assert.Contains(t, report.Reports, "checkout/pay")
+	assert.NotContains(t, report.Reports, "checkout/read")
+	assert.EqualValues(t, "checkout", report.Reports["checkout/pay"].Scenario)
+
+	event.Scenarios = nil
+	slave.startNextStage(event)
+	assert.Empty(t, slave.scenarios)
+
+	slave.stopPlan()
+	assert.Nil(t, slave.commander)
+}
+
+// userIDAttacker 记录执行过的虚拟用户编号
+type userIDAttacker struct {
+	name string
+	ids  map[uint32]struct{}
+	mu   sync.Mutex
+}
+
+func (ua *userIDAttacker) Name() string {
+	return ua.name
+}
+
+func (ua *userIDAttacker) Fire(ctx context.Context) error {
+	id, _ := VirtualUserID(ctx)
+	ua.mu.Lock()
+	ua.ids[id] = struct{}{}
+	ua.mu.Unlock()
+	time.Sleep(time.Millisecond)
+	return nil
+}
+
+func TestSlaveRunner_ScenarioUserIDs(t *testing.T) {
+	slave := newSlaveRunner()
+	slave.ctx, slave.cancel = context.WithCancel(context.Background())
+	defer slave.cancel()
+	read := &userIDAttacker{name: "read", ids: make(map[uint32]struct{})}
+	pay := &userIDAttacker{name: "pay", ids: make(map[uint32]struct{})}
+	task := NewTask()
+	task.Add(read, 1)
+	task.Add(pay, 1)
+	slave.Assign(task)
+	slave.startPlan("user-ids")
+
+	strategy, _ := defaultAttackStrategyConverter.convertAttackStrategy(&FixedConcurrentUsers{ConcurrentUsers: 3})
+	timer, _ := defaultTimerConverter.convertTimer(NonstopTimer{})
+	scenario, _ := convertScenario(&Scenario{Name: "checkout", Strategy: &FixedConcurrentUsers{ConcurrentUsers: 2}, Weights: map[string]uint32{"pay": 1}}, &FixedConcurrentUsers{ConcurrentUsers: 2})
+	slave.startNextStage(&genproto.SubscribeResponse{
+		Type:      genproto.EventType_NEXT_STAGE_STARTED,
+		Data:      &genproto.SubscribeResponse_AttackStrategy{AttackStrategy: strategy},
+		Timer:     timer,
+		Execution: &genproto.ExecutionDTO{SlaveIndex: 0, SlaveCount: 1},
+		Weights:   map[string]uint32{"pay": 0},
+		Scenarios: []*genproto.ScenarioDTO{scenario},
+	})
+	<-time.After(200 * time.Millisecond)
+	slave.stopPlan()
+
+	// 默认场景与命名场景的用户编号不重复
+	read.mu.Lock()
+	defer read.mu.Unlock()
+	pay.mu.Lock()
+	defer pay.mu.Unlock()
+	assert.Len(t, read.ids, 3)
+	assert.Len(t, pay.ids, 2)
+	for id := range pay.ids {
+		assert.NotContains(t, read.ids, id)
+		assert.Less(t, id, uint32(5))
+	}
+}
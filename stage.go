@@ -1,6 +1,7 @@
 package ultron
 
 import (
+	"errors"
 	"time"
 )
 
@@ -18,11 +19,19 @@ type (
 		GetStrategy() AttackStrategy
 	}
 
+	// WeightedStage Stage的可选实现，按Attacker名称覆盖该阶段的权重
+	WeightedStage interface {
+		Stage
+		GetWeights() map[string]uint32
+	}
+
 	// stage 通用的stage对象
 	stage struct {
-		timer    Timer
-		checker  ExitConditions
-		strategy AttackStrategy
+		timer     Timer
+		checker   ExitConditions
+		strategy  AttackStrategy
+		weights   map[string]uint32
+		scenarios []*Scenario
 	}
 
 	// exitConditions 通用的退出条件
@@ -32,15 +41,22 @@ type (
 	}
 
 	V1StageConfig struct {
-		Requests        uint64        `json:"requests,omitempty"`
-		Duration        time.Duration `json:"duration,omitempty"`
-		ConcurrentUsers int           `json:"concurrent_users"`
-		RampUpPeriod    int           `json:"ramp_up_period"` // 单位秒
-		MinWait         time.Duration `json:"min_wait,omitempty"`
-		MaxWait         time.Duration `json:"max_wait,omitempty"`
+		Requests        uint64              `json:"requests,omitempty"`
+		Duration        time.Duration       `json:"duration,omitempty"`
+		ConcurrentUsers int                 `json:"concurrent_users"`
+		RampUpPeriod    int                 `json:"ramp_up_period"` // 单位秒
+		MinWait         time.Duration       `json:"min_wait,omitempty"`
+		MaxWait         time.Duration       `json:"max_wait,omitempty"`
+		Weights         map[string]uint32   `json:"weights,omitempty"`   // 按Attacker名称覆盖权重
+		Scenarios       []*V1ScenarioConfig `json:"scenarios,omitempty"` // 与默认场景并行执行的命名场景
 	}
 )
 
+var (
+	_ WeightedStage = (*stage)(nil)
+	_ WeightedStage = (*V1StageConfig)(nil)
+)
+
 // Check 是否满足退出条件
 func (sec *UniversalExitConditions) Check(actual ExitConditions) bool {
 	if sec.NeverStop() {
@@ -94,6 +110,18 @@ func (s *stage) WithAttackStrategy(d AttackStrategy) *stage {
 	return s
 }
 
+// WithWeights 按Attacker名称覆盖该阶段的权重，未指定的Attacker沿用Task的默认权重，为0时不执行
+func (s *stage) WithWeights(weights map[string]uint32) *stage {
+	s.weights = weights
+	return s
+}
+
+// WithScenarios 追加与默认场景并行执行的命名场景
+func (s *stage) WithScenarios(scenarios ...*Scenario) *stage {
+	s.scenarios = append(s.scenarios, scenarios...)
+	return s
+}
+
 func (s *stage) GetTimer() Timer {
 	return s.timer
 }
@@ -106,6 +134,14 @@ func (s *stage) GetStrategy() AttackStrategy {
 	return s.strategy
 }
 
+func (s *stage) GetWeights() map[string]uint32 {
+	return s.weights
+}
+
+func (s *stage) GetScenarios() []*Scenario {
+	return s.scenarios
+}
+
 func (v1 *V1StageConfig) GetTimer() Timer {
 	return &UniformRandomTimer{MinWait: v1.MinWait, MaxWait: v1.MaxWait}
 }
@@ -117,3 +153,49 @@ func (v1 *V1StageConfig) GetExitConditions() ExitConditions {
 func (v1 *V1StageConfig) GetStrategy() AttackStrategy {
 	return &FixedConcurrentUsers{ConcurrentUsers: v1.ConcurrentUsers, RampUpPeriod: v1.RampUpPeriod}
 }
+
+func (v1 *V1StageConfig) GetWeights() map[string]uint32 {
+	return v1.Weights
+}
+
+func (v1 *V1StageConfig) GetScenarios() []*Scenario {
+	if len(v1.Scenarios) == 0 {
+		return nil
+	}
+	scenarios := make([]*Scenario, len(v1.Scenarios))
+	for i, sc := range v1.Scenarios {
+		scenarios[i] = sc.scenario()
+	}
+	return scenarios
+}
+
+// stageWeights 未实现WeightedStage时返回nil
+// copyStage 以新的压测策略、延时器、退出条件复制阶段，保留权重以及命名场景
+func copyStage(s Stage, strategy AttackStrategy, timer Timer, ec ExitConditions) Stage {
+	return BuildStage().WithAttackStrategy(strategy).WithTimer(timer).WithExitConditions(ec).WithWeights(stageWeights(s)).WithScenarios(stageScenarios(s)...)
+}
+
+func stageWeights(s Stage) map[string]uint32 {
+	if ws, ok := s.(WeightedStage); ok {
+		return ws.GetWeights()
+	}
+	return nil
+}
+
+// checkWeights 权重覆盖不能使所有Attacker都不执行；master无法得知slave上的Attacker，只检查权重本身
+func checkWeights(weights map[string]uint32) error {
+	if len(weights) == 0 {
+		return nil
+	}
+	var positive bool
+	for name, w := range weights {
+		if name == "" {
+			return errors.New("attacker name in weights cannot be empty")
+		}
+		positive = positive || w > 0
+	}
+	if !positive {
+		return errors.New("at least one attacker should have positive weight")
+	}
+	return nil
+}
@@ -59,3 +59,23 @@ func TestV1StageConfig(t *testing.T) {
 	as := conf.GetStrategy()
 	assert.EqualValues(t, as, &FixedConcurrentUsers{ConcurrentUsers: 100, RampUpPeriod: 10})
 }
+
+func TestStage_Weights(t *testing.T) {
+	conf := &V1StageConfig{ConcurrentUsers: 10, Weights: map[string]uint32{"read": 3}}
+	assert.EqualValues(t, map[string]uint32{"read": 3}, stageWeights(conf))
+
+	s := BuildStage().WithAttackStrategy(&FixedConcurrentUsers{ConcurrentUsers: 10}).WithWeights(map[string]uint32{"write": 1})
+	assert.EqualValues(t, map[string]uint32{"write": 1}, stageWeights(s))
+
+	assert.Nil(t, checkWeights(nil))
+	assert.Nil(t, checkWeights(map[string]uint32{"read": 0, "write": 1}))
+	assert.Error(t, checkWeights(map[string]uint32{"read": 0}))
+	assert.Error(t, checkWeights(map[string]uint32{"": 1}))
+	for i := 0; i < 20; i++ { // 与map的遍历顺序无关
+		assert.Error(t, checkWeights(map[string]uint32{"read": 1, "write": 2, "": 0}))
+	}
+
+	plan := NewPlan("weights")
+	plan.AddStages(&V1StageConfig{ConcurrentUsers: 10, Weights: map[string]uint32{"read": 0}})
+	assert.Error(t, plan.validate())
+}
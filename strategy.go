@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"runtime"
 	"runtime/debug"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -45,12 +46,17 @@ type (
 		RampUpPeriod    int `json:"ramp_up_period,omitempty"` // 增压周期时长
 	}
 
+	// AttackStrategyDecoder 将master下发的配置解码为AttackStrategy
+	AttackStrategyDecoder func([]byte) (AttackStrategy, error)
+
+	// CommanderFactory 为压测策略创建AttackStrategyCommander，每个测试计划创建一次
+	CommanderFactory func() AttackStrategyCommander
+
 	attackStrategyConverter struct {
-		convertDTOFunc map[string]convertAttackStrategyDTOFunc
+		decoders map[string]AttackStrategyDecoder
+		mu       sync.RWMutex
 	}
 
-	convertAttackStrategyDTOFunc func([]byte) (AttackStrategy, error)
-
 	fixedConcurrentUsersStrategyCommander struct {
 		ctx            context.Context
 		cancel         context.CancelFunc
@@ -58,7 +64,7 @@ type (
 		output         chan statistics.AttackResult
 		timer          Timer
 		task           Task
-		counter        uint32
+		lowestFree     uint32 // 可能空闲的最小编号
 		pool           map[uint32]*fcuExecutor
 		closed         uint32
 		inRampUpPeriod uint32
@@ -74,7 +80,10 @@ type (
 		mu     sync.RWMutex
 	}
 
-	commanderFactory struct{}
+	commanderFactory struct {
+		factories map[string]CommanderFactory
+		mu        sync.RWMutex
+	}
 )
 
 var (
@@ -82,8 +91,36 @@ var (
 	_ AttackStrategyCommander = (*fixedConcurrentUsersStrategyCommander)(nil)
 )
 
+var (
+	// ErrAlreadyRegistered 重复注册压测策略、延时器
+	ErrAlreadyRegistered = errors.New("already registered")
+	// ErrNotRegistered 压测策略、延时器未注册
+	ErrNotRegistered = errors.New("not registered")
+)
+
 var defaultAttackStrategyConverter *attackStrategyConverter
-var defaultCommanderFactory = commanderFactory{}
+var defaultCommanderFactory *commanderFactory
+
+// RegisterAttackStrategy 注册自定义的压测策略，master与slave都需要注册
+func RegisterAttackStrategy(name string, decoder AttackStrategyDecoder, factory CommanderFactory) error {
+	if name == "" || decoder == nil || factory == nil {
+		return errors.New("invalid attack strategy registration")
+	}
+
+	defaultAttackStrategyConverter.mu.Lock()
+	defer defaultAttackStrategyConverter.mu.Unlock()
+	defaultCommanderFactory.mu.Lock()
+	defer defaultCommanderFactory.mu.Unlock()
+
+	_, decoded := defaultAttackStrategyConverter.decoders[name]
+	_, built := defaultCommanderFactory.factories[name]
+	if decoded || built {
+		return fmt.Errorf("attack strategy %s: %w", name, ErrAlreadyRegistered)
+	}
+	defaultAttackStrategyConverter.decoders[name] = decoder
+	defaultCommanderFactory.factories[name] = factory
+	return nil
+}
 
 func (fc *FixedConcurrentUsers) spawn(current, expected, period, interval int) []*RampUpStep {
 	var ret []*RampUpStep
@@ -163,13 +200,18 @@ func (fx *FixedConcurrentUsers) Split(n int) []AttackStrategy {
 	return ret
 }
 
+// Load 并发用户数
+func (fx *FixedConcurrentUsers) Load() int {
+	return fx.ConcurrentUsers
+}
+
 func (fx *FixedConcurrentUsers) Name() string {
 	return "fixed-concurrent-users"
 }
 
 func newAttackStrategyConverter() *attackStrategyConverter {
 	return &attackStrategyConverter{
-		convertDTOFunc: map[string]convertAttackStrategyDTOFunc{
+		decoders: map[string]AttackStrategyDecoder{
 			"fixed-concurrent-users": func(data []byte) (AttackStrategy, error) {
 				as := new(FixedConcurrentUsers)
 				err := json.Unmarshal(data, as)
@@ -179,10 +221,19 @@ func newAttackStrategyConverter() *attackStrategyConverter {
 	}
 }
 
+func (c *attackStrategyConverter) registered(name string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.decoders[name]
+	return ok
+}
+
 func (c *attackStrategyConverter) convertDTO(dto *genproto.AttackStrategyDTO) (AttackStrategy, error) {
-	fn, ok := c.convertDTOFunc[dto.Type]
+	c.mu.RLock()
+	fn, ok := c.decoders[dto.Type]
+	c.mu.RUnlock()
 	if !ok {
-		return nil, errors.New("cannot found convertion function")
+		return nil, fmt.Errorf("attack strategy %s: %w", dto.Type, ErrNotRegistered)
 	}
 	return fn(dto.AttackStrategy)
 }
@@ -203,10 +254,43 @@ func newFixedConcurrentUsersStrategyCommander() *fixedConcurrentUsersStrategyCom
 	}
 }
 
-func (commander *fixedConcurrentUsersStrategyCommander) clearDeadExector(id uint32) {
+func (commander *fixedConcurrentUsersStrategyCommander) clearDeadExector(exe *fcuExecutor) {
 	commander.mu.Lock()
 	defer commander.mu.Unlock()
+	if commander.pool[exe.id] == exe { // 编号可能已经分配给新的executor
+		commander.release(exe.id)
+	}
+}
+
+// allocate 分配最小的空闲编号，使存活的虚拟用户编号尽量连续，调用方需持有锁
+func (commander *fixedConcurrentUsersStrategyCommander) allocate() uint32 {
+	for {
+		if _, ok := commander.pool[commander.lowestFree]; !ok {
+			return commander.lowestFree
+		}
+		commander.lowestFree++
+	}
+}
+
+// release 调用方需持有锁
+func (commander *fixedConcurrentUsersStrategyCommander) release(id uint32) {
 	delete(commander.pool, id)
+	if id < commander.lowestFree {
+		commander.lowestFree = id
+	}
+}
+
+// newest 编号最大的n个executor，调用方需持有锁
+func (commander *fixedConcurrentUsersStrategyCommander) newest(n int) []*fcuExecutor {
+	ret := make([]*fcuExecutor, 0, len(commander.pool))
+	for _, e := range commander.pool {
+		ret = append(ret, e)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].id > ret[j].id })
+	if n < len(ret) {
+		ret = ret[:n]
+	}
+	return ret
 }
 
 func (commander *fixedConcurrentUsersStrategyCommander) Open(ctx context.Context, task Task) <-chan statistics.AttackResult {
@@ -246,17 +330,10 @@ func (commander *fixedConcurrentUsersStrategyCommander) Command(d AttackStrategy
 	for _, step := range rampUpSteps {
 		switch {
 		case step.N < 0: // 降压策略
-			d := 0
-
 			commander.mu.Lock()
-			for _, e := range commander.pool {
-				if d > step.N {
-					delete(commander.pool, e.id) // 主动清理
-					e.kill()
-					d--
-				} else {
-					break
-				}
+			for _, e := range commander.newest(-step.N) { // 优先退出编号最大的用户
+				commander.release(e.id) // 主动清理
+				e.kill()
 			}
 			commander.mu.Unlock()
 
@@ -272,9 +349,6 @@ func (commander *fixedConcurrentUsersStrategyCommander) Command(d AttackStrategy
 
 		case step.N > 0: // 增压策略
 			for i := 0; i < step.N; i++ {
-				id := atomic.AddUint32(&commander.counter, 1) - 1
-				executor := newFCUExecutor(id, commander, t)
-
 				select {
 				case <-commander.ctx.Done():
 					Logger.Warn("commander was canceled, break out the ramp-up period") // https://pkg.go.dev/sync#WaitGroup.Add
@@ -283,12 +357,13 @@ func (commander *fixedConcurrentUsersStrategyCommander) Command(d AttackStrategy
 					commander.wg.Add(1)
 
 					commander.mu.Lock()
-					commander.pool[id] = executor
+					executor := newFCUExecutor(commander.allocate(), commander, t)
+					commander.pool[executor.id] = executor
 					commander.mu.Unlock()
 
 					go func(exe *fcuExecutor) {
 						defer func() {
-							commander.clearDeadExector(exe.id)
+							commander.clearDeadExector(exe)
 							exe.kill() // 所有清理逻辑
 							commander.wg.Done()
 						}()
@@ -349,7 +424,22 @@ func (e *fcuExecutor) start(ctx context.Context, task Task, output chan<- statis
 	}
 
 	ctx, e.cancel = context.WithCancel(ctx)
-	ctx = newExecutorSharedContext(ctx)
+	done := ctx.Done()
+	ctx = withResultEmitter(ctx, func(ret statistics.AttackResult) {
+		select {
+		case output <- ret:
+		case <-done:
+		}
+	})
+	vu := &virtualUser{id: e.id}
+	if exec, ok := executionFrom(ctx); ok { // commander内的编号只在单个场景内唯一，全局编号使用slave级别的编号
+		slot := exec.slots.acquire()
+		defer exec.slots.release(slot)
+		vu.id = exec.globalUserID(slot)
+	}
+	ctx = withVirtualUser(ctx, vu)
+	shared := newExecutorSharedContext(ctx)
+	ctx = shared
 
 	defer func() {
 		if rec := recover(); rec != nil {
@@ -358,7 +448,18 @@ func (e *fcuExecutor) start(ctx context.Context, task Task, output chan<- statis
 		}
 	}()
 
-	for {
+	if hook, ok := task.(VirtualUserHook); ok {
+		if !e.startHook(ctx, hook, output) {
+			return
+		}
+		defer func() {
+			stopCtx, cancel := shared.detach(VirtualUserStopTimeout)
+			defer cancel()
+			hook.OnStop(stopCtx)
+		}()
+	}
+
+	for iteration := uint64(0); ; iteration++ {
 		select {
 		case <-ctx.Done():
 			// Logger.Warn("a executor is quit")
@@ -366,12 +467,18 @@ func (e *fcuExecutor) start(ctx context.Context, task Task, output chan<- statis
 		default:
 		}
 
+		atomic.StoreUint64(&vu.iteration, iteration)
 		start := time.Now()
-		attacker := task.PickUp()
-		err := attacker.Fire(ctx)
+		attacker := pickUp(ctx, task)
+		throttled, err := throttle(ctx, attacker.Name())
+		if err != nil {
+			return
+		}
+		fired := time.Now()
+		err = attacker.Fire(ctx)
 
 		select {
-		case output <- statistics.AttackResult{Name: attacker.Name(), Duration: time.Since(start), Error: err}:
+		case output <- statistics.AttackResult{Name: takeResultName(ctx, attacker.Name()), Duration: time.Since(fired), Error: err, Diagnostic: DiagnosticOf(err), Throttled: throttled, Trace: takeResultTrace(ctx)}:
 		case <-ctx.Done():
 			// Logger.Warn("a executor is quit")
 			return
@@ -380,14 +487,51 @@ func (e *fcuExecutor) start(ctx context.Context, task Task, output chan<- statis
 		e.mu.RLock()
 		t := e.timer
 		e.mu.RUnlock()
-		t.Sleep()
+		if err := sleepTimer(ctx, t, time.Since(start)); err != nil {
+			return
+		}
 	}
 }
 
-func (cf commanderFactory) build(ct string) AttackStrategyCommander {
-	return newFixedConcurrentUsersStrategyCommander()
+// startHook 调用OnStart直至成功，每次失败都记录结果，使阶段内的虚拟用户数量不会悄悄减少；ctx取消时返回false
+func (e *fcuExecutor) startHook(ctx context.Context, hook VirtualUserHook, output chan<- statistics.AttackResult) bool {
+	for {
+		start := time.Now()
+		err := hook.OnStart(ctx)
+		if err == nil {
+			return true
+		}
+		Logger.Error("failed to start virtual user", zap.Uint32("id", e.id), zap.Error(err))
+		select {
+		case output <- statistics.AttackResult{Name: VirtualUserStartName, Duration: time.Since(start), Error: err, Diagnostic: DiagnosticOf(err)}:
+		case <-ctx.Done():
+			return false
+		}
+		if sleepContext(ctx, VirtualUserRetryInterval) != nil {
+			return false
+		}
+	}
+}
+
+func newCommanderFactory() *commanderFactory {
+	return &commanderFactory{
+		factories: map[string]CommanderFactory{
+			"fixed-concurrent-users": func() AttackStrategyCommander { return newFixedConcurrentUsersStrategyCommander() },
+		},
+	}
+}
+
+func (cf *commanderFactory) build(name string) (AttackStrategyCommander, error) {
+	cf.mu.RLock()
+	factory, ok := cf.factories[name]
+	cf.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("commander of attack strategy %s: %w", name, ErrNotRegistered)
+	}
+	return factory(), nil
 }
 
 func init() {
 	defaultAttackStrategyConverter = newAttackStrategyConverter()
+	defaultCommanderFactory = newCommanderFactory()
 }
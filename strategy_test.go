@@ -2,12 +2,15 @@ package ultron
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/wosai/ultron/v2/pkg/genproto"
 	"github.com/wosai/ultron/v2/pkg/statistics"
 	"go.uber.org/zap"
 )
@@ -139,6 +142,194 @@ func TestFCUExecutor(t *testing.T) {
 	commander.Close()
 }
 
+type sessionAttacker struct {
+	mu      sync.Mutex
+	started int
+	stopped int
+	fired   int
+	bad     int
+}
+
+func (s *sessionAttacker) Name() string {
+	return "session"
+}
+
+func (s *sessionAttacker) OnStart(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.started++
+	StoreUserValue(ctx, "token", s.started)
+	return nil
+}
+
+func (s *sessionAttacker) Fire(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fired++
+	if _, ok := UserValue(ctx, "token"); !ok {
+		s.bad++
+	}
+	time.Sleep(time.Millisecond)
+	return nil
+}
+
+func (s *sessionAttacker) OnStop(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ctx.Err() != nil {
+		s.bad++
+	}
+	if _, ok := UserValue(ctx, "token"); !ok {
+		s.bad++
+	}
+	s.stopped++
+}
+
+func TestFCUExecutor_VirtualUserHook(t *testing.T) {
+	commander := newFixedConcurrentUsersStrategyCommander()
+	attacker := &sessionAttacker{}
+	task := NewTask()
+	task.Add(attacker, 1)
+
+	output := commander.Open(context.Background(), task)
+	go func() {
+		for range output {
+		}
+	}()
+
+	commander.Command(&FixedConcurrentUsers{ConcurrentUsers: 5}, NonstopTimer{})
+	<-time.After(200 * time.Millisecond)
+	commander.Command(&FixedConcurrentUsers{ConcurrentUsers: 2}, NonstopTimer{})
+	<-time.After(200 * time.Millisecond)
+	commander.Close()
+
+	attacker.mu.Lock()
+	defer attacker.mu.Unlock()
+	assert.EqualValues(t, 5, attacker.started)
+	assert.EqualValues(t, 5, attacker.stopped)
+	assert.Greater(t, attacker.fired, 0)
+	assert.EqualValues(t, 0, attacker.bad)
+}
+
+func TestFCUExecutor_VirtualUserHookFailed(t *testing.T) {
+	var mu sync.Mutex
+	var starts int
+	task := NewTask()
+	task.Add(&sessionAttacker{}, 1)
+	task.Hook(VirtualUserHookFuncs{Start: func(context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+		starts++
+		if starts == 1 {
+			return errors.New("login failed")
+		}
+		return nil
+	}})
+
+	commander := newFixedConcurrentUsersStrategyCommander()
+	output := commander.Open(context.Background(), task)
+	results := make(map[string]int)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for ret := range output {
+			if ret.Name == VirtualUserStartName {
+				assert.EqualError(t, ret.Error, "login failed")
+			}
+			results[ret.Name]++
+		}
+	}()
+
+	commander.Command(&FixedConcurrentUsers{ConcurrentUsers: 1}, NonstopTimer{})
+	<-time.After(VirtualUserRetryInterval + 200*time.Millisecond)
+	commander.Close()
+	<-done
+
+	assert.EqualValues(t, 1, results[VirtualUserStartName])
+	assert.Greater(t, results["session"], 0) // 重试成功后继续压测
+}
+
+type executionAttacker struct {
+	mu         sync.Mutex
+	users      map[uint32]uint64 // 用户编号 -> 最大的iteration
+	stages     map[int]struct{}
+	plan       string
+	withoutIDs int
+}
+
+func (ea *executionAttacker) Name() string {
+	return "execution"
+}
+
+func (ea *executionAttacker) Fire(ctx context.Context) error {
+	id, ok := VirtualUserID(ctx)
+	iteration, _ := Iteration(ctx)
+	stage, _ := StageIndex(ctx)
+	plan, _ := PlanName(ctx)
+
+	ea.mu.Lock()
+	defer ea.mu.Unlock()
+	if !ok {
+		ea.withoutIDs++
+	}
+	if iteration >= ea.users[id] {
+		ea.users[id] = iteration
+	}
+	ea.stages[stage] = struct{}{}
+	ea.plan = plan
+	time.Sleep(time.Millisecond)
+	return nil
+}
+
+func TestFCUExecutor_Execution(t *testing.T) {
+	commander := newFixedConcurrentUsersStrategyCommander()
+	attacker := &executionAttacker{users: make(map[uint32]uint64), stages: make(map[int]struct{})}
+	task := NewTask()
+	task.Add(attacker, 1)
+
+	exec := newExecution("plan", "slave")
+	exec.update(&genproto.ExecutionDTO{StageIndex: 0, SlaveIndex: 1, SlaveCount: 2})
+	output := commander.Open(withExecution(context.Background(), exec), task)
+	go func() {
+		for range output {
+		}
+	}()
+
+	commander.Command(&FixedConcurrentUsers{ConcurrentUsers: 4}, NonstopTimer{})
+	<-time.After(100 * time.Millisecond)
+
+	exec.update(&genproto.ExecutionDTO{StageIndex: 1, SlaveIndex: 1, SlaveCount: 2})
+	commander.Command(&FixedConcurrentUsers{ConcurrentUsers: 2}, NonstopTimer{})
+	<-time.After(100 * time.Millisecond)
+	commander.mu.Lock()
+	ids := make([]uint32, 0)
+	for id := range commander.pool {
+		ids = append(ids, id)
+	}
+	commander.mu.Unlock()
+	assert.ElementsMatch(t, []uint32{0, 1}, ids) // 优先退出编号最大的用户
+
+	commander.Command(&FixedConcurrentUsers{ConcurrentUsers: 3}, NonstopTimer{})
+	<-time.After(100 * time.Millisecond)
+	commander.mu.Lock()
+	_, reused := commander.pool[2]
+	commander.mu.Unlock()
+	assert.True(t, reused) // 复用空闲的最小编号
+	commander.Close()
+
+	attacker.mu.Lock()
+	defer attacker.mu.Unlock()
+	assert.EqualValues(t, 0, attacker.withoutIDs)
+	assert.EqualValues(t, "plan", attacker.plan)
+	assert.Contains(t, attacker.stages, 0)
+	assert.Contains(t, attacker.stages, 1)
+	for id, iteration := range attacker.users {
+		assert.EqualValues(t, 1, id%2)
+		assert.Less(t, id, uint32(8))
+		assert.Greater(t, iteration, uint64(0))
+	}
+}
+
 func TestFCUSBenchmark(t *testing.T) {
 	commander := newFixedConcurrentUsersStrategyCommander()
 	task := NewTask()
@@ -163,3 +354,93 @@ func TestFCUSBenchmark(t *testing.T) {
 	report := sg.Report(true) // tps理论最大值10000, 1.6.0该配置均值在8000左右
 	Logger.Info("report", zap.Float64("tps", report.TotalTPS), zap.Time("first_attack", report.FirstAttack), zap.Time("last_attack", report.LastAttack))
 }
+
+type customStrategy struct {
+	FixedConcurrentUsers
+}
+
+func (cs *customStrategy) Name() string {
+	return "custom-strategy"
+}
+
+func TestRegisterAttackStrategy(t *testing.T) {
+	plan := NewPlan("custom")
+	plan.AddStages(BuildStage().WithAttackStrategy(&customStrategy{FixedConcurrentUsers{ConcurrentUsers: 10}}))
+	assert.ErrorIs(t, plan.validate(), ErrNotRegistered)
+
+	_, err := defaultCommanderFactory.build("custom-strategy")
+	assert.ErrorIs(t, err, ErrNotRegistered)
+	_, err = defaultAttackStrategyConverter.convertDTO(&genproto.AttackStrategyDTO{Type: "custom-strategy"})
+	assert.ErrorIs(t, err, ErrNotRegistered)
+
+	decoder := func(data []byte) (AttackStrategy, error) {
+		cs := new(customStrategy)
+		err := json.Unmarshal(data, cs)
+		return cs, err
+	}
+	factory := func() AttackStrategyCommander { return newFixedConcurrentUsersStrategyCommander() }
+	assert.Nil(t, RegisterAttackStrategy("custom-strategy", decoder, factory))
+	assert.ErrorIs(t, RegisterAttackStrategy("custom-strategy", decoder, factory), ErrAlreadyRegistered)
+	assert.ErrorIs(t, RegisterAttackStrategy("fixed-concurrent-users", decoder, factory), ErrAlreadyRegistered)
+	assert.Error(t, RegisterAttackStrategy("", decoder, factory))
+	assert.Error(t, RegisterAttackStrategy("another", decoder, nil))
+
+	assert.Nil(t, plan.validate())
+	commander, err := defaultCommanderFactory.build("custom-strategy")
+	assert.Nil(t, err)
+	assert.NotNil(t, commander)
+
+	dto, err := defaultAttackStrategyConverter.convertAttackStrategy(&customStrategy{FixedConcurrentUsers{ConcurrentUsers: 10}})
+	assert.Nil(t, err)
+	as, err := defaultAttackStrategyConverter.convertDTO(dto)
+	assert.Nil(t, err)
+	assert.EqualValues(t, &customStrategy{FixedConcurrentUsers{ConcurrentUsers: 10}}, as)
+
+	// slave为每个场景只创建一次commander，不同阶段不能切换压测策略的类型
+	mixed := NewPlan("mixed")
+	mixed.AddStages(
+		BuildStage().WithAttackStrategy(&FixedConcurrentUsers{ConcurrentUsers: 10}).WithExitConditions(&UniversalExitConditions{Requests: 10}),
+		BuildStage().WithAttackStrategy(&customStrategy{FixedConcurrentUsers{ConcurrentUsers: 10}}),
+	)
+	assert.ErrorContains(t, mixed.validate(), "stage 1: cannot switch attack strategy from fixed-concurrent-users to custom-strategy")
+
+	scenarios := NewPlan("mixed-scenarios")
+	scenarios.AddStages(
+		BuildStage().WithAttackStrategy(&FixedConcurrentUsers{ConcurrentUsers: 10}).WithExitConditions(&UniversalExitConditions{Requests: 10}).
+			WithScenarios(&Scenario{Name: "pay", Strategy: &FixedConcurrentUsers{ConcurrentUsers: 1}}),
+		BuildStage().WithAttackStrategy(&FixedConcurrentUsers{ConcurrentUsers: 10}).
+			WithScenarios(&Scenario{Name: "pay", Strategy: &customStrategy{FixedConcurrentUsers{ConcurrentUsers: 1}}}),
+	)
+	assert.ErrorContains(t, scenarios.validate(), "stage 1: scenario pay: cannot switch attack strategy")
+}
+
+func TestFCUExecutor_RateLimit(t *testing.T) {
+	commander := newFixedConcurrentUsersStrategyCommander()
+	task := NewTask()
+	task.Add(newBenchmarkAttacker("pay", 0), 1)
+
+	exec := newExecution("plan", "slave")
+	exec.setRateLimits([]*genproto.RateLimitDTO{{Rps: 50}})
+	output := commander.Open(withExecution(context.Background(), exec), task)
+
+	var requests int
+	var throttled time.Duration
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for ret := range output {
+			requests++
+			throttled += ret.Throttled
+			assert.Less(t, ret.Duration, 10*time.Millisecond) // 等待令牌的时长不计入Duration
+		}
+	}()
+
+	start := time.Now()
+	commander.Command(&FixedConcurrentUsers{ConcurrentUsers: 10}, NonstopTimer{})
+	<-time.After(500 * time.Millisecond)
+	commander.Close()
+	<-done
+
+	assert.LessOrEqual(t, float64(requests), time.Since(start).Seconds()*50+1)
+	assert.Greater(t, throttled, time.Second) // 10个用户争抢每秒50个令牌
+}
@@ -0,0 +1,152 @@
+package ultron
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/wosai/ultron/v2/pkg/statistics"
+	"go.uber.org/zap"
+)
+
+type (
+	// streamHub 将聚合报告、测试计划事件推送给所有订阅的客户端
+	streamHub struct {
+		clients    map[*streamClient]struct{}
+		bufferSize int
+		heartbeat  time.Duration
+		mu         sync.RWMutex
+	}
+
+	// streamClient 每个客户端独立的缓冲区，缓冲区满时丢弃最旧的消息，慢客户端不会阻塞事件总线
+	streamClient struct {
+		messages chan streamMessage
+		dropped  uint64
+	}
+
+	streamMessage struct {
+		event string
+		data  []byte
+	}
+)
+
+const (
+	streamEventReport = "report"
+	streamEventPlan   = "plan"
+)
+
+func newStreamHub(bufferSize int) *streamHub {
+	if bufferSize <= 0 {
+		bufferSize = 1
+	}
+	return &streamHub{
+		clients:    make(map[*streamClient]struct{}),
+		bufferSize: bufferSize,
+		heartbeat:  15 * time.Second,
+	}
+}
+
+func (hub *streamHub) register() *streamClient {
+	client := &streamClient{messages: make(chan streamMessage, hub.bufferSize)}
+	hub.mu.Lock()
+	hub.clients[client] = struct{}{}
+	hub.mu.Unlock()
+	return client
+}
+
+func (hub *streamHub) unregister(client *streamClient) {
+	hub.mu.Lock()
+	delete(hub.clients, client)
+	hub.mu.Unlock()
+}
+
+func (hub *streamHub) broadcast(event string, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		Logger.Error("failed to marshal stream message", zap.String("event", event), zap.Error(err))
+		return
+	}
+	msg := streamMessage{event: event, data: data}
+
+	hub.mu.RLock()
+	defer hub.mu.RUnlock()
+	for client := range hub.clients {
+		client.push(msg)
+	}
+}
+
+// push 非阻塞写入，缓冲区满时丢弃最旧的一条消息
+func (c *streamClient) push(msg streamMessage) {
+	for {
+		select {
+		case c.messages <- msg:
+			return
+		default:
+		}
+
+		select {
+		case <-c.messages:
+			atomic.AddUint64(&c.dropped, 1)
+		default:
+		}
+	}
+}
+
+func (hub *streamHub) handleReport() ReportHandleFunc {
+	return func(_ context.Context, report statistics.SummaryReport) {
+		hub.broadcast(streamEventReport, report)
+	}
+}
+
+func (hub *streamHub) handlePlanEvent() PlanEventHandleFunc {
+	return func(_ context.Context, event PlanEvent) {
+		hub.broadcast(streamEventPlan, event)
+	}
+}
+
+// serveSSE 以Server-Sent Events的形式推送消息
+func (hub *streamHub) serveSSE() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		flusher, ok := rw.(http.Flusher)
+		if !ok {
+			renderResponse(errors.New("streaming is not supported"), rw, r)
+			return
+		}
+
+		client := hub.register()
+		defer hub.unregister(client)
+
+		rw.Header().Set("Content-Type", "text/event-stream")
+		rw.Header().Set("Cache-Control", "no-cache")
+		rw.Header().Set("Connection", "keep-alive")
+		rw.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		ticker := time.NewTicker(hub.heartbeat)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+
+			case <-ticker.C:
+				if _, err := fmt.Fprint(rw, ": heartbeat\n\n"); err != nil {
+					return
+				}
+
+			case msg := <-client.messages:
+				if _, err := fmt.Fprintf(rw, "event: %s\ndata: %s\n\n", msg.event, msg.data); err != nil {
+					Logger.Warn("failed to push message to stream client", zap.Error(err))
+					return
+				}
+			}
+			flusher.Flush()
+		}
+	}
+}
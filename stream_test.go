@@ -0,0 +1,61 @@
+package ultron
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wosai/ultron/v2/pkg/statistics"
+)
+
+func TestStreamClient_Push(t *testing.T) {
+	hub := newStreamHub(2)
+	client := hub.register()
+	defer hub.unregister(client)
+
+	for i := 0; i < 5; i++ {
+		hub.handlePlanEvent()(context.TODO(), PlanEvent{Type: EventStageStarted, Stage: i})
+	}
+	assert.EqualValues(t, len(client.messages), 2)
+	assert.EqualValues(t, client.dropped, 3)
+
+	msg := <-client.messages
+	assert.EqualValues(t, msg.event, streamEventPlan)
+	assert.Contains(t, string(msg.data), `"stage":3`)
+}
+
+func TestStreamHub_ServeSSE(t *testing.T) {
+	hub := newStreamHub(8)
+	ts := httptest.NewServer(hub.serveSSE())
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL)
+	assert.Nil(t, err)
+	defer res.Body.Close()
+	assert.EqualValues(t, res.Header.Get("Content-Type"), "text/event-stream")
+
+	for {
+		hub.mu.RLock()
+		n := len(hub.clients)
+		hub.mu.RUnlock()
+		if n == 1 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	hub.handleReport()(context.TODO(), statistics.SummaryReport{TotalRequests: 100})
+
+	reader := bufio.NewReader(res.Body)
+	line, err := reader.ReadString('\n')
+	assert.Nil(t, err)
+	assert.EqualValues(t, line, "event: report\n")
+	line, err = reader.ReadString('\n')
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(line, "data: "))
+	assert.Contains(t, line, `"total_requests":100`)
+}
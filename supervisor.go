@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -23,6 +24,7 @@ type (
 		toleranceForDelay uint32 // 容忍延后的批次
 		slaveAgents       map[string]*slaveAgent
 		buffer            map[uint32]map[string]*statsCallback
+		events            planEventBus
 		mu                sync.RWMutex
 	}
 
@@ -74,6 +76,7 @@ func newSlaveSupervisor() *slaveSupervisor {
 		slaveAgents:       make(map[string]*slaveAgent),
 		buffer:            make(map[uint32]map[string]*statsCallback),
 		toleranceForDelay: 3,
+		events:            defaultEventBus,
 	}
 }
 
@@ -83,9 +86,11 @@ func (sup *slaveSupervisor) Subscribe(req *genproto.SubscribeRequest, stream gen
 		Logger.Error("cannot subscribe to ultron server", zap.String("slave_id", agent.ID()), zap.Error(err))
 	}
 	Logger.Info("a new slave is subscribing to ultron server", zap.String("slave_id", agent.ID()), zap.Any("extras", agent.extras))
+	sup.events.publishPlanEvent(PlanEvent{Type: EventSlaveJoined, SlaveID: agent.ID()})
 
 	defer func() {
 		sup.Remove(agent.ID())
+		sup.events.publishPlanEvent(PlanEvent{Type: EventSlaveLeft, SlaveID: agent.ID()})
 		if err := agent.close(); err != nil {
 			Logger.Error("failed to close slave agent", zap.String("slave_id", agent.ID()), zap.Error(err))
 		}
@@ -222,7 +227,11 @@ func (sup *slaveSupervisor) Aggregate(fullHistory bool, tags ...statistics.Tag)
 	sup.mu.Unlock()
 
 	// 检查是否完成
-	sg := statistics.NewStatisticianGroup()
+	sg := statistics.NewStatisticianGroup(
+		statistics.WithMaxFailureKeys(loadedOption.Statistics.MaxFailureKeys),
+		statistics.WithFailureSamples(loadedOption.Statistics.FailureSamples),
+		statistics.WithDiagnosticSamples(loadedOption.Statistics.DiagnosticSamples),
+	)
 	for _, tag := range tags {
 		sg.Attach(tag)
 	}
@@ -311,10 +320,17 @@ func (sup *slaveSupervisor) StartNewPlan(ctx context.Context, name string) error
 	})
 }
 
-func (sup *slaveSupervisor) NextStage(ctx context.Context, strategy AttackStrategy, t Timer) error {
+// NextStage 按slave ID排序后切分并下发，使各个slave的序号在集群规模不变时保持稳定；
+// 命名场景按默认场景实际下发的slave数量切分，请求速率上限按各个slave的负载比例切分，断言不切分
+func (sup *slaveSupervisor) NextStage(ctx context.Context, index int, stage Stage, limit *RateLimit, assertions map[string][]*HTTPAssertion) error {
+	strategy, t, weights := stage.GetStrategy(), stage.GetTimer(), stageWeights(stage)
 	if t == nil {
 		t = NonstopTimer{}
 	}
+	asserts, err := convertAssertions(assertions)
+	if err != nil {
+		return err
+	}
 
 	sup.mu.RLock()
 	slaves := make([]*slaveAgent, len(sup.slaveAgents))
@@ -324,15 +340,37 @@ func (sup *slaveSupervisor) NextStage(ctx context.Context, strategy AttackStrate
 		i++
 	}
 	sup.mu.RUnlock()
+	sort.Slice(slaves, func(i, j int) bool { return slaves[i].ID() < slaves[j].ID() })
 
 	strategies := strategy.Split(len(slaves)) // 数量可能少于 len(slaves)
+	scenarios := stageScenarios(stage)
+	parts := make([][]AttackStrategy, len(scenarios))
+	for j, sc := range scenarios {
+		parts[j] = sc.Strategy.Split(len(strategies))
+	}
+	loads := strategyLoads(strategies, parts)
+	if loads == nil {
+		loads = make([]int, len(strategies))
+	}
+	limits := limit.split(loads)
+
 	eg, _ := errgroup.WithContext(ctx)
 	for i, strategy := range strategies {
 		i := i
 		strategy := strategy
 		eg.Go(func() error {
 			var err error
-			event := &genproto.SubscribeResponse{Type: genproto.EventType_NEXT_STAGE_STARTED}
+			event := &genproto.SubscribeResponse{
+				Type: genproto.EventType_NEXT_STAGE_STARTED,
+				Execution: &genproto.ExecutionDTO{
+					StageIndex: int32(index),
+					SlaveIndex: uint32(i),
+					SlaveCount: uint32(len(strategies)), // 只有分到压测策略的slave参与切分，避免部分数据无人读取
+				},
+				Weights:    weights,
+				RateLimits: limits[i],
+				Assertions: asserts,
+			}
 			event.Timer, err = defaultTimerConverter.convertTimer(t)
 			if err != nil {
 				return err
@@ -342,6 +380,16 @@ func (sup *slaveSupervisor) NextStage(ctx context.Context, strategy AttackStrate
 				return err
 			}
 			event.Data = &genproto.SubscribeResponse_AttackStrategy{AttackStrategy: as}
+			for j, sc := range scenarios {
+				if i >= len(parts[j]) {
+					continue
+				}
+				dto, err := convertScenario(sc, parts[j][i])
+				if err != nil {
+					return err
+				}
+				event.Scenarios = append(event.Scenarios, dto)
+			}
 			return slaves[i].send(event)
 		})
 	}
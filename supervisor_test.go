@@ -185,3 +185,60 @@ func TestSlaverSupervisor_Aggregate_FuzzTesting(t *testing.T) {
 	assert.Greater(t, submitted, uint32(0))
 	assert.EqualValues(t, submitted+canceled, 10)
 }
+
+func TestSlaveSupervisor_NextStage(t *testing.T) {
+	supervisor := newSlaveSupervisor()
+	agents := make(map[string]*slaveAgent)
+	for _, id := range []string{"c", "a", "b"} {
+		agents[id] = newSlaveAgent(&genproto.SubscribeRequest{SlaveId: id})
+		supervisor.Add(agents[id])
+	}
+
+	err := supervisor.NextStage(context.Background(), 2, BuildStage().WithAttackStrategy(&FixedConcurrentUsers{ConcurrentUsers: 10}).WithWeights(map[string]uint32{"a": 1}), nil, nil)
+	assert.Nil(t, err)
+
+	for index, id := range []string{"a", "b", "c"} {
+		event := <-agents[id].input
+		assert.EqualValues(t, genproto.EventType_NEXT_STAGE_STARTED, event.GetType())
+		assert.EqualValues(t, 2, event.GetExecution().GetStageIndex())
+		assert.EqualValues(t, index, event.GetExecution().GetSlaveIndex())
+		assert.EqualValues(t, 3, event.GetExecution().GetSlaveCount())
+		assert.EqualValues(t, map[string]uint32{"a": 1}, event.GetWeights())
+	}
+
+	stage := BuildStage().WithAttackStrategy(&FixedConcurrentUsers{ConcurrentUsers: 3}).WithScenarios(
+		&Scenario{Name: "checkout", Strategy: &FixedConcurrentUsers{ConcurrentUsers: 7}, Weights: map[string]uint32{"pay": 1}},
+	)
+	assert.Nil(t, supervisor.NextStage(context.Background(), 3, stage, nil, nil))
+	var users int
+	for _, id := range []string{"a", "b", "c"} {
+		event := <-agents[id].input
+		scenarios := event.GetScenarios()
+		assert.Len(t, scenarios, 1)
+		assert.EqualValues(t, "checkout", scenarios[0].GetName())
+		assert.EqualValues(t, map[string]uint32{"pay": 1}, scenarios[0].GetWeights())
+		sc, err := newScenarioFromDTO(scenarios[0])
+		assert.Nil(t, err)
+		users += sc.Strategy.(*FixedConcurrentUsers).ConcurrentUsers
+	}
+	assert.EqualValues(t, 7, users)
+
+	// 按负载比例切分请求速率上限：a、b、c分别分到4、3、3个用户
+	limit := &RateLimit{GlobalRPS: 100}
+	assert.Nil(t, supervisor.NextStage(context.Background(), 4, BuildStage().WithAttackStrategy(&FixedConcurrentUsers{ConcurrentUsers: 10}), limit, nil))
+	for id, rps := range map[string]float64{"a": 40, "b": 30, "c": 30} {
+		event := <-agents[id].input
+		assert.Len(t, event.GetRateLimits(), 1)
+		assert.InDelta(t, rps, event.GetRateLimits()[0].GetRps(), 1e-9)
+	}
+
+	// 断言不切分，每个slave都收到完整的断言
+	assertions := map[string][]*HTTPAssertion{"pay": {{Type: AssertJSON, Key: "$.code", Value: 0}}}
+	assert.Nil(t, supervisor.NextStage(context.Background(), 5, BuildStage().WithAttackStrategy(&FixedConcurrentUsers{ConcurrentUsers: 10}), nil, assertions))
+	for _, id := range []string{"a", "b", "c"} {
+		event := <-agents[id].input
+		assert.Len(t, event.GetAssertions(), 1)
+		assert.EqualValues(t, "pay", event.GetAssertions()[0].GetAttacker())
+		assert.EqualValues(t, "0", event.GetAssertions()[0].GetValue())
+	}
+}
@@ -1,8 +1,12 @@
 package ultron
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 type (
@@ -10,10 +14,17 @@ type (
 		attacker    []*swrrAttacker
 		totalWeight uint32
 		counts      uint32
-		preempted   []Attacker
+		picker      atomic.Value // *taskPicker，按阶段的权重覆盖重建
+		hooks       []VirtualUserHook
+		exclusive   bool // 为true时Reweight未列出的Attacker不执行
 		once        sync.Once
 	}
 
+	// taskPicker 按平滑加权轮询预先排好的Attacker序列
+	taskPicker struct {
+		preempted []Attacker
+	}
+
 	// swrrAttacker 平滑的加权请求
 	swrrAttacker struct {
 		attacker Attacker
@@ -25,6 +36,52 @@ type (
 		Add(Attacker, uint32)
 		PickUp() Attacker
 	}
+
+	// ReweightableTask Task的可选实现，按阶段的权重覆盖调整Attacker的比例
+	ReweightableTask interface {
+		Task
+		Reweight(weights map[string]uint32) error // weights为空时恢复默认权重
+	}
+
+	// ScenarioTask Task的可选实现，为每个命名场景复制出独立的Task
+	ScenarioTask interface {
+		Task
+		Fork() ReweightableTask // 共享Attacker以及钩子；Reweight时仅执行列出的Attacker，为空时执行全部Attacker
+	}
+
+	// ContextTask Task的可选实现，按虚拟用户选取Attacker，可以在虚拟用户级别的数据中保存选取的状态
+	ContextTask interface {
+		Task
+		PickUpContext(context.Context) Attacker
+	}
+
+	// VirtualUserHook 虚拟用户的生命周期钩子，Task、Attacker均可实现
+	VirtualUserHook interface {
+		OnStart(context.Context) error // 虚拟用户启动时调用，返回error时记录名为VirtualUserStartName的失败结果，间隔VirtualUserRetryInterval后重试
+		OnStop(context.Context)        // 虚拟用户退出时调用，context不会被取消，但有超时时间
+	}
+
+	// VirtualUserHookFuncs 以函数的形式实现VirtualUserHook
+	VirtualUserHookFuncs struct {
+		Start func(context.Context) error
+		Stop  func(context.Context)
+	}
+)
+
+const (
+	// VirtualUserStopTimeout 虚拟用户退出时，OnStop可使用的最长时间
+	VirtualUserStopTimeout = 30 * time.Second
+	// VirtualUserRetryInterval 虚拟用户OnStart失败后重试的间隔
+	VirtualUserRetryInterval = time.Second
+	// VirtualUserStartName OnStart失败时记录的结果名称
+	VirtualUserStartName = "virtual-user-start"
+)
+
+var (
+	_ ReweightableTask = (*task)(nil)
+	_ ScenarioTask     = (*task)(nil)
+	_ VirtualUserHook  = (*task)(nil)
+	_ VirtualUserHook  = VirtualUserHookFuncs{}
 )
 
 func NewTask() *task {
@@ -42,27 +99,159 @@ func (t *task) Add(a Attacker, weight uint32) {
 }
 
 // https://tenfy.cn/2018/11/12/smooth-weighted-round-robin/
-func (t *task) swrr() Attacker {
+func swrr(attackers []*swrrAttacker, totalWeight uint32) Attacker {
 	var best *swrrAttacker
 
-	for _, attacker := range t.attacker {
+	for _, attacker := range attackers {
 		attacker.current += int32(attacker.weight)
 		if best == nil || attacker.current > best.current {
 			best = attacker
 		}
 	}
 
-	best.current -= int32(t.totalWeight)
+	best.current -= int32(totalWeight)
 	return best.attacker
 }
 
+func newTaskPicker(attackers []*swrrAttacker) *taskPicker {
+	var totalWeight uint32
+	for _, a := range attackers {
+		totalWeight += a.weight
+	}
+	preempted := make([]Attacker, int(totalWeight))
+	for i := range preempted {
+		preempted[i] = swrr(attackers, totalWeight)
+	}
+	return &taskPicker{preempted: preempted}
+}
+
 func (t *task) PickUp() Attacker {
 	t.once.Do(func() {
-		t.preempted = make([]Attacker, int(t.totalWeight))
-		for i := 0; i < int(t.totalWeight); i++ {
-			t.preempted[i] = t.swrr()
+		if t.picker.Load() == nil {
+			t.picker.Store(newTaskPicker(t.attacker))
 		}
 	})
+	picker := t.picker.Load().(*taskPicker)
 	v := atomic.AddUint32(&t.counts, 1)
-	return t.preempted[(v-1)%t.totalWeight]
+	return picker.preempted[(v-1)%uint32(len(picker.preempted))]
+}
+
+// Reweight 按Attacker名称覆盖权重并原子地替换序列，不影响正在执行的虚拟用户
+func (t *task) Reweight(weights map[string]uint32) error {
+	known := make(map[string]struct{}, len(t.attacker))
+	attackers := make([]*swrrAttacker, 0, len(t.attacker))
+	for _, a := range t.attacker {
+		known[a.attacker.Name()] = struct{}{}
+		weight := a.weight
+		if w, ok := weights[a.attacker.Name()]; ok {
+			weight = w
+		} else if t.exclusive && len(weights) > 0 {
+			weight = 0
+		}
+		if weight > 0 {
+			attackers = append(attackers, &swrrAttacker{attacker: a.attacker, weight: weight})
+		}
+	}
+	for name := range weights {
+		if _, ok := known[name]; !ok {
+			return fmt.Errorf("cannot find attacker %s in task", name)
+		}
+	}
+	if len(attackers) == 0 {
+		return errors.New("at least one attacker should have positive weight")
+	}
+	t.picker.Store(newTaskPicker(attackers))
+	return nil
+}
+
+// Fork 复制出独立的Task，选取Attacker的序列与原Task互不影响
+func (t *task) Fork() ReweightableTask {
+	forked := &task{
+		attacker:    make([]*swrrAttacker, len(t.attacker)),
+		totalWeight: t.totalWeight,
+		hooks:       append([]VirtualUserHook(nil), t.hooks...),
+		exclusive:   true,
+	}
+	for i, a := range t.attacker {
+		forked.attacker[i] = &swrrAttacker{attacker: a.attacker, weight: a.weight}
+	}
+	return forked
+}
+
+// Hook 追加虚拟用户的生命周期钩子，实现了VirtualUserHook的Attacker无需再次添加
+func (t *task) Hook(hooks ...VirtualUserHook) {
+	for _, hook := range hooks {
+		if hook == nil {
+			panic("invalid VirtualUserHook")
+		}
+	}
+	t.hooks = append(t.hooks, hooks...)
+}
+
+// allHooks 依次为通过Hook添加的钩子、实现了VirtualUserHook的Attacker
+func (t *task) allHooks() []VirtualUserHook {
+	attackers := make([]Attacker, len(t.attacker))
+	for i, a := range t.attacker {
+		attackers[i] = a.attacker
+	}
+	return collectHooks(t.hooks, attackers)
+}
+
+// OnStart 依次调用钩子，任意钩子返回error时，对已经启动成功的钩子调用OnStop
+func (t *task) OnStart(ctx context.Context) error {
+	return startHooks(ctx, t.allHooks())
+}
+
+// OnStop 逆序调用钩子
+func (t *task) OnStop(ctx context.Context) {
+	stopHooks(ctx, t.allHooks())
+}
+
+func collectHooks(hooks []VirtualUserHook, attackers []Attacker) []VirtualUserHook {
+	ret := append([]VirtualUserHook(nil), hooks...)
+	for _, a := range attackers {
+		if hook, ok := a.(VirtualUserHook); ok {
+			ret = append(ret, hook)
+		}
+	}
+	return ret
+}
+
+func startHooks(ctx context.Context, hooks []VirtualUserHook) error {
+	for i, hook := range hooks {
+		if err := hook.OnStart(ctx); err != nil {
+			stopCtx, cancel := detachContext(ctx, VirtualUserStopTimeout) // OnStart可能因ctx取消而失败，回滚时不能再使用ctx
+			defer cancel()
+			stopHooks(stopCtx, hooks[:i])
+			return err
+		}
+	}
+	return nil
+}
+
+func stopHooks(ctx context.Context, hooks []VirtualUserHook) {
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hooks[i].OnStop(ctx)
+	}
+}
+
+// pickUp 优先按虚拟用户选取Attacker
+func pickUp(ctx context.Context, t Task) Attacker {
+	if ct, ok := t.(ContextTask); ok {
+		return ct.PickUpContext(ctx)
+	}
+	return t.PickUp()
+}
+
+func (h VirtualUserHookFuncs) OnStart(ctx context.Context) error {
+	if h.Start == nil {
+		return nil
+	}
+	return h.Start(ctx)
+}
+
+func (h VirtualUserHookFuncs) OnStop(ctx context.Context) {
+	if h.Stop != nil {
+		h.Stop(ctx)
+	}
 }
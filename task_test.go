@@ -1,6 +1,9 @@
 package ultron
 
 import (
+	"context"
+	"errors"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -14,7 +17,7 @@ func TestTask_PickUp(t *testing.T) {
 	task.PickUp()
 
 	actual := make(map[string]int)
-	for _, attacker := range task.preempted {
+	for _, attacker := range task.picker.Load().(*taskPicker).preempted {
 		actual[attacker.Name()]++
 	}
 	assert.EqualValues(t, actual["task-1"], 5)
@@ -35,6 +38,144 @@ func TestTask_PickUp2(t *testing.T) {
 	Logger.Info("attacker picked up", zap.Any("attackers", counter))
 }
 
+type hookedAttacker struct {
+	name  string
+	calls *[]string
+	err   error
+}
+
+func (h *hookedAttacker) Name() string {
+	return h.name
+}
+
+func (h *hookedAttacker) Fire(context.Context) error {
+	return nil
+}
+
+func (h *hookedAttacker) OnStart(context.Context) error {
+	*h.calls = append(*h.calls, "start "+h.name)
+	return h.err
+}
+
+func (h *hookedAttacker) OnStop(context.Context) {
+	*h.calls = append(*h.calls, "stop "+h.name)
+}
+
+func TestTask_Hooks(t *testing.T) {
+	var calls []string
+	task := NewTask()
+	task.Hook(VirtualUserHookFuncs{
+		Start: func(context.Context) error {
+			calls = append(calls, "start task")
+			return nil
+		},
+		Stop: func(context.Context) {
+			calls = append(calls, "stop task")
+		},
+	})
+	task.Add(&hookedAttacker{name: "a", calls: &calls}, 1)
+	task.Add(NewHTTPAttacker("b"), 1)
+
+	assert.NoError(t, task.OnStart(context.Background()))
+	task.OnStop(context.Background())
+	assert.EqualValues(t, []string{"start task", "start a", "stop a", "stop task"}, calls)
+
+	calls = nil
+	task.Add(&hookedAttacker{name: "c", calls: &calls, err: errors.New("login failed")}, 1)
+	assert.Error(t, task.OnStart(context.Background()))
+	assert.EqualValues(t, []string{"start task", "start a", "start c", "stop a", "stop task"}, calls)
+
+	assert.Panics(t, func() { task.Hook(nil) })
+	assert.NoError(t, VirtualUserHookFuncs{}.OnStart(context.Background()))
+
+	// 回滚时使用不会被取消的context
+	var rollback error
+	ctx, cancel := context.WithCancel(context.Background())
+	err := startHooks(ctx, []VirtualUserHook{
+		VirtualUserHookFuncs{Stop: func(ctx context.Context) { rollback = ctx.Err() }},
+		VirtualUserHookFuncs{Start: func(ctx context.Context) error {
+			cancel()
+			return ctx.Err()
+		}},
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, rollback)
+}
+
+func TestTask_Reweight(t *testing.T) {
+	task := NewTask()
+	task.Add(NewHTTPAttacker("read"), 9)
+	task.Add(NewHTTPAttacker("write"), 1)
+
+	count := func(n int) map[string]int {
+		counter := make(map[string]int)
+		for i := 0; i < n; i++ {
+			counter[task.PickUp().Name()]++
+		}
+		return counter
+	}
+	assert.EqualValues(t, map[string]int{"read": 90, "write": 10}, count(100))
+
+	assert.Nil(t, task.Reweight(map[string]uint32{"read": 1, "write": 3}))
+	assert.EqualValues(t, map[string]int{"read": 25, "write": 75}, count(100))
+
+	assert.Nil(t, task.Reweight(map[string]uint32{"read": 0}))
+	assert.EqualValues(t, map[string]int{"write": 100}, count(100))
+
+	assert.Error(t, task.Reweight(map[string]uint32{"unknown": 1}))
+	assert.Error(t, task.Reweight(map[string]uint32{"read": 0, "write": 0}))
+	assert.EqualValues(t, map[string]int{"write": 100}, count(100)) // 失败时保持原权重
+
+	assert.Nil(t, task.Reweight(nil))
+	assert.EqualValues(t, map[string]int{"read": 90, "write": 10}, count(100))
+}
+
+func TestTask_Fork(t *testing.T) {
+	task := NewTask()
+	task.Add(NewHTTPAttacker("read"), 9)
+	task.Add(NewHTTPAttacker("write"), 1)
+
+	forked := task.Fork()
+	count := func(t Task, n int) map[string]int {
+		counter := make(map[string]int)
+		for i := 0; i < n; i++ {
+			counter[t.PickUp().Name()]++
+		}
+		return counter
+	}
+	assert.EqualValues(t, map[string]int{"read": 90, "write": 10}, count(forked, 100))
+
+	// 仅执行列出的Attacker，不影响原Task
+	assert.Nil(t, forked.Reweight(map[string]uint32{"write": 1}))
+	assert.EqualValues(t, map[string]int{"write": 100}, count(forked, 100))
+	assert.EqualValues(t, map[string]int{"read": 90, "write": 10}, count(task, 100))
+
+	assert.Nil(t, forked.Reweight(nil))
+	assert.EqualValues(t, map[string]int{"read": 90, "write": 10}, count(forked, 100))
+	assert.Error(t, forked.Reweight(map[string]uint32{"unknown": 1}))
+}
+
+func TestTask_ReweightConcurrently(t *testing.T) {
+	task := NewTask()
+	task.Add(NewHTTPAttacker("read"), 5)
+	task.Add(NewHTTPAttacker("write"), 5)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10000; j++ {
+				assert.NotNil(t, task.PickUp())
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		assert.Nil(t, task.Reweight(map[string]uint32{"read": uint32(i%3 + 1)}))
+	}
+	wg.Wait()
+}
+
 func BenchmarkTest_PickUp(b *testing.B) {
 	task := NewTask()
 	task.Add(NewHTTPAttacker("task-1"), 5)
@@ -1,9 +1,12 @@
 package ultron
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/wosai/ultron/v2/pkg/genproto"
@@ -15,6 +18,12 @@ type (
 		Sleep()
 	}
 
+	// ContextTimer 可取消的延时器，executor优先调用SleepContext；elapsed为本次迭代已经消耗的时间
+	ContextTimer interface {
+		Timer
+		SleepContext(ctx context.Context, elapsed time.Duration) error
+	}
+
 	namedTimer interface {
 		Timer
 		Name() string
@@ -26,30 +35,86 @@ type (
 		MaxWait time.Duration `json:"max_wait,omitempty"`
 	}
 
-	// GaussianRandomTimer 高斯分布
+	// GaussianRandomTimer 高斯分布，序列化时只输出以毫秒表示的std_dev、desired_mean
 	GaussianRandomTimer struct {
-		StdDev      float64 `json:"std_dev"`      // 标准差
-		DesiredMean float64 `json:"desired_mean"` // 期望均值
+		StdDev         float64       `json:"std_dev"`      // 标准差，单位毫秒
+		DesiredMean    float64       `json:"desired_mean"` // 期望均值，单位毫秒
+		StdDevDuration time.Duration `json:"-"`            // 标准差，不能与StdDev同时设置
+		MeanDuration   time.Duration `json:"-"`            // 期望均值，不能与DesiredMean同时设置
+	}
+
+	// ConstantTimer 固定时长
+	ConstantTimer struct {
+		Wait time.Duration `json:"wait"`
+	}
+
+	// PacingTimer 固定每次迭代的总时长，等待时间为Pacing减去本次迭代已经消耗的时间
+	PacingTimer struct {
+		Pacing time.Duration `json:"pacing"`
+	}
+
+	// ExponentialRandomTimer 指数分布，模拟泊松到达的思考时间
+	ExponentialRandomTimer struct {
+		Mean time.Duration `json:"mean"`          // 期望均值
+		Max  time.Duration `json:"max,omitempty"` // 上限，为0时不限制
 	}
 
 	// NonstopTimer 不中断
 	NonstopTimer struct{}
 
+	// TimerDecoder 将master下发的配置解码为Timer
+	TimerDecoder func([]byte) (Timer, error)
+
 	timerConverter struct {
-		convertDTOFuncs map[string]convertTimerDTOFunc
+		decoders map[string]TimerDecoder
+		mu       sync.RWMutex
 	}
-
-	convertTimerDTOFunc func([]byte) (Timer, error)
 )
 
 var (
 	defaultTimerConverter *timerConverter
+
+	_ ContextTimer = (*UniformRandomTimer)(nil)
+	_ ContextTimer = (*GaussianRandomTimer)(nil)
+	_ ContextTimer = (*ConstantTimer)(nil)
+	_ ContextTimer = (*PacingTimer)(nil)
+	_ ContextTimer = (*ExponentialRandomTimer)(nil)
+	_ ContextTimer = NonstopTimer{}
 )
 
+// sleepContext 等待d，ctx取消时立即返回
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// sleepTimer 未实现ContextTimer的延时器无法被取消
+func sleepTimer(ctx context.Context, t Timer, elapsed time.Duration) error {
+	if ct, ok := t.(ContextTimer); ok {
+		return ct.SleepContext(ctx, elapsed)
+	}
+	t.Sleep()
+	return ctx.Err()
+}
+
 func (urt *UniformRandomTimer) Sleep() {
-	if urt.MaxWait > 0 {
-		time.Sleep(urt.MinWait + time.Duration(rand.Int63n(int64(urt.MaxWait-urt.MinWait)+1)))
+	urt.SleepContext(context.Background(), 0)
+}
+
+func (urt *UniformRandomTimer) SleepContext(ctx context.Context, _ time.Duration) error {
+	if urt.MaxWait <= 0 {
+		return ctx.Err()
 	}
+	return sleepContext(ctx, urt.MinWait+time.Duration(rand.Int63n(int64(urt.MaxWait-urt.MinWait)+1)))
 }
 
 func (urt *UniformRandomTimer) Name() string {
@@ -57,25 +122,105 @@ func (urt *UniformRandomTimer) Name() string {
 }
 
 func (grt *GaussianRandomTimer) Sleep() {
-	t := time.Duration(rand.NormFloat64()*grt.StdDev + grt.DesiredMean)
-	if t > 0 {
-		time.Sleep(t * time.Millisecond)
+	grt.SleepContext(context.Background(), 0)
+}
+
+func (grt *GaussianRandomTimer) SleepContext(ctx context.Context, _ time.Duration) error {
+	stddev, mean := grt.durations()
+	return sleepContext(ctx, time.Duration(rand.NormFloat64()*float64(stddev))+mean)
+}
+
+// check 同一参数不能同时以毫秒和time.Duration设置
+func (grt *GaussianRandomTimer) check() error {
+	if grt.StdDev != 0 && grt.StdDevDuration != 0 {
+		return errors.New("cannot set both StdDev and StdDevDuration")
+	}
+	if grt.DesiredMean != 0 && grt.MeanDuration != 0 {
+		return errors.New("cannot set both DesiredMean and MeanDuration")
+	}
+	return nil
+}
+
+// durations 以time.Duration表示的标准差、期望均值
+func (grt *GaussianRandomTimer) durations() (time.Duration, time.Duration) {
+	stddev, mean := grt.StdDevDuration, grt.MeanDuration
+	if stddev == 0 {
+		stddev = time.Duration(grt.StdDev * float64(time.Millisecond))
+	}
+	if mean == 0 {
+		mean = time.Duration(grt.DesiredMean * float64(time.Millisecond))
 	}
+	return stddev, mean
+}
+
+// MarshalJSON Duration字段转换为以毫秒表示的std_dev、desired_mean
+func (grt *GaussianRandomTimer) MarshalJSON() ([]byte, error) {
+	if err := grt.check(); err != nil {
+		return nil, err
+	}
+	stddev, mean := grt.durations()
+	type plain GaussianRandomTimer
+	return json.Marshal(plain{StdDev: float64(stddev) / float64(time.Millisecond), DesiredMean: float64(mean) / float64(time.Millisecond)})
 }
 
 func (grt *GaussianRandomTimer) Name() string {
 	return "gaussion-random-timer"
 }
 
+func (ct *ConstantTimer) Sleep() {
+	ct.SleepContext(context.Background(), 0)
+}
+
+func (ct *ConstantTimer) SleepContext(ctx context.Context, _ time.Duration) error {
+	return sleepContext(ctx, ct.Wait)
+}
+
+func (ct *ConstantTimer) Name() string {
+	return "constant-timer"
+}
+
+// Sleep 无法得知本次迭代的耗时，等待完整的Pacing
+func (pt *PacingTimer) Sleep() {
+	pt.SleepContext(context.Background(), 0)
+}
+
+func (pt *PacingTimer) SleepContext(ctx context.Context, elapsed time.Duration) error {
+	return sleepContext(ctx, pt.Pacing-elapsed)
+}
+
+func (pt *PacingTimer) Name() string {
+	return "pacing-timer"
+}
+
+func (ert *ExponentialRandomTimer) Sleep() {
+	ert.SleepContext(context.Background(), 0)
+}
+
+func (ert *ExponentialRandomTimer) SleepContext(ctx context.Context, _ time.Duration) error {
+	d := time.Duration(rand.ExpFloat64() * float64(ert.Mean))
+	if ert.Max > 0 && d > ert.Max {
+		d = ert.Max
+	}
+	return sleepContext(ctx, d)
+}
+
+func (ert *ExponentialRandomTimer) Name() string {
+	return "exponential-random-timer"
+}
+
 func (ns NonstopTimer) Sleep() {}
 
+func (ns NonstopTimer) SleepContext(ctx context.Context, _ time.Duration) error {
+	return ctx.Err()
+}
+
 func (ns NonstopTimer) Name() string {
 	return "non-stop-timer"
 }
 
 func newTimeConveter() *timerConverter {
 	return &timerConverter{
-		convertDTOFuncs: map[string]convertTimerDTOFunc{
+		decoders: map[string]TimerDecoder{
 			"non-stop-timer": func([]byte) (Timer, error) { return NonstopTimer{}, nil },
 			"gaussion-random-timer": func(data []byte) (Timer, error) {
 				t := new(GaussianRandomTimer)
@@ -87,14 +232,69 @@ func newTimeConveter() *timerConverter {
 				err := json.Unmarshal(data, t)
 				return t, err
 			},
+			"constant-timer": func(data []byte) (Timer, error) {
+				t := new(ConstantTimer)
+				err := json.Unmarshal(data, t)
+				return t, err
+			},
+			"pacing-timer": func(data []byte) (Timer, error) {
+				t := new(PacingTimer)
+				err := json.Unmarshal(data, t)
+				return t, err
+			},
+			"exponential-random-timer": func(data []byte) (Timer, error) {
+				t := new(ExponentialRandomTimer)
+				err := json.Unmarshal(data, t)
+				return t, err
+			},
 		},
 	}
 }
 
+// RegisterTimer 注册自定义的延时器，Timer需实现Name() string，master与slave都需要注册
+func RegisterTimer(name string, decoder TimerDecoder) error {
+	if name == "" || decoder == nil {
+		return errors.New("invalid timer registration")
+	}
+
+	defaultTimerConverter.mu.Lock()
+	defer defaultTimerConverter.mu.Unlock()
+	if _, ok := defaultTimerConverter.decoders[name]; ok {
+		return fmt.Errorf("timer %s: %w", name, ErrAlreadyRegistered)
+	}
+	defaultTimerConverter.decoders[name] = decoder
+	return nil
+}
+
+// check 检查延时器是否可以下发给slave，nil视为NonstopTimer
+func (tc *timerConverter) check(t Timer) error {
+	if t == nil {
+		return nil
+	}
+	nt, ok := t.(namedTimer)
+	if !ok {
+		return fmt.Errorf("timer %T must implement Name() string", t)
+	}
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+	if _, ok := tc.decoders[nt.Name()]; !ok {
+		return fmt.Errorf("timer %s: %w", nt.Name(), ErrNotRegistered)
+	}
+	switch v := t.(type) {
+	case *GaussianRandomTimer:
+		if err := v.check(); err != nil {
+			return fmt.Errorf("timer %s: %w", nt.Name(), err)
+		}
+	}
+	return nil
+}
+
 func (tc *timerConverter) convertDTO(dto *genproto.TimerDTO) (Timer, error) {
-	fn, ok := tc.convertDTOFuncs[dto.Type]
+	tc.mu.RLock()
+	fn, ok := tc.decoders[dto.Type]
+	tc.mu.RUnlock()
 	if !ok {
-		return nil, errors.New("cannot find convert func")
+		return nil, fmt.Errorf("timer %s: %w", dto.Type, ErrNotRegistered)
 	}
 	return fn(dto.Timer)
 }
@@ -1,10 +1,13 @@
 package ultron
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/wosai/ultron/v2/pkg/genproto"
 )
 
 func TestTimerConverterUniformRandomTimer(t *testing.T) {
@@ -36,3 +39,104 @@ func TestTimerConverterNonStopTimer(t *testing.T) {
 	assert.Nil(t, err)
 	assert.EqualValues(t, t1, t2)
 }
+
+func TestGaussianRandomTimer_Durations(t *testing.T) {
+	timer := new(GaussianRandomTimer)
+	assert.Nil(t, json.Unmarshal([]byte(`{"std_dev":15.5,"desired_mean":100}`), timer))
+	stddev, mean := timer.durations()
+	assert.EqualValues(t, 15500*time.Microsecond, stddev)
+	assert.EqualValues(t, 100*time.Millisecond, mean)
+
+	// 同一参数不能同时以毫秒和time.Duration设置
+	conflict := &GaussianRandomTimer{StdDev: 15, StdDevDuration: time.Second}
+	assert.Error(t, newTimeConveter().check(conflict))
+	_, err := json.Marshal(conflict)
+	assert.Error(t, err)
+
+	// 序列化时只输出毫秒
+	data, err := json.Marshal(&GaussianRandomTimer{StdDevDuration: 1500 * time.Microsecond, DesiredMean: 2000})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"std_dev":1.5,"desired_mean":2000}`, string(data))
+	timer = new(GaussianRandomTimer)
+	assert.Nil(t, json.Unmarshal([]byte(`{"std_dev":1.5,"mean":500}`), timer)) // 未知字段被忽略
+	stddev, mean = timer.durations()
+	assert.EqualValues(t, 1500*time.Microsecond, stddev)
+	assert.EqualValues(t, 0, mean)
+}
+
+func TestTimerConverter_NewTimers(t *testing.T) {
+	converter := newTimeConveter()
+	for _, t1 := range []Timer{
+		&ConstantTimer{Wait: time.Second},
+		&PacingTimer{Pacing: 2 * time.Second},
+		&ExponentialRandomTimer{Mean: time.Second, Max: 5 * time.Second},
+	} {
+		dto, err := converter.convertTimer(t1)
+		assert.Nil(t, err)
+		t2, err := converter.convertDTO(dto)
+		assert.Nil(t, err)
+		assert.EqualValues(t, t1, t2)
+	}
+}
+
+func TestTimer_SleepContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := sleepTimer(ctx, &UniformRandomTimer{MinWait: time.Minute, MaxWait: 2 * time.Minute}, 0)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+
+	start = time.Now()
+	assert.Nil(t, sleepTimer(context.Background(), &PacingTimer{Pacing: 100 * time.Millisecond}, 80*time.Millisecond))
+	assert.Less(t, time.Since(start), 60*time.Millisecond)
+	assert.Nil(t, sleepTimer(context.Background(), &PacingTimer{Pacing: 100 * time.Millisecond}, time.Second))
+
+	start = time.Now()
+	assert.Nil(t, sleepTimer(context.Background(), &ConstantTimer{Wait: 30 * time.Millisecond}, 0))
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+
+	assert.Nil(t, sleepTimer(context.Background(), &ExponentialRandomTimer{Mean: time.Hour, Max: time.Millisecond}, 0))
+	assert.Nil(t, sleepTimer(context.Background(), &GaussianRandomTimer{MeanDuration: -time.Second}, 0))
+	assert.Nil(t, sleepTimer(context.Background(), NonstopTimer{}, 0))
+}
+
+type customTimer struct {
+	Wait time.Duration `json:"wait"`
+}
+
+func (ct *customTimer) Sleep() {
+	time.Sleep(ct.Wait)
+}
+
+func (ct *customTimer) Name() string {
+	return "custom-timer"
+}
+
+func TestRegisterTimer(t *testing.T) {
+	plan := NewPlan("custom")
+	plan.AddStages(BuildStage().WithAttackStrategy(&FixedConcurrentUsers{ConcurrentUsers: 10}).WithTimer(&customTimer{}))
+	assert.ErrorIs(t, plan.validate(), ErrNotRegistered)
+
+	decoder := func(data []byte) (Timer, error) {
+		ct := new(customTimer)
+		err := json.Unmarshal(data, ct)
+		return ct, err
+	}
+	assert.Nil(t, RegisterTimer("custom-timer", decoder))
+	assert.ErrorIs(t, RegisterTimer("custom-timer", decoder), ErrAlreadyRegistered)
+	assert.ErrorIs(t, RegisterTimer("pacing-timer", decoder), ErrAlreadyRegistered)
+	assert.Error(t, RegisterTimer("another-timer", nil))
+	assert.Nil(t, plan.validate())
+
+	dto, err := defaultTimerConverter.convertTimer(&customTimer{Wait: time.Second})
+	assert.Nil(t, err)
+	t2, err := defaultTimerConverter.convertDTO(dto)
+	assert.Nil(t, err)
+	assert.EqualValues(t, &customTimer{Wait: time.Second}, t2)
+
+	_, err = defaultTimerConverter.convertDTO(&genproto.TimerDTO{Type: "unknown-timer"})
+	assert.ErrorIs(t, err, ErrNotRegistered)
+	assert.Error(t, defaultTimerConverter.check(struct{ Timer }{}))
+}
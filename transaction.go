@@ -0,0 +1,100 @@
+package ultron
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/wosai/ultron/v2/pkg/statistics"
+)
+
+type (
+	// TransactionAttacker 在一次迭代中按顺序执行多个步骤，步骤之间通过executorSharedContext共享存储，任一步骤失败则中止
+	// 每个步骤以自身的名称单独统计，整个流程以TransactionAttacker的名称作为一个事务统计
+	TransactionAttacker struct {
+		name  string
+		steps []Attacker
+	}
+
+	// resultEmitter 由executor提供，用于在一次Fire中上报额外的执行结果
+	resultEmitter func(statistics.AttackResult)
+
+	resultEmitterKey struct{}
+)
+
+var (
+	_ Attacker        = (*TransactionAttacker)(nil)
+	_ VirtualUserHook = (*TransactionAttacker)(nil)
+)
+
+func NewTransactionAttacker(name string, steps ...Attacker) *TransactionAttacker {
+	for _, step := range steps {
+		if step == nil {
+			panic("invalid step")
+		}
+	}
+	return &TransactionAttacker{name: name, steps: steps}
+}
+
+// Then 追加步骤
+func (ta *TransactionAttacker) Then(steps ...Attacker) *TransactionAttacker {
+	for _, step := range steps {
+		if step == nil {
+			panic("invalid step")
+		}
+	}
+	ta.steps = append(ta.steps, steps...)
+	return ta
+}
+
+func (ta *TransactionAttacker) Name() string {
+	return ta.name
+}
+
+// OnStart 依次调用实现了VirtualUserHook的步骤，任意步骤返回error时，对已经启动成功的步骤调用OnStop
+func (ta *TransactionAttacker) OnStart(ctx context.Context) error {
+	return startHooks(ctx, collectHooks(nil, ta.steps))
+}
+
+// OnStop 逆序调用实现了VirtualUserHook的步骤
+func (ta *TransactionAttacker) OnStop(ctx context.Context) {
+	stopHooks(ctx, collectHooks(nil, ta.steps))
+}
+
+func (ta *TransactionAttacker) Fire(ctx context.Context) error {
+	if len(ta.steps) == 0 {
+		panic("call Then() to add steps first")
+	}
+
+	ctx = AllocateStorageInContext(ctx)
+	pinStorageInContext(ctx)
+	defer func() {
+		unpinStorageInContext(ctx)
+		ClearStorageInContext(ctx)
+	}()
+
+	for _, step := range ta.steps {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		start := time.Now()
+		err := step.Fire(ctx)
+		emitResult(ctx, statistics.AttackResult{Name: takeResultName(ctx, step.Name()), Duration: time.Since(start), Error: err, Diagnostic: DiagnosticOf(err), Trace: takeResultTrace(ctx)})
+		if err != nil {
+			return fmt.Errorf("step %s: %w", step.Name(), err)
+		}
+	}
+	return nil
+}
+
+func withResultEmitter(ctx context.Context, emit resultEmitter) context.Context {
+	return context.WithValue(ctx, resultEmitterKey{}, emit)
+}
+
+// emitResult 上报额外的执行结果，不在executor中执行时忽略
+func emitResult(ctx context.Context, ret statistics.AttackResult) {
+	if emit, ok := ctx.Value(resultEmitterKey{}).(resultEmitter); ok {
+		emit(ret)
+	}
+}
@@ -0,0 +1,128 @@
+package ultron
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wosai/ultron/v2/pkg/statistics"
+)
+
+type stepAttacker struct {
+	name string
+	fire func(context.Context) error
+}
+
+func (s *stepAttacker) Name() string {
+	return s.name
+}
+
+func (s *stepAttacker) Fire(ctx context.Context) error {
+	ctx = AllocateStorageInContext(ctx) // 模拟HTTPAttacker等内置Attacker的行为
+	defer ClearStorageInContext(ctx)
+	return s.fire(ctx)
+}
+
+func TestTransactionAttacker_Fire(t *testing.T) {
+	var results []statistics.AttackResult
+	ctx := withResultEmitter(context.Background(), func(ret statistics.AttackResult) {
+		results = append(results, ret)
+	})
+	ctx = newExecutorSharedContext(ctx)
+
+	var token interface{}
+	flow := NewTransactionAttacker("checkout",
+		&stepAttacker{name: "login", fire: func(ctx context.Context) error {
+			StoreInContext(ctx, "token", "abc")
+			return nil
+		}},
+	).Then(&stepAttacker{name: "add-to-cart", fire: func(ctx context.Context) error {
+		token, _ = FromContext(ctx, "token")
+		return nil
+	}})
+
+	assert.Nil(t, flow.Fire(ctx))
+	assert.EqualValues(t, token, "abc")
+	assert.EqualValues(t, len(results), 2)
+	assert.EqualValues(t, results[0].Name, "login")
+	assert.EqualValues(t, results[1].Name, "add-to-cart")
+
+	// 流程结束后释放存储空间
+	es := ctx.(*executorSharedContext)
+	assert.EqualValues(t, es.pinned, 0)
+	assert.Empty(t, es.resources)
+}
+
+func TestTransactionAttacker_FailedStep(t *testing.T) {
+	var results []statistics.AttackResult
+	ctx := newExecutorSharedContext(withResultEmitter(context.Background(), func(ret statistics.AttackResult) {
+		results = append(results, ret)
+	}))
+
+	failure := errors.New("bad status code: 500")
+	executed := false
+	flow := NewTransactionAttacker("checkout",
+		&stepAttacker{name: "login", fire: func(context.Context) error { return failure }},
+		&stepAttacker{name: "checkout", fire: func(context.Context) error {
+			executed = true
+			return nil
+		}},
+	)
+
+	err := flow.Fire(ctx)
+	assert.ErrorIs(t, err, failure)
+	assert.EqualError(t, err, "step login: bad status code: 500")
+	assert.False(t, executed)
+	assert.EqualValues(t, len(results), 1)
+	assert.ErrorIs(t, results[0].Error, failure)
+
+	// 不在executor中执行时，不会上报步骤的结果
+	assert.ErrorIs(t, flow.Fire(context.Background()), failure)
+}
+
+func TestTransactionAttacker_InExecutor(t *testing.T) {
+	commander := newFixedConcurrentUsersStrategyCommander()
+	task := NewTask()
+	task.Add(NewTransactionAttacker("flow", newBenchmarkAttacker("step-1", time.Millisecond), newBenchmarkAttacker("step-2", time.Millisecond)), 1)
+	sg := statistics.NewStatisticianGroup()
+	output := commander.Open(context.Background(), task)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for result := range output {
+			sg.Record(result)
+		}
+	}()
+
+	commander.Command(&FixedConcurrentUsers{ConcurrentUsers: 2}, NonstopTimer{})
+	<-time.After(time.Second)
+	commander.Close()
+	wg.Wait()
+
+	report := sg.Report(true)
+	assert.Contains(t, report.Reports, "flow")
+	assert.Contains(t, report.Reports, "step-1")
+	assert.Contains(t, report.Reports, "step-2")
+	assert.GreaterOrEqual(t, report.Reports["step-1"].Requests, report.Reports["flow"].Requests)
+}
+
+func TestTransactionAttacker_Hooks(t *testing.T) {
+	var calls []string
+	flow := NewTransactionAttacker("flow", &hookedAttacker{name: "a", calls: &calls}, &stepAttacker{name: "b"}, &hookedAttacker{name: "c", calls: &calls})
+	task := NewTask()
+	task.Add(flow, 1)
+
+	assert.NoError(t, task.OnStart(context.Background()))
+	task.OnStop(context.Background())
+	assert.EqualValues(t, []string{"start a", "start c", "stop c", "stop a"}, calls)
+
+	calls = nil
+	flow.Then(&hookedAttacker{name: "d", calls: &calls, err: errors.New("login failed")})
+	assert.Error(t, flow.OnStart(context.Background()))
+	assert.EqualValues(t, []string{"start a", "start c", "start d", "stop c", "stop a"}, calls)
+}